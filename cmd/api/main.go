@@ -2,12 +2,22 @@ package main
 
 import (
 	"kasir-api/internal/bootstrap"
+	"os"
+	_ "time/tzdata" // database zona waktu ikut di-embed, untuk STORE_TIMEZONE di container minimal
 )
 
 func main() {
+	// subcommand migrate: api migrate up|down|status
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		bootstrap.RunMigrate(os.Args[2:])
+		return
+	}
+
 	// initialize bootstrap
 	app := bootstrap.InitApp()
 
-	// run server
-	app.Run()
+	// run server, exit non-zero jika server gagal (error sudah dicatat di log)
+	if err := app.Run(); err != nil {
+		os.Exit(1)
+	}
 }
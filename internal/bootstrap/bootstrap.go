@@ -1,9 +1,13 @@
 package bootstrap
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"kasir-api/internal/config"
 	"kasir-api/internal/database"
+	"kasir-api/internal/domain/pricing"
+	"kasir-api/internal/domain/receipt"
 	"kasir-api/internal/domain/repositories"
 	"kasir-api/internal/domain/usecases"
 	"kasir-api/internal/http/handlers"
@@ -11,13 +15,17 @@ import (
 	"kasir-api/internal/pkg"
 	"kasir-api/internal/routes"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
 
 type Bootstrap struct {
 	DB     *sql.DB
-	Port   string
+	Config *config.Config
 	Router http.Handler
 }
 
@@ -27,6 +35,10 @@ func InitApp() *Bootstrap {
 
 	cfg := config.LoadConfig()
 
+	if cfg.JWTSecret == "" {
+		pkg.Log.Fatal("JWT_SECRET is required")
+	}
+
 	db, err := database.InitDB(cfg.DBConn)
 	if err != nil {
 		pkg.Log.WithFields(logrus.Fields{
@@ -34,38 +46,177 @@ func InitApp() *Bootstrap {
 		}).Fatal("Failed to connect to database")
 	}
 
-	deps := initDependencies(db)
+	pkg.RegisterDBMetrics(db)
+
+	if cfg.AutoMigrate {
+		applied, err := database.MigrateUp(db)
+		if err != nil {
+			pkg.Log.WithFields(logrus.Fields{
+				"error": err.Error(),
+			}).Fatal("Failed to run database migrations")
+		}
+		pkg.Log.WithFields(logrus.Fields{
+			"applied": applied,
+		}).Info("Database migrations are up to date")
+	}
+
+	deps := initDependencies(db, cfg)
 	router := routes.RegisterAll(deps)
 
 	return &Bootstrap{
 		DB:     db,
-		Port:   cfg.Port,
+		Config: cfg,
 		Router: router,
 	}
 }
 
-func (a *Bootstrap) Run() {
-	addr := "0.0.0.0:" + a.Port
+// Run menjalankan HTTP server sampai menerima SIGINT/SIGTERM, lalu menunggu
+// request yang sedang berjalan selesai sebelum menutup koneksi database.
+// Jika server gagal (mis. port sudah dipakai) koneksi database tetap ditutup
+// dan error-nya dikembalikan ke pemanggil.
+func (a *Bootstrap) Run() error {
+	addr := "0.0.0.0:" + a.Config.Port
+
+	srv := &http.Server{
+		Addr:         addr,
+		Handler:      middleware.RequestID(middleware.Logging(middleware.Timeout(a.Config.QueryTimeout)(middleware.Metrics(a.Router)))),
+		ReadTimeout:  a.Config.ReadTimeout,
+		WriteTimeout: a.Config.WriteTimeout,
+		IdleTimeout:  a.Config.IdleTimeout,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		pkg.Log.WithFields(logrus.Fields{
+			"address": addr,
+		}).Info("HTTP server running")
+
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
+	var runErr error
+	select {
+	case runErr = <-serverErr:
+		pkg.Log.WithFields(logrus.Fields{
+			"error": runErr.Error(),
+		}).Error("HTTP server failed")
+	case <-ctx.Done():
+		a.shutdown(srv)
+	}
+
+	if err := a.DB.Close(); err != nil {
+		pkg.Log.WithFields(logrus.Fields{
+			"error": err.Error(),
+		}).Error("Failed to close database connection")
+	} else {
+		pkg.Log.Info("Database connection closed")
+	}
+
+	pkg.Log.Info("Server stopped")
+	return runErr
+}
+
+// shutdown menunggu request yang sedang berjalan selesai selama
+// ShutdownTimeout, lalu memutus sisanya
+func (a *Bootstrap) shutdown(srv *http.Server) {
 	pkg.Log.WithFields(logrus.Fields{
-		"address": addr,
-	}).Info("HTTP server running")
+		"grace_period": a.Config.ShutdownTimeout.String(),
+	}).Info("Shutdown signal received, draining in-flight requests")
 
-	if err := http.ListenAndServe(addr, middleware.Logging(a.Router)); err != nil {
-		pkg.Log.Fatal(err)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), a.Config.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		pkg.Log.WithFields(logrus.Fields{
+			"error": err.Error(),
+		}).Error("Graceful shutdown timed out, forcing close")
+		srv.Close()
 	}
 }
 
-func initDependencies(db *sql.DB) *routes.RouteConfig {
+func initDependencies(db *sql.DB, cfg *config.Config) *routes.RouteConfig {
 	productRepo := repositories.NewProductRepository(db)
 	productUseCase := usecases.NewProductUseCase(productRepo)
 	categoryRepo := repositories.NewCategoryRepository(db)
 	categoryUseCase := usecases.NewCategoryUseCase(categoryRepo)
-	healthUseCase := usecases.NewHealthUseCase("Kasir API", "1.0.0")
+	promotionRepo := repositories.NewPromotionRepository(db)
+	promotionUseCase := usecases.NewPromotionUseCase(promotionRepo)
+	storeLocation, err := time.LoadLocation(cfg.StoreTimezone)
+	if err != nil {
+		pkg.Log.WithFields(logrus.Fields{
+			"timezone": cfg.StoreTimezone,
+			"error":    err.Error(),
+		}).Fatal("Invalid store timezone")
+	}
+	if cfg.TaxRateBps < 0 || cfg.TaxRateBps > pricing.BasisPoints || cfg.ServiceChargeBps < 0 || cfg.ServiceChargeBps > pricing.BasisPoints {
+		pkg.Log.WithFields(logrus.Fields{
+			"tax_rate_bps":       cfg.TaxRateBps,
+			"service_charge_bps": cfg.ServiceChargeBps,
+		}).Fatal("Tax and service charge rates must be between 0 and 10000 basis points")
+	}
+	taxSettings := pricing.TaxSettings{
+		DefaultRateBps:   cfg.TaxRateBps,
+		ServiceChargeBps: cfg.ServiceChargeBps,
+		PricesIncludeTax: cfg.PricesIncludeTax,
+	}
+	orderRepo := repositories.NewOrderRepository(db)
+	transactionRepo := repositories.NewTransactionRepository(db)
+	transactionUseCase := usecases.NewTransactionUseCase(transactionRepo, productRepo, promotionRepo, orderRepo, storeLocation, taxSettings)
+	refundRepo := repositories.NewRefundRepository(db)
+	refundUseCase := usecases.NewRefundUseCase(refundRepo)
+	stockRepo := repositories.NewStockRepository(db)
+	stockUseCase := usecases.NewStockUseCase(stockRepo, productRepo)
+	shiftRepo := repositories.NewShiftRepository(db)
+	shiftUseCase := usecases.NewShiftUseCase(shiftRepo)
+	userRepo := repositories.NewUserRepository(db)
+	authUseCase := usecases.NewAuthUseCase(userRepo, cfg.JWTSecret, cfg.AccessTokenTTL, cfg.RefreshTokenTTL)
+	orderUseCase := usecases.NewOrderUseCase(orderRepo, productRepo, authUseCase)
+	receiptStore := receipt.Store{
+		Name:    cfg.StoreName,
+		Address: cfg.StoreAddress,
+		Phone:   cfg.StorePhone,
+		Footer:  cfg.ReceiptFooter,
+	}
+	receiptUseCase := usecases.NewReceiptUseCase(transactionRepo, userRepo, receiptStore, storeLocation)
+	reportRepo := repositories.NewReportRepository(db)
+	reportUseCase := usecases.NewReportUseCase(reportRepo, storeLocation)
+	supplierRepo := repositories.NewSupplierRepository(db)
+	supplierUseCase := usecases.NewSupplierUseCase(supplierRepo)
+	purchaseOrderRepo := repositories.NewPurchaseOrderRepository(db)
+	purchaseOrderUseCase := usecases.NewPurchaseOrderUseCase(purchaseOrderRepo, supplierRepo, productRepo)
+	stockOpnameRepo := repositories.NewStockOpnameRepository(db)
+	stockOpnameUseCase := usecases.NewStockOpnameUseCase(stockOpnameRepo, productRepo, categoryRepo, productUseCase)
+	healthRepo := repositories.NewHealthRepository(db)
+	healthUseCase := usecases.NewHealthUseCase("Kasir API", healthRepo, cfg.HealthCheckTimeout)
+
+	// buat admin awal jika belum ada user sama sekali
+	if err := authUseCase.EnsureAdmin(context.Background(), cfg.AdminUsername, cfg.AdminPassword); err != nil {
+		pkg.Log.WithFields(logrus.Fields{
+			"error": err.Error(),
+		}).Fatal("Failed to create initial admin user")
+	}
 
 	return &routes.RouteConfig{
-		ProductHandler:  handlers.NewProductHandler(productUseCase),
-		CategoryHandler: handlers.NewCategoryHandler(categoryUseCase),
-		HealthHandler:   handlers.NewHealthHandler(healthUseCase),
+		ProductHandler:       handlers.NewProductHandler(productUseCase),
+		CategoryHandler:      handlers.NewCategoryHandler(categoryUseCase),
+		HealthHandler:        handlers.NewHealthHandler(healthUseCase),
+		TransactionHandler:   handlers.NewTransactionHandler(transactionUseCase),
+		AuthHandler:          handlers.NewAuthHandler(authUseCase),
+		StockHandler:         handlers.NewStockHandler(stockUseCase),
+		ShiftHandler:         handlers.NewShiftHandler(shiftUseCase),
+		PromotionHandler:     handlers.NewPromotionHandler(promotionUseCase),
+		RefundHandler:        handlers.NewRefundHandler(refundUseCase),
+		OrderHandler:         handlers.NewOrderHandler(orderUseCase),
+		ReceiptHandler:       handlers.NewReceiptHandler(receiptUseCase),
+		ReportHandler:        handlers.NewReportHandler(reportUseCase),
+		SupplierHandler:      handlers.NewSupplierHandler(supplierUseCase),
+		PurchaseOrderHandler: handlers.NewPurchaseOrderHandler(purchaseOrderUseCase),
+		StockOpnameHandler:   handlers.NewStockOpnameHandler(stockOpnameUseCase),
+		JWTSecret:            cfg.JWTSecret,
 	}
 }
@@ -0,0 +1,88 @@
+package bootstrap
+
+import (
+	"fmt"
+	"kasir-api/internal/config"
+	"kasir-api/internal/database"
+	"kasir-api/internal/pkg"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+)
+
+const migrateUsage = "usage: api migrate up|down [steps]|status"
+
+// RunMigrate menjalankan subcommand migrate dari CLI
+func RunMigrate(args []string) {
+	pkg.InitLogger()
+
+	if len(args) == 0 {
+		pkg.Log.Fatal(migrateUsage)
+	}
+
+	cfg := config.LoadConfig()
+
+	db, err := database.InitDB(cfg.DBConn)
+	if err != nil {
+		pkg.Log.WithFields(logrus.Fields{
+			"error": err.Error(),
+		}).Fatal("Failed to connect to database")
+	}
+	defer db.Close()
+
+	switch args[0] {
+	case "up":
+		applied, err := database.MigrateUp(db)
+		if err != nil {
+			pkg.Log.WithFields(logrus.Fields{
+				"error": err.Error(),
+			}).Fatal("Failed to run migrations")
+		}
+		pkg.Log.WithFields(logrus.Fields{
+			"applied": applied,
+		}).Info("Migrations completed")
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				pkg.Log.Fatal(migrateUsage)
+			}
+		}
+		reverted, err := database.MigrateDown(db, steps)
+		if err != nil {
+			pkg.Log.WithFields(logrus.Fields{
+				"error": err.Error(),
+			}).Fatal("Failed to rollback migrations")
+		}
+		pkg.Log.WithFields(logrus.Fields{
+			"reverted": reverted,
+		}).Info("Rollback completed")
+
+	case "status":
+		statuses, err := database.MigrationStatuses(db)
+		if err != nil {
+			pkg.Log.WithFields(logrus.Fields{
+				"error": err.Error(),
+			}).Fatal("Failed to read migration status")
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, s := range statuses {
+			status, appliedAt := "pending", "-"
+			if s.Applied {
+				status = "applied"
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", s.Version, s.Name, status, appliedAt)
+		}
+		tw.Flush()
+
+	default:
+		pkg.Log.Fatal(migrateUsage)
+	}
+}
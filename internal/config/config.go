@@ -4,19 +4,68 @@ import (
 	"kasir-api/internal/pkg"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Port   string
-	DBConn string
+	Port         string
+	DBConn       string
+	AutoMigrate  bool
+	QueryTimeout time.Duration
+
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	IdleTimeout     time.Duration
+	ShutdownTimeout time.Duration
+
+	HealthCheckTimeout time.Duration
+
+	JWTSecret       string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	AdminUsername   string
+	AdminPassword   string
+
+	// StoreTimezone dipakai untuk jam berlaku promo (happy hour)
+	StoreTimezone string
+
+	// tarif dalam basis poin (1100 = 11%)
+	TaxRateBps       int
+	ServiceChargeBps int
+	PricesIncludeTax bool
+
+	// identitas toko yang dicetak di struk
+	StoreName     string
+	StoreAddress  string
+	StorePhone    string
+	ReceiptFooter string
 }
 
+// Nilai default setting durasi, juga dipakai jika nilai yang diset tidak valid
+const (
+	defaultQueryTimeout       = 5 * time.Second
+	defaultReadTimeout        = 15 * time.Second
+	defaultWriteTimeout       = 30 * time.Second
+	defaultIdleTimeout        = 60 * time.Second
+	defaultShutdownTimeout    = 20 * time.Second
+	defaultHealthCheckTimeout = 2 * time.Second
+	defaultAccessTokenTTL     = 15 * time.Minute
+	defaultRefreshTokenTTL    = 168 * time.Hour
+)
+
 func LoadConfig() *Config {
 	viper.AutomaticEnv()
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	viper.SetDefault("AUTO_MIGRATE", true)
+	viper.SetDefault("STORE_TIMEZONE", "Asia/Jakarta")
+	viper.SetDefault("TAX_RATE_BPS", 0)
+	viper.SetDefault("SERVICE_CHARGE_BPS", 0)
+	viper.SetDefault("PRICES_INCLUDE_TAX", false)
+	viper.SetDefault("STORE_NAME", "Kasir")
+	viper.SetDefault("RECEIPT_FOOTER", "Terima kasih")
 
 	if _, err := os.Stat(".env"); err == nil {
 		viper.SetConfigFile(".env")
@@ -33,9 +82,57 @@ func LoadConfig() *Config {
 	}
 
 	config := &Config{
-		Port:   port,
-		DBConn: viper.GetString("DB_CONN"),
+		Port:         port,
+		DBConn:       viper.GetString("DB_CONN"),
+		AutoMigrate:  viper.GetBool("AUTO_MIGRATE"),
+		QueryTimeout: durationSetting("QUERY_TIMEOUT", defaultQueryTimeout, true),
+
+		ReadTimeout:     durationSetting("SERVER_READ_TIMEOUT", defaultReadTimeout, false),
+		WriteTimeout:    durationSetting("SERVER_WRITE_TIMEOUT", defaultWriteTimeout, false),
+		IdleTimeout:     durationSetting("SERVER_IDLE_TIMEOUT", defaultIdleTimeout, false),
+		ShutdownTimeout: durationSetting("SHUTDOWN_TIMEOUT", defaultShutdownTimeout, false),
+
+		HealthCheckTimeout: durationSetting("HEALTH_CHECK_TIMEOUT", defaultHealthCheckTimeout, false),
+
+		JWTSecret:       viper.GetString("JWT_SECRET"),
+		AccessTokenTTL:  durationSetting("ACCESS_TOKEN_TTL", defaultAccessTokenTTL, false),
+		RefreshTokenTTL: durationSetting("REFRESH_TOKEN_TTL", defaultRefreshTokenTTL, false),
+		AdminUsername:   viper.GetString("ADMIN_USERNAME"),
+		AdminPassword:   viper.GetString("ADMIN_PASSWORD"),
+
+		StoreTimezone: viper.GetString("STORE_TIMEZONE"),
+
+		TaxRateBps:       viper.GetInt("TAX_RATE_BPS"),
+		ServiceChargeBps: viper.GetInt("SERVICE_CHARGE_BPS"),
+		PricesIncludeTax: viper.GetBool("PRICES_INCLUDE_TAX"),
+
+		StoreName:     viper.GetString("STORE_NAME"),
+		StoreAddress:  viper.GetString("STORE_ADDRESS"),
+		StorePhone:    viper.GetString("STORE_PHONE"),
+		ReceiptFooter: viper.GetString("RECEIPT_FOOTER"),
 	}
 
 	return config
 }
+
+// durationSetting membaca setting durasi (mis. "5s"). Nilai kosong memakai
+// fallback; nilai yang tidak bisa di-parse, negatif, atau nol jika allowZero
+// false juga memakai fallback dengan warning. viper.GetDuration sendiri
+// mengembalikan 0 untuk nilai yang salah, yang untuk timeout berarti context
+// langsung kedaluwarsa atau server tanpa batas waktu.
+func durationSetting(key string, fallback time.Duration, allowZero bool) time.Duration {
+	value := strings.TrimSpace(viper.GetString(key))
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err == nil && (d > 0 || (d == 0 && allowZero)) {
+		return d
+	}
+	pkg.Log.WithFields(logrus.Fields{
+		"key":     key,
+		"value":   value,
+		"default": fallback.String(),
+	}).Warn("Invalid duration setting, using default")
+	return fallback
+}
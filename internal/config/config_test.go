@@ -0,0 +1,43 @@
+package config
+
+import (
+	"io"
+	"kasir-api/internal/pkg"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestDurationSetting(t *testing.T) {
+	pkg.InitLogger()
+	pkg.Log.SetOutput(io.Discard)
+
+	tests := []struct {
+		name      string
+		value     string
+		allowZero bool
+		want      time.Duration
+	}{
+		{name: "unset uses fallback", value: "", want: 5 * time.Second},
+		{name: "valid value", value: "1m30s", want: 90 * time.Second},
+		{name: "surrounding spaces", value: " 250ms ", want: 250 * time.Millisecond},
+		{name: "unparseable uses fallback", value: "5 detik", want: 5 * time.Second},
+		{name: "bare number uses fallback", value: "30", want: 5 * time.Second},
+		{name: "negative uses fallback", value: "-1s", want: 5 * time.Second},
+		{name: "zero uses fallback", value: "0s", want: 5 * time.Second},
+		{name: "zero allowed", value: "0", allowZero: true, want: 0},
+		{name: "negative uses fallback even if zero is allowed", value: "-1s", allowZero: true, want: 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Set("TEST_DURATION", tt.value)
+			t.Cleanup(func() { viper.Set("TEST_DURATION", nil) })
+
+			if got := durationSetting("TEST_DURATION", 5*time.Second, tt.allowZero); got != tt.want {
+				t.Errorf("durationSetting(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
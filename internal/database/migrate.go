@@ -0,0 +1,292 @@
+package database
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"kasir-api/internal/pkg"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockID adalah key pg_advisory_xact_lock supaya dua instance
+// tidak menjalankan migration yang sama secara bersamaan
+const migrationLockID = 7_246_104
+
+// Migration adalah satu versi schema beserta script up dan down
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus adalah status sebuah migration di database
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// LoadMigrations membaca semua file migration yang di-embed ke binary.
+// Format nama file: <version>_<name>.up.sql dan <version>_<name>.down.sql
+func LoadMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		fileName := entry.Name()
+
+		var direction string
+		switch {
+		case strings.HasSuffix(fileName, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(fileName, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		base := strings.TrimSuffix(fileName, "."+direction+".sql")
+		versionStr, name, found := strings.Cut(base, "_")
+		if !found {
+			return nil, fmt.Errorf("invalid migration file name: %s", fileName)
+		}
+		version, err := strconv.Atoi(versionStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", fileName, err)
+		}
+
+		content, err := migrationFiles.ReadFile("migrations/" + fileName)
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if direction == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func ensureMigrationTable(db *sql.DB) error {
+	query := `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		name       VARCHAR(255) NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`
+	_, err := db.Exec(query)
+	return err
+}
+
+// MigrateUp menjalankan semua migration yang belum diterapkan.
+// Setiap migration berjalan di transaction sendiri.
+func MigrateUp(db *sql.DB) (int, error) {
+	if err := ensureMigrationTable(db); err != nil {
+		return 0, err
+	}
+
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, m := range migrations {
+		ok, err := applyMigration(db, m)
+		if err != nil {
+			return applied, fmt.Errorf("migration %d_%s failed: %w", m.Version, m.Name, err)
+		}
+		if ok {
+			applied++
+			pkg.Log.WithFields(logrus.Fields{
+				"version": m.Version,
+				"name":    m.Name,
+			}).Info("Migration applied")
+		}
+	}
+
+	return applied, nil
+}
+
+func applyMigration(db *sql.DB, m Migration) (bool, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("SELECT pg_advisory_xact_lock($1)", migrationLockID); err != nil {
+		return false, err
+	}
+
+	var exists bool
+	err = tx.QueryRow("SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)", m.Version).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(m.Up); err != nil {
+		return false, err
+	}
+	if _, err := tx.Exec("INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", m.Version, m.Name); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// MigrateDown me-rollback migration terakhir sebanyak steps versi
+func MigrateDown(db *sql.DB, steps int) (int, error) {
+	if err := ensureMigrationTable(db); err != nil {
+		return 0, err
+	}
+
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return 0, err
+	}
+	byVersion := make(map[int]Migration, len(migrations))
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+	}
+
+	reverted := 0
+	for reverted < steps {
+		tx, err := db.Begin()
+		if err != nil {
+			return reverted, err
+		}
+
+		if _, err := tx.Exec("SELECT pg_advisory_xact_lock($1)", migrationLockID); err != nil {
+			tx.Rollback()
+			return reverted, err
+		}
+
+		var version int
+		err = tx.QueryRow("SELECT version FROM schema_migrations ORDER BY version DESC LIMIT 1").Scan(&version)
+		if err == sql.ErrNoRows {
+			tx.Rollback()
+			break
+		}
+		if err != nil {
+			tx.Rollback()
+			return reverted, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok || m.Down == "" {
+			tx.Rollback()
+			return reverted, fmt.Errorf("migration %d has no down script", version)
+		}
+
+		if _, err := tx.Exec(m.Down); err != nil {
+			tx.Rollback()
+			return reverted, fmt.Errorf("migration %d_%s rollback failed: %w", m.Version, m.Name, err)
+		}
+		if _, err := tx.Exec("DELETE FROM schema_migrations WHERE version = $1", version); err != nil {
+			tx.Rollback()
+			return reverted, err
+		}
+		if err := tx.Commit(); err != nil {
+			return reverted, err
+		}
+
+		reverted++
+		pkg.Log.WithFields(logrus.Fields{
+			"version": m.Version,
+			"name":    m.Name,
+		}).Info("Migration reverted")
+	}
+
+	return reverted, nil
+}
+
+// MigrationStatuses mengembalikan status semua migration yang di-embed
+func MigrationStatuses(db *sql.DB) ([]MigrationStatus, error) {
+	if err := ensureMigrationTable(db); err != nil {
+		return nil, err
+	}
+
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	appliedAt := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		appliedAt[version] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		status := MigrationStatus{Version: m.Version, Name: m.Name}
+		if at, ok := appliedAt[m.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &at
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// LatestMigrationVersion mengembalikan versi migration tertinggi yang di-embed
+func LatestMigrationVersion() (int, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return 0, err
+	}
+	if len(migrations) == 0 {
+		return 0, nil
+	}
+	return migrations[len(migrations)-1].Version, nil
+}
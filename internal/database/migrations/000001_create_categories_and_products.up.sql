@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS categories (
+    id          SERIAL PRIMARY KEY,
+    name        VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS products (
+    id          SERIAL PRIMARY KEY,
+    name        VARCHAR(255) NOT NULL,
+    price       INTEGER NOT NULL DEFAULT 0 CHECK (price >= 0),
+    stock       INTEGER NOT NULL DEFAULT 0 CHECK (stock >= 0),
+    category_id INTEGER NOT NULL REFERENCES categories (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_products_category_id ON products (category_id);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS transactions (
+    id           SERIAL PRIMARY KEY,
+    total_amount INTEGER NOT NULL DEFAULT 0,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS transaction_items (
+    id             SERIAL PRIMARY KEY,
+    transaction_id INTEGER NOT NULL REFERENCES transactions (id) ON DELETE CASCADE,
+    product_id     INTEGER NOT NULL REFERENCES products (id),
+    quantity       INTEGER NOT NULL CHECK (quantity > 0),
+    price          INTEGER NOT NULL,
+    subtotal       INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_items_transaction_id ON transaction_items (transaction_id);
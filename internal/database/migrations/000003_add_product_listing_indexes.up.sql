@@ -0,0 +1,4 @@
+-- index untuk sorting + keyset pagination pada list produk
+CREATE INDEX IF NOT EXISTS idx_products_name_id ON products (name, id);
+CREATE INDEX IF NOT EXISTS idx_products_price_id ON products (price, id);
+CREATE INDEX IF NOT EXISTS idx_products_stock_id ON products (stock, id);
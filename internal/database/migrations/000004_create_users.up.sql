@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS users (
+    id            SERIAL PRIMARY KEY,
+    username      VARCHAR(100) NOT NULL UNIQUE,
+    password_hash VARCHAR(255) NOT NULL,
+    role          VARCHAR(20) NOT NULL CHECK (role IN ('admin', 'cashier')),
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id           SERIAL PRIMARY KEY,
+    product_id   INTEGER NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    delta        INTEGER NOT NULL CHECK (delta <> 0),
+    stock_after  INTEGER NOT NULL CHECK (stock_after >= 0),
+    reason       VARCHAR(20) NOT NULL CHECK (reason IN ('sale', 'restock', 'adjustment', 'return', 'damage')),
+    reference_id VARCHAR(100),
+    note         TEXT NOT NULL DEFAULT '',
+    user_id      INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_movements_product_created ON stock_movements (product_id, created_at DESC, id DESC);
+
+-- saldo awal untuk produk yang sudah ada supaya total ledger sama dengan stok
+INSERT INTO stock_movements (product_id, delta, stock_after, reason, note)
+SELECT id, stock, stock, 'adjustment', 'opening balance'
+FROM products
+WHERE stock > 0;
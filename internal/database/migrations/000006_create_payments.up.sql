@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS payments (
+    id              SERIAL PRIMARY KEY,
+    transaction_id  INTEGER NOT NULL REFERENCES transactions (id) ON DELETE CASCADE,
+    method          VARCHAR(20) NOT NULL CHECK (method IN ('cash', 'debit_card', 'credit_card', 'ewallet', 'qris')),
+    amount          INTEGER NOT NULL CHECK (amount > 0),
+    tendered_amount INTEGER,
+    change_amount   INTEGER NOT NULL DEFAULT 0 CHECK (change_amount >= 0),
+    reference       VARCHAR(100),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    -- hanya pembayaran tunai yang punya uang diterima dan kembalian
+    CONSTRAINT payments_cash_tender CHECK (
+        (method = 'cash' AND tendered_amount >= amount AND change_amount = tendered_amount - amount)
+        OR (method <> 'cash' AND tendered_amount IS NULL AND change_amount = 0)
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_payments_transaction_id ON payments (transaction_id);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_transactions_shift_id;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS shift_id,
+    DROP COLUMN IF EXISTS user_id;
+
+DROP TABLE IF EXISTS shift_cash_events;
+DROP TABLE IF EXISTS shifts;
@@ -0,0 +1,39 @@
+CREATE TABLE IF NOT EXISTS shifts (
+    id            SERIAL PRIMARY KEY,
+    user_id       INTEGER NOT NULL REFERENCES users (id),
+    status        VARCHAR(10) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed')),
+    opening_float INTEGER NOT NULL CHECK (opening_float >= 0),
+    expected_cash INTEGER,
+    counted_cash  INTEGER CHECK (counted_cash >= 0),
+    variance      INTEGER,
+    opening_note  TEXT NOT NULL DEFAULT '',
+    closing_note  TEXT NOT NULL DEFAULT '',
+    opened_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    closed_at     TIMESTAMPTZ,
+    CONSTRAINT shifts_closed_fields CHECK (
+        (status = 'open' AND closed_at IS NULL AND counted_cash IS NULL)
+        OR (status = 'closed' AND closed_at IS NOT NULL AND counted_cash IS NOT NULL AND expected_cash IS NOT NULL)
+    )
+);
+
+-- satu kasir hanya boleh punya satu shift yang masih terbuka
+CREATE UNIQUE INDEX IF NOT EXISTS idx_shifts_one_open_per_user ON shifts (user_id) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_shifts_opened_at ON shifts (opened_at DESC);
+
+CREATE TABLE IF NOT EXISTS shift_cash_events (
+    id         SERIAL PRIMARY KEY,
+    shift_id   INTEGER NOT NULL REFERENCES shifts (id) ON DELETE CASCADE,
+    type       VARCHAR(10) NOT NULL CHECK (type IN ('cash_in', 'cash_out')),
+    amount     INTEGER NOT NULL CHECK (amount > 0),
+    note       TEXT NOT NULL DEFAULT '',
+    user_id    INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_shift_cash_events_shift_id ON shift_cash_events (shift_id);
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS user_id  INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS shift_id INTEGER REFERENCES shifts (id);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_shift_id ON transactions (shift_id);
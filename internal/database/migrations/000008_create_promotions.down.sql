@@ -0,0 +1,9 @@
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS discount_amount,
+    DROP COLUMN IF EXISTS subtotal_amount;
+
+ALTER TABLE transaction_items
+    DROP COLUMN IF EXISTS discount;
+
+DROP TABLE IF EXISTS transaction_promotions;
+DROP TABLE IF EXISTS promotions;
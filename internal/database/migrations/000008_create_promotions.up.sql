@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS promotions (
+    id                SERIAL PRIMARY KEY,
+    name              VARCHAR(255) NOT NULL,
+    type              VARCHAR(20) NOT NULL CHECK (type IN ('percentage', 'fixed', 'buy_x_get_y')),
+    scope             VARCHAR(20) NOT NULL CHECK (scope IN ('item', 'category', 'cart')),
+    product_id        INTEGER REFERENCES products (id) ON DELETE CASCADE,
+    category_id       INTEGER REFERENCES categories (id) ON DELETE CASCADE,
+    value             INTEGER NOT NULL DEFAULT 0 CHECK (value >= 0),
+    buy_quantity      INTEGER NOT NULL DEFAULT 0 CHECK (buy_quantity >= 0),
+    get_quantity      INTEGER NOT NULL DEFAULT 0 CHECK (get_quantity >= 0),
+    min_spend         INTEGER NOT NULL DEFAULT 0 CHECK (min_spend >= 0),
+    starts_at         TIMESTAMPTZ,
+    ends_at           TIMESTAMPTZ,
+    daily_start_time  VARCHAR(5) NOT NULL DEFAULT '',
+    daily_end_time    VARCHAR(5) NOT NULL DEFAULT '',
+    code              VARCHAR(50) NOT NULL DEFAULT '',
+    active            BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT promotions_window CHECK (starts_at IS NULL OR ends_at IS NULL OR ends_at > starts_at)
+);
+
+-- kode promo unik tanpa membedakan huruf besar/kecil
+CREATE UNIQUE INDEX IF NOT EXISTS idx_promotions_code ON promotions (UPPER(code)) WHERE code <> '';
+CREATE INDEX IF NOT EXISTS idx_promotions_active ON promotions (active);
+
+CREATE TABLE IF NOT EXISTS transaction_promotions (
+    id              SERIAL PRIMARY KEY,
+    transaction_id  INTEGER NOT NULL REFERENCES transactions (id) ON DELETE CASCADE,
+    promotion_id    INTEGER REFERENCES promotions (id) ON DELETE SET NULL,
+    -- nama dan kode disalin supaya laporan tetap terbaca walau promo dihapus
+    name            VARCHAR(255) NOT NULL,
+    code            VARCHAR(50) NOT NULL DEFAULT '',
+    discount_amount INTEGER NOT NULL CHECK (discount_amount > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_promotions_transaction_id ON transaction_promotions (transaction_id);
+CREATE INDEX IF NOT EXISTS idx_transaction_promotions_promotion_id ON transaction_promotions (promotion_id);
+
+ALTER TABLE transaction_items
+    ADD COLUMN IF NOT EXISTS discount INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS subtotal_amount INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS discount_amount INTEGER NOT NULL DEFAULT 0;
+
+UPDATE transactions SET subtotal_amount = total_amount WHERE subtotal_amount = 0;
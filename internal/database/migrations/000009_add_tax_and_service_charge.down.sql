@@ -0,0 +1,16 @@
+DROP TABLE IF EXISTS transaction_taxes;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS prices_include_tax,
+    DROP COLUMN IF EXISTS tax_amount,
+    DROP COLUMN IF EXISTS service_charge_amount;
+
+ALTER TABLE transaction_items
+    DROP COLUMN IF EXISTS tax_rate_bps;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS tax_exempt,
+    DROP COLUMN IF EXISTS tax_rate_bps;
+
+ALTER TABLE categories
+    DROP COLUMN IF EXISTS tax_rate_bps;
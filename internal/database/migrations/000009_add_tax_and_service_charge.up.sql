@@ -0,0 +1,25 @@
+-- tarif pajak dalam basis poin (1100 = 11%), NULL berarti ikut tarif di atasnya
+ALTER TABLE categories
+    ADD COLUMN IF NOT EXISTS tax_rate_bps INTEGER CHECK (tax_rate_bps BETWEEN 0 AND 10000);
+
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS tax_rate_bps INTEGER CHECK (tax_rate_bps BETWEEN 0 AND 10000),
+    ADD COLUMN IF NOT EXISTS tax_exempt BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE transaction_items
+    ADD COLUMN IF NOT EXISTS tax_rate_bps INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS service_charge_amount INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tax_amount INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS prices_include_tax BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- rincian pajak per tarif untuk laporan PPN
+CREATE TABLE IF NOT EXISTS transaction_taxes (
+    id              SERIAL PRIMARY KEY,
+    transaction_id  INTEGER NOT NULL REFERENCES transactions (id) ON DELETE CASCADE,
+    rate_bps        INTEGER NOT NULL CHECK (rate_bps > 0),
+    taxable_amount  INTEGER NOT NULL CHECK (taxable_amount >= 0),
+    tax_amount      INTEGER NOT NULL CHECK (tax_amount >= 0),
+    UNIQUE (transaction_id, rate_bps)
+);
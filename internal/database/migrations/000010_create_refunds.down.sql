@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS refund_items;
+DROP TABLE IF EXISTS refunds;
+
+ALTER TABLE transaction_items
+    DROP COLUMN IF EXISTS total_amount;
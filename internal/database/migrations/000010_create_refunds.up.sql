@@ -0,0 +1,38 @@
+-- nilai yang dibayar untuk setiap baris (setelah diskon, termasuk service
+-- charge dan pajak), dasar perhitungan refund
+ALTER TABLE transaction_items
+    ADD COLUMN IF NOT EXISTS total_amount INTEGER NOT NULL DEFAULT 0;
+
+UPDATE transaction_items ti
+SET total_amount = (ti.subtotal::BIGINT * t.total_amount / s.subtotal_sum)::INTEGER
+FROM transactions t,
+     (SELECT transaction_id, SUM(subtotal) AS subtotal_sum FROM transaction_items GROUP BY transaction_id) s
+WHERE t.id = ti.transaction_id AND s.transaction_id = ti.transaction_id AND s.subtotal_sum > 0;
+
+CREATE TABLE IF NOT EXISTS refunds (
+    id             SERIAL PRIMARY KEY,
+    transaction_id INTEGER NOT NULL REFERENCES transactions (id) ON DELETE CASCADE,
+    user_id        INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    shift_id       INTEGER REFERENCES shifts (id),
+    reason         VARCHAR(30) NOT NULL CHECK (reason IN ('customer_request', 'damaged', 'wrong_item', 'expired', 'other')),
+    note           TEXT NOT NULL DEFAULT '',
+    method         VARCHAR(20) NOT NULL CHECK (method IN ('cash', 'debit_card', 'credit_card', 'ewallet', 'qris')),
+    restock        BOOLEAN NOT NULL DEFAULT FALSE,
+    total_amount   INTEGER NOT NULL CHECK (total_amount >= 0),
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_refunds_transaction_id ON refunds (transaction_id);
+CREATE INDEX IF NOT EXISTS idx_refunds_shift_id ON refunds (shift_id);
+
+CREATE TABLE IF NOT EXISTS refund_items (
+    id                  SERIAL PRIMARY KEY,
+    refund_id           INTEGER NOT NULL REFERENCES refunds (id) ON DELETE CASCADE,
+    transaction_item_id INTEGER NOT NULL REFERENCES transaction_items (id) ON DELETE CASCADE,
+    product_id          INTEGER NOT NULL,
+    quantity            INTEGER NOT NULL CHECK (quantity > 0),
+    amount              INTEGER NOT NULL CHECK (amount >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_refund_items_refund_id ON refund_items (refund_id);
+CREATE INDEX IF NOT EXISTS idx_refund_items_transaction_item_id ON refund_items (transaction_item_id);
@@ -0,0 +1,27 @@
+-- order draft/held: keranjang yang disimpan di server, stok baru berkurang saat checkout
+CREATE TABLE IF NOT EXISTS orders (
+    id             SERIAL PRIMARY KEY,
+    user_id        INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    status         VARCHAR(10) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'held', 'voided', 'completed')),
+    note           TEXT NOT NULL DEFAULT '',
+    promo_code     VARCHAR(50) NOT NULL DEFAULT '',
+    transaction_id INTEGER UNIQUE REFERENCES transactions (id),
+    void_reason    TEXT NOT NULL DEFAULT '',
+    voided_by      INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    voided_at      TIMESTAMPTZ,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT orders_status_fields CHECK (
+        (status <> 'completed' OR transaction_id IS NOT NULL)
+        AND (status <> 'voided' OR (voided_at IS NOT NULL AND void_reason <> ''))
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_orders_status ON orders (status, updated_at DESC);
+
+CREATE TABLE IF NOT EXISTS order_items (
+    order_id   INTEGER NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    quantity   INTEGER NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (order_id, product_id)
+);
@@ -0,0 +1,4 @@
+DROP SEQUENCE IF EXISTS internal_barcode_seq;
+DROP TABLE IF EXISTS product_barcodes;
+DROP INDEX IF EXISTS products_sku_key;
+ALTER TABLE products DROP COLUMN IF EXISTS sku;
@@ -0,0 +1,18 @@
+-- SKU internal toko, NULL jika produk tidak punya SKU
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS sku VARCHAR(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS products_sku_key ON products (sku) WHERE sku IS NOT NULL;
+
+-- satu produk bisa punya beberapa barcode (mis. kemasan lama dan baru),
+-- tetapi satu barcode hanya milik satu produk. UPC-A disimpan sebagai EAN-13.
+CREATE TABLE IF NOT EXISTS product_barcodes (
+    code        VARCHAR(13) PRIMARY KEY CHECK (code ~ '^[0-9]{8}$|^[0-9]{13}$'),
+    product_id  INTEGER NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS product_barcodes_product_id_idx ON product_barcodes (product_id);
+
+-- nomor urut barcode internal berprefix 04
+CREATE SEQUENCE IF NOT EXISTS internal_barcode_seq;
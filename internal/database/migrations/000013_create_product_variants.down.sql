@@ -0,0 +1,13 @@
+DELETE FROM order_items WHERE variant_id IS NOT NULL;
+DROP INDEX IF EXISTS order_items_line_key;
+ALTER TABLE order_items DROP COLUMN IF EXISTS variant_id;
+ALTER TABLE order_items ADD PRIMARY KEY (order_id, product_id);
+
+ALTER TABLE refund_items DROP COLUMN IF EXISTS variant_id;
+ALTER TABLE transaction_items DROP COLUMN IF EXISTS variant_id;
+ALTER TABLE stock_movements DROP COLUMN IF EXISTS variant_id;
+DELETE FROM product_barcodes WHERE variant_id IS NOT NULL;
+ALTER TABLE product_barcodes DROP COLUMN IF EXISTS variant_id;
+
+DROP TABLE IF EXISTS product_variants;
+DROP TABLE IF EXISTS product_options;
@@ -0,0 +1,47 @@
+-- sumbu opsi produk, mis. size [S, M, L] x colour [Merah, Biru]
+CREATE TABLE IF NOT EXISTS product_options (
+    id            SERIAL PRIMARY KEY,
+    product_id    INTEGER NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    name          VARCHAR(50) NOT NULL,
+    position      INTEGER NOT NULL,
+    option_values TEXT[] NOT NULL,
+    UNIQUE (product_id, name),
+    UNIQUE (product_id, position)
+);
+
+-- satu varian per kombinasi nilai opsi, urutan option_values mengikuti
+-- position opsi. Varian tidak pernah dihapus supaya riwayat penjualan tetap
+-- utuh, kombinasi yang tidak dipakai lagi dinonaktifkan.
+CREATE TABLE IF NOT EXISTS product_variants (
+    id            SERIAL PRIMARY KEY,
+    product_id    INTEGER NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    option_values TEXT[] NOT NULL,
+    sku           VARCHAR(64),
+    price         INTEGER NOT NULL CHECK (price >= 0),
+    stock         INTEGER NOT NULL DEFAULT 0 CHECK (stock >= 0),
+    active        BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (product_id, option_values)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS product_variants_sku_key ON product_variants (sku) WHERE sku IS NOT NULL;
+
+-- variant_id NULL berarti baris milik produk tanpa varian
+ALTER TABLE product_barcodes
+    ADD COLUMN IF NOT EXISTS variant_id INTEGER REFERENCES product_variants (id) ON DELETE CASCADE;
+
+ALTER TABLE stock_movements
+    ADD COLUMN IF NOT EXISTS variant_id INTEGER REFERENCES product_variants (id) ON DELETE CASCADE;
+
+ALTER TABLE transaction_items
+    ADD COLUMN IF NOT EXISTS variant_id INTEGER REFERENCES product_variants (id);
+
+ALTER TABLE refund_items
+    ADD COLUMN IF NOT EXISTS variant_id INTEGER REFERENCES product_variants (id);
+
+ALTER TABLE order_items
+    ADD COLUMN IF NOT EXISTS variant_id INTEGER REFERENCES product_variants (id) ON DELETE CASCADE;
+
+ALTER TABLE order_items DROP CONSTRAINT IF EXISTS order_items_pkey;
+
+CREATE UNIQUE INDEX IF NOT EXISTS order_items_line_key ON order_items (order_id, product_id, (COALESCE(variant_id, 0)));
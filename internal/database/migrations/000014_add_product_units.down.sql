@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS product_units;
+ALTER TABLE products DROP COLUMN IF EXISTS base_unit;
@@ -0,0 +1,14 @@
+-- satuan dasar produk; products.stock, stok varian dan semua movement selalu
+-- dalam satuan ini
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS base_unit VARCHAR(20) NOT NULL DEFAULT 'pcs';
+
+-- satuan beli/jual tambahan, mis. box = 24 pcs
+CREATE TABLE IF NOT EXISTS product_units (
+    id          SERIAL PRIMARY KEY,
+    product_id  INTEGER NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    name        VARCHAR(20) NOT NULL,
+    factor      INTEGER NOT NULL CHECK (factor > 1)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS product_units_name_key ON product_units (product_id, LOWER(name));
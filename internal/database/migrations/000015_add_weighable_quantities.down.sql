@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS products_plu_key;
+ALTER TABLE products
+    DROP COLUMN IF EXISTS plu,
+    DROP COLUMN IF EXISTS weighable;
+
+-- quantity pecahan dibulatkan ke atas dan delta dibulatkan menjauhi nol
+-- supaya CHECK (quantity > 0) dan CHECK (delta <> 0) tetap terpenuhi
+ALTER TABLE order_items ALTER COLUMN quantity TYPE INTEGER USING CEIL(quantity);
+ALTER TABLE refund_items ALTER COLUMN quantity TYPE INTEGER USING CEIL(quantity);
+ALTER TABLE transaction_items ALTER COLUMN quantity TYPE INTEGER USING CEIL(quantity);
+ALTER TABLE stock_movements ALTER COLUMN stock_after TYPE INTEGER USING FLOOR(stock_after);
+ALTER TABLE stock_movements ALTER COLUMN delta TYPE INTEGER USING CASE WHEN delta > 0 THEN CEIL(delta) ELSE FLOOR(delta) END;
+ALTER TABLE product_variants ALTER COLUMN stock TYPE INTEGER USING FLOOR(stock);
+ALTER TABLE products ALTER COLUMN stock TYPE INTEGER USING FLOOR(stock);
@@ -0,0 +1,17 @@
+-- quantity dan stok memakai desimal tetap 3 angka (gram untuk kg, mm untuk m)
+-- supaya barang timbang bisa dijual pecahan tanpa pembulatan float
+ALTER TABLE products ALTER COLUMN stock TYPE NUMERIC(14, 3);
+ALTER TABLE product_variants ALTER COLUMN stock TYPE NUMERIC(14, 3);
+ALTER TABLE stock_movements ALTER COLUMN delta TYPE NUMERIC(14, 3);
+ALTER TABLE stock_movements ALTER COLUMN stock_after TYPE NUMERIC(14, 3);
+ALTER TABLE transaction_items ALTER COLUMN quantity TYPE NUMERIC(14, 3);
+ALTER TABLE refund_items ALTER COLUMN quantity TYPE NUMERIC(14, 3);
+ALTER TABLE order_items ALTER COLUMN quantity TYPE NUMERIC(14, 3);
+
+-- hanya barang timbang yang boleh punya quantity pecahan; plu adalah kode
+-- 5 digit produk di label timbangan berprefix 20-29
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS weighable BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS plu VARCHAR(5) CHECK (plu ~ '^[0-9]{5}$');
+
+CREATE UNIQUE INDEX IF NOT EXISTS products_plu_key ON products (plu) WHERE plu IS NOT NULL;
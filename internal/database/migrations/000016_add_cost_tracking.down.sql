@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_transactions_created_at;
+ALTER TABLE refund_items DROP COLUMN IF EXISTS cost_amount;
+ALTER TABLE transaction_items DROP COLUMN IF EXISTS cost_amount;
+DROP TABLE IF EXISTS cost_layers;
+ALTER TABLE stock_movements
+    DROP COLUMN IF EXISTS cost_amount,
+    DROP COLUMN IF EXISTS unit_cost;
+ALTER TABLE products
+    DROP COLUMN IF EXISTS costing_method,
+    DROP COLUMN IF EXISTS cost_price;
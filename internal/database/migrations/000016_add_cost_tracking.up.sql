@@ -0,0 +1,33 @@
+-- harga pokok per satuan dasar (rata-rata bergerak) dan metode perhitungan
+-- HPP yang dipakai saat barang keluar
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS cost_price INTEGER NOT NULL DEFAULT 0 CHECK (cost_price >= 0),
+    ADD COLUMN IF NOT EXISTS costing_method VARCHAR(10) NOT NULL DEFAULT 'average' CHECK (costing_method IN ('average', 'fifo'));
+
+-- unit_cost adalah harga pokok per satuan dasar barang masuk, cost_amount
+-- adalah perubahan nilai persediaan (negatif untuk barang keluar)
+ALTER TABLE stock_movements
+    ADD COLUMN IF NOT EXISTS unit_cost INTEGER CHECK (unit_cost >= 0),
+    ADD COLUMN IF NOT EXISTS cost_amount INTEGER NOT NULL DEFAULT 0;
+
+-- setiap penerimaan barang menjadi satu layer FIFO; remaining berkurang saat
+-- barang keluar dari layer paling lama
+CREATE TABLE IF NOT EXISTS cost_layers (
+    id          SERIAL PRIMARY KEY,
+    product_id  INTEGER NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    movement_id INTEGER NOT NULL REFERENCES stock_movements (id) ON DELETE CASCADE,
+    unit_cost   INTEGER NOT NULL CHECK (unit_cost >= 0),
+    remaining   NUMERIC(14, 3) NOT NULL CHECK (remaining >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_cost_layers_open ON cost_layers (product_id, id) WHERE remaining > 0;
+
+-- HPP setiap baris penjualan dan porsinya untuk unit yang di-refund; HPP
+-- refund hanya kembali ke persediaan jika refund di-restock
+ALTER TABLE transaction_items
+    ADD COLUMN IF NOT EXISTS cost_amount INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE refund_items
+    ADD COLUMN IF NOT EXISTS cost_amount INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_created_at ON transactions (created_at);
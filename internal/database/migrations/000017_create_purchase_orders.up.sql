@@ -0,0 +1,69 @@
+CREATE TABLE IF NOT EXISTS suppliers (
+    id         SERIAL PRIMARY KEY,
+    name       VARCHAR(100) NOT NULL,
+    phone      VARCHAR(30) NOT NULL DEFAULT '',
+    email      VARCHAR(100) NOT NULL DEFAULT '',
+    address    TEXT NOT NULL DEFAULT '',
+    note       TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS suppliers_name_key ON suppliers (LOWER(name));
+
+-- purchase order: draft -> sent -> partially_received -> received, atau
+-- cancelled sebelum selesai diterima. Stok baru bertambah saat barang diterima.
+CREATE TABLE IF NOT EXISTS purchase_orders (
+    id           SERIAL PRIMARY KEY,
+    supplier_id  INTEGER NOT NULL REFERENCES suppliers (id),
+    user_id      INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    status       VARCHAR(20) NOT NULL DEFAULT 'draft'
+        CHECK (status IN ('draft', 'sent', 'partially_received', 'received', 'cancelled')),
+    note         TEXT NOT NULL DEFAULT '',
+    total_amount INTEGER NOT NULL DEFAULT 0 CHECK (total_amount >= 0),
+    sent_at      TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_orders_supplier ON purchase_orders (supplier_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_purchase_orders_status ON purchase_orders (status, created_at DESC, id DESC);
+
+-- quantity, received_quantity dan unit_cost dalam satuan unit baris; factor
+-- disalin dari satuan produk saat PO dibuat supaya perubahan satuan produk
+-- tidak mengubah PO yang sudah ada
+CREATE TABLE IF NOT EXISTS purchase_order_lines (
+    id                SERIAL PRIMARY KEY,
+    purchase_order_id INTEGER NOT NULL REFERENCES purchase_orders (id) ON DELETE CASCADE,
+    product_id        INTEGER NOT NULL REFERENCES products (id),
+    variant_id        INTEGER REFERENCES product_variants (id),
+    unit              VARCHAR(20) NOT NULL,
+    factor            INTEGER NOT NULL CHECK (factor >= 1),
+    quantity          NUMERIC(14, 3) NOT NULL CHECK (quantity > 0),
+    unit_cost         INTEGER NOT NULL CHECK (unit_cost >= 0),
+    received_quantity NUMERIC(14, 3) NOT NULL DEFAULT 0 CHECK (received_quantity >= 0 AND received_quantity <= quantity)
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_order_lines_po ON purchase_order_lines (purchase_order_id);
+
+-- setiap penerimaan barang (goods receipt) untuk sebuah PO
+CREATE TABLE IF NOT EXISTS goods_receipts (
+    id                SERIAL PRIMARY KEY,
+    purchase_order_id INTEGER NOT NULL REFERENCES purchase_orders (id) ON DELETE CASCADE,
+    user_id           INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    note              TEXT NOT NULL DEFAULT '',
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_goods_receipts_po ON goods_receipts (purchase_order_id);
+
+CREATE TABLE IF NOT EXISTS goods_receipt_lines (
+    id                     SERIAL PRIMARY KEY,
+    goods_receipt_id       INTEGER NOT NULL REFERENCES goods_receipts (id) ON DELETE CASCADE,
+    purchase_order_line_id INTEGER NOT NULL REFERENCES purchase_order_lines (id) ON DELETE CASCADE,
+    quantity               NUMERIC(14, 3) NOT NULL CHECK (quantity > 0),
+    unit_cost              INTEGER NOT NULL CHECK (unit_cost >= 0),
+    stock_movement_id      INTEGER REFERENCES stock_movements (id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_goods_receipt_lines_receipt ON goods_receipt_lines (goods_receipt_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS stock_opname_counts;
+DROP TABLE IF EXISTS stock_opname_items;
+DROP TABLE IF EXISTS stock_opnames;
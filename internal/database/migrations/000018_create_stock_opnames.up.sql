@@ -0,0 +1,46 @@
+-- stock opname (hitung fisik): stok sistem di-snapshot saat sesi dibuka,
+-- hasil hitung dikumpulkan selama sesi open, lalu selisihnya diposting
+-- sebagai adjustment saat approve
+CREATE TABLE IF NOT EXISTS stock_opnames (
+    id          SERIAL PRIMARY KEY,
+    status      VARCHAR(20) NOT NULL DEFAULT 'open'
+        CHECK (status IN ('open', 'approved', 'cancelled')),
+    note        TEXT NOT NULL DEFAULT '',
+    category_id INTEGER REFERENCES categories (id) ON DELETE SET NULL,
+    created_by  INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    approved_by INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    approved_at TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- hanya satu sesi yang boleh open supaya selisih yang sama tidak diposting dua kali
+CREATE UNIQUE INDEX IF NOT EXISTS stock_opnames_open_key ON stock_opnames ((TRUE)) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_stock_opnames_created_at ON stock_opnames (created_at DESC, id DESC);
+
+-- expected_quantity dan unit_cost adalah snapshot stok dan harga pokok saat
+-- sesi dibuka; counted_quantity dibekukan saat approve
+CREATE TABLE IF NOT EXISTS stock_opname_items (
+    id                SERIAL PRIMARY KEY,
+    stock_opname_id   INTEGER NOT NULL REFERENCES stock_opnames (id) ON DELETE CASCADE,
+    product_id        INTEGER NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    variant_id        INTEGER REFERENCES product_variants (id) ON DELETE CASCADE,
+    expected_quantity NUMERIC(14, 3) NOT NULL,
+    unit_cost         INTEGER NOT NULL DEFAULT 0,
+    counted_quantity  NUMERIC(14, 3),
+    stock_movement_id INTEGER REFERENCES stock_movements (id) ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS stock_opname_items_key
+    ON stock_opname_items (stock_opname_id, product_id, (COALESCE(variant_id, 0)));
+
+-- hasil hitung per penghitung; jumlah semua penghitung adalah hasil hitung item
+CREATE TABLE IF NOT EXISTS stock_opname_counts (
+    id                   SERIAL PRIMARY KEY,
+    stock_opname_item_id INTEGER NOT NULL REFERENCES stock_opname_items (id) ON DELETE CASCADE,
+    user_id              INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    quantity             NUMERIC(14, 3) NOT NULL CHECK (quantity >= 0),
+    updated_at           TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS stock_opname_counts_key ON stock_opname_counts (stock_opname_item_id, user_id);
@@ -0,0 +1 @@
+ALTER TABLE cost_layers DROP COLUMN IF EXISTS remaining_value;
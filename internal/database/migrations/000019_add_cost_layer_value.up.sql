@@ -0,0 +1,6 @@
+-- sisa nilai layer FIFO. Harga per satuan dasar barang yang dibeli per
+-- karton bisa tidak bulat, jadi layer menyimpan nilai yang dibayar dan
+-- layer yang habis mengeluarkan seluruh sisa nilainya
+ALTER TABLE cost_layers ADD COLUMN IF NOT EXISTS remaining_value INTEGER;
+UPDATE cost_layers SET remaining_value = ROUND(remaining * unit_cost) WHERE remaining_value IS NULL;
+ALTER TABLE cost_layers ALTER COLUMN remaining_value SET NOT NULL;
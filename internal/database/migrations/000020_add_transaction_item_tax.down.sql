@@ -0,0 +1,3 @@
+ALTER TABLE transaction_items
+    DROP COLUMN IF EXISTS tax_amount,
+    DROP COLUMN IF EXISTS service_charge_amount;
@@ -0,0 +1,19 @@
+-- porsi service charge dan pajak setiap baris dari checkout, dipakai laporan
+-- penjualan supaya penjualan bersih sama dengan transaksi yang tersimpan
+ALTER TABLE transaction_items
+    ADD COLUMN IF NOT EXISTS service_charge_amount INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tax_amount INTEGER NOT NULL DEFAULT 0;
+
+-- Baris lama tidak menyimpan porsinya, jadi diisi perkiraan dari total baris:
+-- pajak = total x tarif / (1 + tarif) dibulatkan per baris dan service charge
+-- adalah sisa total di atas subtotal. Pajak transaksi lama dibulatkan sekali
+-- per tarif, jadi jumlah per baris bisa selisih beberapa rupiah dari
+-- transactions.tax_amount; transaksi baru selalu memakai porsi yang tepat.
+UPDATE transaction_items ti
+SET tax_amount = ROUND(ti.total_amount * ti.tax_rate_bps / (10000.0 + ti.tax_rate_bps))
+WHERE ti.tax_rate_bps > 0;
+
+UPDATE transaction_items ti
+SET service_charge_amount = GREATEST(ti.total_amount - ti.subtotal - CASE WHEN t.prices_include_tax THEN 0 ELSE ti.tax_amount END, 0)
+FROM transactions t
+WHERE t.id = ti.transaction_id AND t.service_charge_amount > 0;
@@ -0,0 +1,13 @@
+-- ledger stok adalah jejak audit dan tidak ikut terhapus bersama produknya:
+-- product_id menjadi NULL dan nama produk disimpan di setiap baris
+ALTER TABLE stock_movements ADD COLUMN IF NOT EXISTS product_name VARCHAR(255) NOT NULL DEFAULT '';
+
+UPDATE stock_movements sm
+SET product_name = p.name
+FROM products p
+WHERE p.id = sm.product_id AND sm.product_name = '';
+
+ALTER TABLE stock_movements ALTER COLUMN product_id DROP NOT NULL;
+ALTER TABLE stock_movements DROP CONSTRAINT IF EXISTS stock_movements_product_id_fkey;
+ALTER TABLE stock_movements
+    ADD CONSTRAINT stock_movements_product_id_fkey FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE SET NULL;
@@ -0,0 +1,7 @@
+DELETE FROM stock_movements WHERE variant_id IS NULL AND variant_name <> '';
+
+ALTER TABLE stock_movements DROP CONSTRAINT IF EXISTS stock_movements_variant_id_fkey;
+ALTER TABLE stock_movements
+    ADD CONSTRAINT stock_movements_variant_id_fkey FOREIGN KEY (variant_id) REFERENCES product_variants (id) ON DELETE CASCADE;
+
+ALTER TABLE stock_movements DROP COLUMN IF EXISTS variant_name;
@@ -0,0 +1,12 @@
+-- varian ikut terhapus bersama produknya; movement varian tetap tersimpan
+-- dengan variant_id NULL dan nama varian disimpan di setiap baris
+ALTER TABLE stock_movements ADD COLUMN IF NOT EXISTS variant_name VARCHAR(255) NOT NULL DEFAULT '';
+
+UPDATE stock_movements sm
+SET variant_name = array_to_string(v.option_values, ' / ')
+FROM product_variants v
+WHERE v.id = sm.variant_id AND sm.variant_name = '';
+
+ALTER TABLE stock_movements DROP CONSTRAINT IF EXISTS stock_movements_variant_id_fkey;
+ALTER TABLE stock_movements
+    ADD CONSTRAINT stock_movements_variant_id_fkey FOREIGN KEY (variant_id) REFERENCES product_variants (id) ON DELETE SET NULL;
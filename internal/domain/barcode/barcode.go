@@ -0,0 +1,110 @@
+// Package barcode memvalidasi kode EAN/UPC dari scanner dan membuat kode
+// internal untuk barang yang tidak punya kode pabrik.
+package barcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+// InternalPrefix adalah prefix GS1 020-029/040-049 yang dicadangkan untuk
+// penomoran di dalam toko, sehingga kode internal tidak bentrok dengan kode
+// pabrik yang valid
+const InternalPrefix = "04"
+
+var (
+	ErrInvalidFormat   = errors.New("barcode must be 8 (EAN-8), 12 (UPC-A) or 13 (EAN-13) digits")
+	ErrInvalidChecksum = errors.New("barcode check digit is invalid")
+)
+
+// Normalize memvalidasi check digit lalu mengembalikan bentuk kanonik kode.
+// UPC-A 12 digit disimpan sebagai EAN-13 dengan awalan 0 karena scanner bisa
+// mengirim kode yang sama dalam kedua bentuk.
+func Normalize(code string) (string, error) {
+	switch len(code) {
+	case 8, 12, 13:
+	default:
+		return "", ErrInvalidFormat
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return "", ErrInvalidFormat
+		}
+	}
+
+	body, check := code[:len(code)-1], int(code[len(code)-1]-'0')
+	if CheckDigit(body) != check {
+		return "", ErrInvalidChecksum
+	}
+	if len(code) == 12 {
+		return "0" + code, nil
+	}
+	return code, nil
+}
+
+// CheckDigit menghitung check digit modulo 10 GS1 untuk body (kode tanpa
+// check digit). Digit paling kanan body berbobot 3, selang-seling dengan 1.
+func CheckDigit(body string) int {
+	sum := 0
+	for i := 0; i < len(body); i++ {
+		digit := int(body[len(body)-1-i] - '0')
+		if i%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return (10 - sum%10) % 10
+}
+
+// Internal membuat EAN-13 internal dari nomor urut: prefix 04, sepuluh digit
+// nomor urut dan check digit
+func Internal(sequence int64) (string, error) {
+	if sequence <= 0 || sequence > 9_999_999_999 {
+		return "", fmt.Errorf("internal barcode sequence %d out of range", sequence)
+	}
+	body := fmt.Sprintf("%s%010d", InternalPrefix, sequence)
+	return fmt.Sprintf("%s%d", body, CheckDigit(body)), nil
+}
+
+// ScaleLabel adalah isi barcode label timbangan. Label memakai EAN-13
+// berprefix 20-29 (rentang GS1 untuk barang dengan berat/harga variabel):
+// 2 digit prefix, 5 digit PLU produk, 5 digit nilai dan check digit. Prefix
+// 20-24 berisi berat dalam seperseribu satuan dasar (gram untuk kg), prefix
+// 25-29 berisi harga total dalam rupiah.
+type ScaleLabel struct {
+	PLU string
+	// IsPrice bernilai true jika Value adalah harga total (rupiah), false
+	// jika Value adalah berat dalam seperseribu satuan dasar
+	IsPrice bool
+	Value   int
+}
+
+// ParseScale membaca barcode timbangan dari kode yang sudah dinormalisasi.
+// ok false jika kode bukan EAN-13 berprefix 20-29.
+func ParseScale(code string) (label ScaleLabel, ok bool) {
+	if len(code) != 13 || code[0] != '2' {
+		return ScaleLabel{}, false
+	}
+	value := 0
+	for _, c := range code[7:12] {
+		value = value*10 + int(c-'0')
+	}
+	return ScaleLabel{
+		PLU:     code[2:7],
+		IsPrice: code[1] >= '5',
+		Value:   value,
+	}, true
+}
+
+// ValidPLU mengecek bahwa plu adalah kode timbangan 5 digit
+func ValidPLU(plu string) bool {
+	if len(plu) != 5 {
+		return false
+	}
+	for _, c := range plu {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,138 @@
+package barcode
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckDigit(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{name: "EAN-13", body: "400638133393", want: 1},
+		{name: "EAN-13", body: "590123412345", want: 7},
+		{name: "EAN-13 check digit zero", body: "251234500750", want: 0},
+		{name: "EAN-8", body: "9638507", want: 4},
+		{name: "EAN-8", body: "7351353", want: 7},
+		{name: "UPC-A", body: "03600029145", want: 2},
+		{name: "UPC-A", body: "01234567890", want: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" "+tt.body, func(t *testing.T) {
+			if got := CheckDigit(tt.body); got != tt.want {
+				t.Errorf("CheckDigit(%q) = %d, want %d", tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		code    string
+		want    string
+		wantErr error
+	}{
+		{code: "4006381333931", want: "4006381333931"},
+		{code: "96385074", want: "96385074"},
+		{code: "036000291452", want: "0036000291452"},
+		{code: "012345678905", want: "0012345678905"},
+		{code: "4006381333932", wantErr: ErrInvalidChecksum},
+		{code: "96385075", wantErr: ErrInvalidChecksum},
+		{code: "036000291453", wantErr: ErrInvalidChecksum},
+		{code: "40063813339", wantErr: ErrInvalidFormat},
+		{code: "40063813339311", wantErr: ErrInvalidFormat},
+		{code: "400638133393A", wantErr: ErrInvalidFormat},
+		{code: "", wantErr: ErrInvalidFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			got, err := Normalize(tt.code)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Normalize(%q) error = %v, want %v", tt.code, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeUPCAMatchesEAN13(t *testing.T) {
+	upc, err := Normalize("036000291452")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ean, err := Normalize("0036000291452")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if upc != ean {
+		t.Errorf("UPC-A normalized to %q, EAN-13 form to %q, want the same code", upc, ean)
+	}
+}
+
+func TestInternal(t *testing.T) {
+	tests := []struct {
+		sequence int64
+		want     string
+		wantErr  bool
+	}{
+		{sequence: 1, want: "0400000000015"},
+		{sequence: 9_999_999_999, want: "0499999999998"},
+		{sequence: 0, wantErr: true},
+		{sequence: 10_000_000_000, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := Internal(tt.sequence)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Internal(%d) = %q, want error", tt.sequence, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Internal(%d) = %q, %v, want %q", tt.sequence, got, err, tt.want)
+			continue
+		}
+		if _, err := Normalize(got); err != nil {
+			t.Errorf("Internal(%d) = %q is not a valid EAN-13: %v", tt.sequence, got, err)
+		}
+	}
+}
+
+func TestParseScale(t *testing.T) {
+	tests := []struct {
+		name   string
+		code   string
+		want   ScaleLabel
+		wantOK bool
+	}{
+		{name: "weight label", code: "2012345012509", want: ScaleLabel{PLU: "12345", Value: 1250}, wantOK: true},
+		{name: "price label", code: "2512345007500", want: ScaleLabel{PLU: "12345", IsPrice: true, Value: 750}, wantOK: true},
+		{name: "last weight prefix", code: "2400001000013", want: ScaleLabel{PLU: "00001", Value: 1}, wantOK: true},
+		{name: "regular EAN-13", code: "4006381333931"},
+		{name: "EAN-8 starting with 2", code: "20123454"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseScale(tt.code)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("ParseScale(%q) = %+v, %v, want %+v, %v", tt.code, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestValidPLU(t *testing.T) {
+	for plu, want := range map[string]bool{"12345": true, "00001": true, "1234": false, "123456": false, "12a45": false} {
+		if got := ValidPLU(plu); got != want {
+			t.Errorf("ValidPLU(%q) = %v, want %v", plu, got, want)
+		}
+	}
+}
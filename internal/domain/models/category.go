@@ -4,4 +4,7 @@ type Category struct {
 	ID          int    `json:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	// TaxRateBps menimpa tarif pajak default untuk produk di kategori ini
+	// (basis poin, 1100 = 11%). Kosong berarti ikut tarif default.
+	TaxRateBps *int `json:"tax_rate_bps,omitempty"`
 }
@@ -0,0 +1,42 @@
+package models
+
+const (
+	// CostingAverage menghitung HPP dari harga pokok rata-rata bergerak
+	CostingAverage = "average"
+	// CostingFIFO menghitung HPP dari layer penerimaan paling lama
+	CostingFIFO = "fifo"
+)
+
+// marginBasisPoints adalah penyebut margin: 10000 bps = 100%
+const marginBasisPoints = 10000
+
+// MarginBps menghitung margin kotor profit terhadap revenue dalam basis poin
+// (dibulatkan ke bawah). Revenue nol atau negatif menghasilkan 0.
+func MarginBps(profit, revenue int) int {
+	if revenue <= 0 {
+		return 0
+	}
+	return int(int64(profit) * marginBasisPoints / int64(revenue))
+}
+
+// BaseUnitCost membagi harga pokok per satuan besar (factor satuan dasar)
+// menjadi harga pokok per satuan dasar, dibulatkan ke rupiah terdekat
+func BaseUnitCost(unitCost, factor int) int {
+	return (unitCost + factor/2) / factor
+}
+
+// FillMargins menghitung margin produk dan setiap variannya dari harga jual
+// dikurangi harga pokok. Varian memakai harga pokok produk.
+func (p *Product) FillMargins() {
+	cost := 0
+	if p.CostPrice != nil {
+		cost = *p.CostPrice
+	}
+	p.Margin = p.Price - cost
+	p.MarginBps = MarginBps(p.Margin, p.Price)
+	for i := range p.Variants {
+		v := &p.Variants[i]
+		v.Margin = v.Price - cost
+		v.MarginBps = MarginBps(v.Margin, v.Price)
+	}
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+// Sentinel error domain. Gunakan errors.Is untuk mengecek jenis error,
+// misalnya errors.Is(err, models.ErrNotFound).
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrValidation   = errors.New("validation failed")
+	ErrConflict     = errors.New("conflict")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+)
+
+// FieldError adalah detail validasi untuk satu field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError adalah error input yang tidak valid beserta field-nya
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func NewValidationError(field, message string) error {
+	return &ValidationError{Fields: []FieldError{{Field: field, Message: message}}}
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		messages[i] = f.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// NotFoundError adalah error untuk resource yang tidak ditemukan
+type NotFoundError struct {
+	Resource string
+}
+
+func NewNotFoundError(resource string) error {
+	return &NotFoundError{Resource: resource}
+}
+
+func (e *NotFoundError) Error() string {
+	return e.Resource + " not found"
+}
+
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// ConflictError adalah error karena state data bentrok, misalnya
+// unique/foreign key violation atau stok yang tidak cukup
+type ConflictError struct {
+	Message string
+}
+
+func NewConflictError(message string) error {
+	return &ConflictError{Message: message}
+}
+
+func (e *ConflictError) Error() string {
+	return e.Message
+}
+
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// UnauthorizedError adalah error autentikasi (kredensial atau token tidak valid)
+type UnauthorizedError struct {
+	Message string
+}
+
+func NewUnauthorizedError(message string) error {
+	return &UnauthorizedError{Message: message}
+}
+
+func (e *UnauthorizedError) Error() string {
+	return e.Message
+}
+
+func (e *UnauthorizedError) Is(target error) bool {
+	return target == ErrUnauthorized
+}
+
+// ForbiddenError adalah error karena user yang login tidak berhak atas resource,
+// misalnya kasir yang mengakses shift milik kasir lain
+type ForbiddenError struct {
+	Message string
+}
+
+func NewForbiddenError(message string) error {
+	return &ForbiddenError{Message: message}
+}
+
+func (e *ForbiddenError) Error() string {
+	return e.Message
+}
+
+func (e *ForbiddenError) Is(target error) bool {
+	return target == ErrForbidden
+}
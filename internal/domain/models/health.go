@@ -2,6 +2,11 @@ package models
 
 import "time"
 
+const (
+	HealthStatusUp   = "up"
+	HealthStatusDown = "down"
+)
+
 // HealthResponse adalah response untuk health check
 type HealthResponse struct {
 	Status    string    `json:"status"`
@@ -9,3 +14,48 @@ type HealthResponse struct {
 	Service   string    `json:"service"`
 	Version   string    `json:"version"`
 }
+
+// BuildInfo adalah informasi build yang di-inject saat link time
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// DependencyCheck adalah hasil pengecekan satu dependency
+type DependencyCheck struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// DatabasePoolStats adalah ringkasan sql.DBStats
+type DatabasePoolStats struct {
+	MaxOpenConnections int   `json:"max_open_connections"`
+	OpenConnections    int   `json:"open_connections"`
+	InUse              int   `json:"in_use"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"wait_count"`
+	WaitDurationMs     int64 `json:"wait_duration_ms"`
+	MaxIdleClosed      int64 `json:"max_idle_closed"`
+	MaxLifetimeClosed  int64 `json:"max_lifetime_closed"`
+}
+
+// MigrationInfo adalah versi schema yang terpasang dibanding versi di binary
+type MigrationInfo struct {
+	CurrentVersion int `json:"current_version"`
+	LatestVersion  int `json:"latest_version"`
+}
+
+// ReadinessResponse adalah response untuk readiness probe
+type ReadinessResponse struct {
+	Status     string            `json:"status"`
+	Timestamp  time.Time         `json:"timestamp"`
+	Service    string            `json:"service"`
+	Build      BuildInfo         `json:"build"`
+	Checks     []DependencyCheck `json:"checks"`
+	Database   DatabasePoolStats `json:"database"`
+	Migrations MigrationInfo     `json:"migrations"`
+}
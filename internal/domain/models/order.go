@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+const (
+	OrderStatusDraft     = "draft"
+	OrderStatusHeld      = "held"
+	OrderStatusVoided    = "voided"
+	OrderStatusCompleted = "completed"
+)
+
+// Order adalah keranjang yang disimpan di server sebelum dibayar.
+//
+// Alur status: draft -> held (parkir) -> draft (resume) -> completed lewat
+// checkout dengan order_id. Order draft/held bisa di-void dengan alasan dan
+// persetujuan supervisor. Stok tidak berubah sampai order di-checkout.
+type Order struct {
+	ID            int         `json:"id"`
+	UserID        *int        `json:"user_id,omitempty"`
+	Status        string      `json:"status"`
+	Note          string      `json:"note,omitempty"`
+	PromoCode     string      `json:"promo_code,omitempty"`
+	TransactionID *int        `json:"transaction_id,omitempty"`
+	VoidReason    string      `json:"void_reason,omitempty"`
+	VoidedBy      *int        `json:"voided_by,omitempty"`
+	VoidedAt      *time.Time  `json:"voided_at,omitempty"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+	Items         []OrderItem `json:"items"`
+}
+
+// OrderItem adalah satu baris order. Price adalah harga produk (atau
+// varian) saat ini, harga final dihitung ulang saat checkout.
+type OrderItem struct {
+	ProductID   int      `json:"product_id"`
+	ProductName string   `json:"product_name"`
+	VariantID   *int     `json:"variant_id,omitempty"`
+	VariantName string   `json:"variant_name,omitempty"`
+	Price       int      `json:"price"`
+	Quantity    Quantity `json:"quantity"`
+}
+
+// CreateOrderRequest adalah payload untuk POST /api/orders
+type CreateOrderRequest struct {
+	Note      string         `json:"note"`
+	PromoCode string         `json:"promo_code"`
+	Items     []CheckoutItem `json:"items"`
+}
+
+// UpdateOrderRequest adalah payload untuk PATCH /api/orders/{id}.
+// Setiap item menimpa quantity produk tersebut di order, quantity 0 menghapus
+// barisnya, dan produk yang tidak disebut tidak berubah. Note dan PromoCode
+// hanya diubah jika dikirim.
+type UpdateOrderRequest struct {
+	Note      *string        `json:"note,omitempty"`
+	PromoCode *string        `json:"promo_code,omitempty"`
+	Items     []CheckoutItem `json:"items"`
+}
+
+// VoidOrderRequest adalah payload untuk POST /api/orders/{id}/void.
+// Kredensial supervisor (admin) wajib diisi jika yang melakukan void adalah kasir.
+type VoidOrderRequest struct {
+	Reason             string `json:"reason"`
+	SupervisorUsername string `json:"supervisor_username,omitempty"`
+	SupervisorPassword string `json:"supervisor_password,omitempty"`
+}
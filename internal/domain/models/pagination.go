@@ -0,0 +1,10 @@
+package models
+
+// PaginationMeta adalah metadata pagination yang dikirim bersama data list
+type PaginationMeta struct {
+	Page       int    `json:"page,omitempty"`
+	PerPage    int    `json:"per_page"`
+	Total      int    `json:"total"`
+	TotalPages int    `json:"total_pages"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
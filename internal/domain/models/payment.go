@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+const (
+	PaymentMethodCash       = "cash"
+	PaymentMethodDebitCard  = "debit_card"
+	PaymentMethodCreditCard = "credit_card"
+	PaymentMethodEWallet    = "ewallet"
+	PaymentMethodQRIS       = "qris"
+)
+
+// IsValidPaymentMethod mengecek apakah method adalah salah satu metode pembayaran yang didukung
+func IsValidPaymentMethod(method string) bool {
+	switch method {
+	case PaymentMethodCash, PaymentMethodDebitCard, PaymentMethodCreditCard, PaymentMethodEWallet, PaymentMethodQRIS:
+		return true
+	}
+	return false
+}
+
+// Payment adalah satu bagian pembayaran dari sebuah transaksi. Satu transaksi
+// bisa dibayar dengan beberapa metode (split tender).
+type Payment struct {
+	ID             int       `json:"id"`
+	TransactionID  int       `json:"transaction_id"`
+	Method         string    `json:"method"`
+	Amount         int       `json:"amount"`
+	TenderedAmount *int      `json:"tendered_amount,omitempty"`
+	ChangeAmount   int       `json:"change_amount"`
+	Reference      string    `json:"reference,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// PaymentRequest adalah pembayaran yang dikirim kasir saat checkout.
+// Amount adalah porsi total yang dibayar dengan metode ini; untuk tunai,
+// TenderedAmount adalah uang yang diterima dan selisihnya menjadi kembalian.
+// Reference diisi kode approval EDC / ID transaksi e-wallet / QRIS.
+type PaymentRequest struct {
+	Method         string `json:"method"`
+	Amount         int    `json:"amount"`
+	TenderedAmount *int   `json:"tendered_amount,omitempty"`
+	Reference      string `json:"reference,omitempty"`
+}
@@ -1,10 +1,99 @@
 package models
 
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
 type Product struct {
 	ID         int       `json:"id"`
 	Name       string    `json:"name"`
 	Price      int       `json:"price"`
-	Stock      int       `json:"stock"`
+	Stock      Quantity  `json:"stock"`
 	CategoryID int       `json:"category_id"`
 	Category   *Category `json:"category,omitempty"`
+	// TaxRateBps menimpa tarif kategori dan default (basis poin).
+	// TaxExempt membebaskan produk dari pajak apapun tarifnya.
+	TaxRateBps *int `json:"tax_rate_bps,omitempty"`
+	TaxExempt  bool `json:"tax_exempt"`
+	// SKU adalah kode internal toko, unik jika diisi
+	SKU string `json:"sku,omitempty"`
+	// Barcodes berisi kode EAN-8/EAN-13 produk (UPC-A disimpan sebagai
+	// EAN-13 berawalan 0). Saat update, nil berarti barcode tidak diubah dan
+	// array kosong menghapus semua barcode.
+	Barcodes []string `json:"barcodes"`
+	// Options dan Variants diisi untuk produk bervarian. Price produk menjadi
+	// harga awal varian baru dan Stock adalah jumlah stok semua varian.
+	Options  []ProductOption  `json:"options,omitempty"`
+	Variants []ProductVariant `json:"variants,omitempty"`
+	// VariantID diisi pada hasil lookup barcode milik sebuah varian
+	VariantID *int `json:"variant_id,omitempty"`
+	// BaseUnit adalah satuan stok dan harga (default pcs). Units adalah
+	// satuan tambahan beserta konversinya; saat update nil berarti tidak
+	// diubah dan array kosong menghapus semua satuan tambahan.
+	BaseUnit string        `json:"base_unit"`
+	Units    []ProductUnit `json:"units"`
+	// Weighable menandai barang timbang/ukur yang boleh dijual dengan
+	// quantity pecahan (mis. 0.35 kg). PLU adalah kode 5 digit produk di
+	// timbangan untuk barcode berawalan 20-29.
+	Weighable bool   `json:"weighable"`
+	PLU       string `json:"plu,omitempty"`
+	// ScannedQuantity diisi pada lookup barcode timbangan dengan berat atau
+	// quantity yang tercetak di label
+	ScannedQuantity *Quantity `json:"scanned_quantity,omitempty"`
+	// CostPrice adalah harga pokok per satuan dasar, rata-rata bergerak dari
+	// semua penerimaan barang; saat update nil berarti tidak diubah.
+	// CostingMethod (average atau fifo) menentukan HPP saat barang keluar.
+	CostPrice     *int   `json:"cost_price"`
+	CostingMethod string `json:"costing_method"`
+	// Margin dan MarginBps dihitung dari Price dikurangi CostPrice
+	Margin    int `json:"margin"`
+	MarginBps int `json:"margin_bps"`
+}
+
+// ProductFilter adalah parameter filter, sorting dan pagination untuk list produk
+type ProductFilter struct {
+	Name       string
+	CategoryID int
+	MinPrice   *int
+	MaxPrice   *int
+	InStock    bool
+	// Sort berisi name, price atau stock, prefix "-" untuk descending
+	Sort    string
+	Page    int
+	PerPage int
+	Cursor  *ProductCursor
+}
+
+// ProductCursor adalah posisi terakhir untuk keyset pagination
+type ProductCursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    int    `json:"id"`
+}
+
+// ProductList adalah hasil list produk beserta metadata pagination
+type ProductList struct {
+	Products []Product
+	Meta     PaginationMeta
+}
+
+// Encode mengubah cursor menjadi token opaque untuk client
+func (c ProductCursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeProductCursor membaca token cursor dari client
+func DecodeProductCursor(token string) (*ProductCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor ProductCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
 }
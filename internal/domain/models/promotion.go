@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+const (
+	PromotionTypePercentage = "percentage"
+	PromotionTypeFixed      = "fixed"
+	PromotionTypeBuyXGetY   = "buy_x_get_y"
+
+	PromotionScopeItem     = "item"
+	PromotionScopeCategory = "category"
+	PromotionScopeCart     = "cart"
+)
+
+// Promotion adalah aturan diskon yang dievaluasi saat checkout.
+//
+// Value berarti persen (1-100) untuk tipe percentage, potongan per unit untuk
+// fixed di scope item/category, atau potongan sekali untuk fixed di scope cart.
+// Tipe buy_x_get_y memberi GetQuantity unit gratis untuk setiap
+// BuyQuantity+GetQuantity unit produk yang sama.
+// DailyStartTime/DailyEndTime ("HH:MM", zona waktu toko) membatasi jam berlaku
+// setiap hari, misalnya happy hour. Promo dengan Code hanya berlaku jika kasir
+// memasukkan kode tersebut.
+type Promotion struct {
+	ID             int        `json:"id"`
+	Name           string     `json:"name"`
+	Type           string     `json:"type"`
+	Scope          string     `json:"scope"`
+	ProductID      *int       `json:"product_id,omitempty"`
+	CategoryID     *int       `json:"category_id,omitempty"`
+	Value          int        `json:"value"`
+	BuyQuantity    int        `json:"buy_quantity,omitempty"`
+	GetQuantity    int        `json:"get_quantity,omitempty"`
+	MinSpend       int        `json:"min_spend"`
+	StartsAt       *time.Time `json:"starts_at,omitempty"`
+	EndsAt         *time.Time `json:"ends_at,omitempty"`
+	DailyStartTime string     `json:"daily_start_time,omitempty"`
+	DailyEndTime   string     `json:"daily_end_time,omitempty"`
+	Code           string     `json:"code,omitempty"`
+	Active         bool       `json:"active"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// AppliedPromotion adalah promo yang terpakai di sebuah transaksi beserta
+// total potongannya
+type AppliedPromotion struct {
+	PromotionID    int    `json:"promotion_id"`
+	Name           string `json:"name"`
+	Code           string `json:"code,omitempty"`
+	DiscountAmount int    `json:"discount_amount"`
+}
@@ -0,0 +1,131 @@
+package models
+
+import "time"
+
+const (
+	PurchaseOrderStatusDraft             = "draft"
+	PurchaseOrderStatusSent              = "sent"
+	PurchaseOrderStatusPartiallyReceived = "partially_received"
+	PurchaseOrderStatusReceived          = "received"
+	PurchaseOrderStatusCancelled         = "cancelled"
+)
+
+// Supplier adalah pemasok barang untuk purchase order
+type Supplier struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Phone     string    `json:"phone"`
+	Email     string    `json:"email"`
+	Address   string    `json:"address"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PurchaseOrder adalah pesanan barang ke supplier.
+//
+// Alur status: draft -> sent -> partially_received -> received. Hanya draft
+// yang boleh diubah; draft, sent dan partially_received bisa di-cancel dan
+// sisa yang belum diterima tidak ditunggu lagi. Stok bertambah per goods
+// receipt, bukan saat PO dibuat.
+type PurchaseOrder struct {
+	ID           int                 `json:"id"`
+	SupplierID   int                 `json:"supplier_id"`
+	SupplierName string              `json:"supplier_name"`
+	UserID       *int                `json:"user_id,omitempty"`
+	Status       string              `json:"status"`
+	Note         string              `json:"note,omitempty"`
+	TotalAmount  int                 `json:"total_amount"`
+	SentAt       *time.Time          `json:"sent_at,omitempty"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+	Lines        []PurchaseOrderLine `json:"lines"`
+	// Receipts hanya dimuat pada detail PO
+	Receipts []GoodsReceipt `json:"receipts,omitempty"`
+}
+
+// PurchaseOrderLine adalah satu baris PO. Quantity, ReceivedQuantity dan
+// UnitCost dalam satuan Unit; Factor adalah jumlah satuan dasar per Unit
+// saat PO dibuat.
+type PurchaseOrderLine struct {
+	ID               int      `json:"id"`
+	ProductID        int      `json:"product_id"`
+	ProductName      string   `json:"product_name"`
+	VariantID        *int     `json:"variant_id,omitempty"`
+	VariantName      string   `json:"variant_name,omitempty"`
+	Unit             string   `json:"unit"`
+	Factor           int      `json:"factor"`
+	Quantity         Quantity `json:"quantity"`
+	UnitCost         int      `json:"unit_cost"`
+	Subtotal         int      `json:"subtotal"`
+	ReceivedQuantity Quantity `json:"received_quantity"`
+}
+
+// GoodsReceipt adalah satu kali penerimaan barang untuk sebuah PO
+type GoodsReceipt struct {
+	ID              int                `json:"id"`
+	PurchaseOrderID int                `json:"purchase_order_id"`
+	UserID          *int               `json:"user_id,omitempty"`
+	Note            string             `json:"note,omitempty"`
+	CreatedAt       time.Time          `json:"created_at"`
+	Lines           []GoodsReceiptLine `json:"lines"`
+}
+
+// GoodsReceiptLine adalah jumlah yang diterima untuk satu baris PO, dalam
+// satuan baris tersebut. StockMovementID menunjuk restock di ledger stok.
+type GoodsReceiptLine struct {
+	LineID          int      `json:"line_id"`
+	ProductID       int      `json:"product_id"`
+	VariantID       *int     `json:"variant_id,omitempty"`
+	Quantity        Quantity `json:"quantity"`
+	UnitCost        int      `json:"unit_cost"`
+	StockMovementID *int     `json:"stock_movement_id,omitempty"`
+}
+
+// PurchaseOrderRequest adalah payload untuk POST /api/purchase-orders dan
+// PUT /api/purchase-orders/{id}. PUT menimpa supplier, note dan semua baris.
+type PurchaseOrderRequest struct {
+	SupplierID int                        `json:"supplier_id"`
+	Note       string                     `json:"note"`
+	Lines      []PurchaseOrderLineRequest `json:"lines"`
+}
+
+// PurchaseOrderLineRequest adalah baris PO. Unit kosong berarti satuan dasar
+// produk, UnitCost adalah harga beli per Unit.
+type PurchaseOrderLineRequest struct {
+	ProductID int      `json:"product_id"`
+	VariantID *int     `json:"variant_id,omitempty"`
+	Quantity  Quantity `json:"quantity"`
+	Unit      string   `json:"unit,omitempty"`
+	UnitCost  int      `json:"unit_cost"`
+}
+
+// ReceiveGoodsRequest adalah payload untuk POST /api/purchase-orders/{id}/receive.
+// Lines kosong berarti semua sisa yang belum diterima.
+type ReceiveGoodsRequest struct {
+	Note  string               `json:"note"`
+	Lines []ReceiveLineRequest `json:"lines"`
+}
+
+// ReceiveLineRequest adalah jumlah yang diterima untuk satu baris PO.
+// UnitCost mengganti harga beli per unit jika harga di faktur berbeda dari PO.
+type ReceiveLineRequest struct {
+	LineID   int      `json:"line_id"`
+	Quantity Quantity `json:"quantity"`
+	UnitCost *int     `json:"unit_cost,omitempty"`
+}
+
+// PurchaseOrderFilter adalah parameter list PO. SupplierID 0 dan Status
+// kosong berarti semua.
+type PurchaseOrderFilter struct {
+	SupplierID int
+	Status     string
+	Page       int
+	PerPage    int
+}
+
+// PurchaseOrderList adalah daftar PO beserta metadata pagination
+type PurchaseOrderList struct {
+	PurchaseOrders []PurchaseOrder
+	Meta           PaginationMeta
+}
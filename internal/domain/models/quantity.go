@@ -0,0 +1,148 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// QuantityScale adalah jumlah pecahan per satu unit: Quantity disimpan
+// sebagai bilangan bulat per seribu (gram untuk kg, milimeter untuk m)
+// sehingga tidak ada pembulatan float.
+const QuantityScale = 1000
+
+// Quantity adalah jumlah barang dengan presisi tetap 3 desimal. Di JSON dan
+// database ditulis sebagai desimal biasa, mis. 0.35 atau 2. Nilai nol
+// Quantity adalah 0.
+type Quantity int64
+
+// WholeQuantity membuat Quantity dari jumlah unit utuh
+func WholeQuantity(n int) Quantity {
+	return Quantity(n) * QuantityScale
+}
+
+// ParseQuantity membaca desimal dengan paling banyak 3 angka di belakang
+// titik. Angka nol di belakang tidak dihitung, jadi "1.2500" tetap valid.
+func ParseQuantity(s string) (Quantity, error) {
+	invalid := fmt.Errorf("invalid quantity %q: must be a decimal with at most 3 fraction digits", s)
+
+	text := strings.TrimSpace(s)
+	negative := strings.HasPrefix(text, "-")
+	text = strings.TrimPrefix(text, "-")
+	whole, frac, hasFrac := strings.Cut(text, ".")
+	if whole == "" || (hasFrac && frac == "") {
+		return 0, invalid
+	}
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > 3 {
+		return 0, invalid
+	}
+	for _, part := range []string{whole, frac} {
+		for _, c := range part {
+			if c < '0' || c > '9' {
+				return 0, invalid
+			}
+		}
+	}
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > 1<<53/QuantityScale {
+		return 0, invalid
+	}
+	milli := int64(0)
+	if frac != "" {
+		milli, _ = strconv.ParseInt(frac+strings.Repeat("0", 3-len(frac)), 10, 64)
+	}
+
+	q := Quantity(units*QuantityScale + milli)
+	if negative {
+		q = -q
+	}
+	return q, nil
+}
+
+// String menulis quantity tanpa nol berlebih, mis. 2, 0.35, -1.5
+func (q Quantity) String() string {
+	sign := ""
+	if q < 0 {
+		sign = "-"
+		q = -q
+	}
+	whole, milli := int64(q)/QuantityScale, int64(q)%QuantityScale
+	if milli == 0 {
+		return fmt.Sprintf("%s%d", sign, whole)
+	}
+	return strings.TrimRight(fmt.Sprintf("%s%d.%03d", sign, whole, milli), "0")
+}
+
+// IsWhole bernilai true jika quantity tidak punya pecahan
+func (q Quantity) IsWhole() bool {
+	return q%QuantityScale == 0
+}
+
+// WholeUnits mengembalikan jumlah unit utuh (pecahan dibuang)
+func (q Quantity) WholeUnits() int {
+	return int(q / QuantityScale)
+}
+
+// Amount menghitung harga quantity dengan unitPrice per unit, dibulatkan ke
+// rupiah terdekat (setengah ke atas). Semua total baris memakai pembulatan
+// ini supaya hasilnya sama di checkout, preview dan order.
+func (q Quantity) Amount(unitPrice int) int {
+	product := int64(unitPrice) * int64(q)
+	if product < 0 {
+		return -int((-product + QuantityScale/2) / QuantityScale)
+	}
+	return int((product + QuantityScale/2) / QuantityScale)
+}
+
+func (q Quantity) MarshalJSON() ([]byte, error) {
+	return []byte(q.String()), nil
+}
+
+// UnmarshalJSON menerima angka JSON atau string berisi desimal. Angka dibaca
+// sebagai teks, tidak lewat float64. null tidak mengubah nilai, sama seperti
+// tipe bawaan encoding/json.
+func (q *Quantity) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	parsed, err := ParseQuantity(strings.Trim(string(data), `"`))
+	if err != nil {
+		return err
+	}
+	*q = parsed
+	return nil
+}
+
+// Scan membaca kolom NUMERIC yang dikirim driver sebagai teks
+func (q *Quantity) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*q = 0
+		return nil
+	case int64:
+		*q = WholeQuantity(int(v))
+		return nil
+	case string:
+		return q.scanText(v)
+	case []byte:
+		return q.scanText(string(v))
+	}
+	return fmt.Errorf("cannot scan %T into Quantity", src)
+}
+
+func (q *Quantity) scanText(s string) error {
+	parsed, err := ParseQuantity(s)
+	if err != nil {
+		return err
+	}
+	*q = parsed
+	return nil
+}
+
+// Value menulis quantity sebagai teks desimal untuk kolom NUMERIC
+func (q Quantity) Value() (driver.Value, error) {
+	return q.String(), nil
+}
@@ -0,0 +1,136 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Quantity
+		wantErr bool
+	}{
+		{input: "2", want: 2000},
+		{input: "0.35", want: 350},
+		{input: "1.250", want: 1250},
+		{input: "1.2500", want: 1250},
+		{input: "-1.5", want: -1500},
+		{input: " 3 ", want: 3000},
+		{input: "0.001", want: 1},
+		{input: "9007199254740", want: 9007199254740000},
+		{input: "9007199254741", wantErr: true},
+		{input: "99999999999999999999", wantErr: true},
+		{input: "1.2345", wantErr: true},
+		{input: "0.0001", wantErr: true},
+		{input: "1.", wantErr: true},
+		{input: ".5", wantErr: true},
+		{input: "", wantErr: true},
+		{input: "-", wantErr: true},
+		{input: "1e3", wantErr: true},
+		{input: "+1", wantErr: true},
+		{input: "1.-5", wantErr: true},
+		{input: "1,5", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseQuantity(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseQuantity(%q) = %d, want error", tt.input, got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ParseQuantity(%q) = %d, %v, want %d", tt.input, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuantityString(t *testing.T) {
+	tests := []struct {
+		q    Quantity
+		want string
+	}{
+		{q: 0, want: "0"},
+		{q: 2000, want: "2"},
+		{q: 350, want: "0.35"},
+		{q: 1, want: "0.001"},
+		{q: -1500, want: "-1.5"},
+		{q: -5, want: "-0.005"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.q.String(); got != tt.want {
+			t.Errorf("Quantity(%d).String() = %q, want %q", int64(tt.q), got, tt.want)
+		}
+	}
+}
+
+func TestQuantityAmount(t *testing.T) {
+	tests := []struct {
+		name      string
+		q         Quantity
+		unitPrice int
+		want      int
+	}{
+		{name: "whole units", q: 3000, unitPrice: 2500, want: 7500},
+		{name: "weight rounds down below half", q: 333, unitPrice: 10, want: 3},
+		{name: "half rupiah rounds up", q: 1500, unitPrice: 1, want: 2},
+		{name: "negative half rounds away from zero", q: -1500, unitPrice: 1, want: -2},
+		{name: "negative below half rounds toward zero", q: -1499, unitPrice: 1, want: -1},
+		{name: "negative price", q: 1500, unitPrice: -1, want: -2},
+		{name: "negative rounding mirrors positive", q: -350, unitPrice: 45000, want: -15750},
+		{name: "large quantity does not overflow", q: 9007199254740000, unitPrice: 1000, want: 9007199254740000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.q.Amount(tt.unitPrice); got != tt.want {
+				t.Errorf("Quantity(%s).Amount(%d) = %d, want %d", tt.q, tt.unitPrice, got, tt.want)
+			}
+			if got, mirrored := tt.q.Amount(tt.unitPrice), (-tt.q).Amount(tt.unitPrice); got != -mirrored {
+				t.Errorf("Amount is not symmetric: %d vs %d", got, mirrored)
+			}
+		})
+	}
+}
+
+func TestQuantityUnmarshalJSON(t *testing.T) {
+	var payload struct {
+		Quantity Quantity  `json:"quantity"`
+		Optional *Quantity `json:"optional"`
+	}
+
+	if err := json.Unmarshal([]byte(`{"quantity": 1.25, "optional": "0.5"}`), &payload); err != nil {
+		t.Fatalf("unmarshal number and string: %v", err)
+	}
+	if payload.Quantity != 1250 || payload.Optional == nil || *payload.Optional != 500 {
+		t.Errorf("got quantity %s, optional %v, want 1.25 and 0.5", payload.Quantity, payload.Optional)
+	}
+
+	if err := json.Unmarshal([]byte(`{"quantity": null, "optional": null}`), &payload); err != nil {
+		t.Fatalf("unmarshal null: %v", err)
+	}
+	if payload.Quantity != 1250 {
+		t.Errorf("null changed quantity to %s, want it unchanged", payload.Quantity)
+	}
+	if payload.Optional != nil {
+		t.Errorf("null optional = %s, want nil", *payload.Optional)
+	}
+
+	for _, input := range []string{`{"quantity": 1.2345}`, `{"quantity": 1e3}`, `{"quantity": true}`} {
+		if err := json.Unmarshal([]byte(input), &payload); err == nil {
+			t.Errorf("unmarshal %s succeeded, want error", input)
+		}
+	}
+
+	data, err := json.Marshal(struct {
+		Quantity Quantity `json:"quantity"`
+	}{Quantity: 350})
+	if err != nil || string(data) != `{"quantity":0.35}` {
+		t.Errorf("marshal = %s, %v, want {\"quantity\":0.35}", data, err)
+	}
+}
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+const (
+	RefundReasonCustomerRequest = "customer_request"
+	RefundReasonDamaged         = "damaged"
+	RefundReasonWrongItem       = "wrong_item"
+	RefundReasonExpired         = "expired"
+	RefundReasonOther           = "other"
+)
+
+// Refund adalah dokumen pengembalian barang/uang yang terhubung ke sebuah
+// transaksi. Satu transaksi bisa punya beberapa refund parsial selama
+// jumlah unit yang di-refund per baris tidak melebihi yang terjual.
+type Refund struct {
+	ID            int          `json:"id"`
+	TransactionID int          `json:"transaction_id"`
+	UserID        *int         `json:"user_id,omitempty"`
+	ShiftID       *int         `json:"shift_id,omitempty"`
+	Reason        string       `json:"reason"`
+	Note          string       `json:"note,omitempty"`
+	Method        string       `json:"method"`
+	Restock       bool         `json:"restock"`
+	TotalAmount   int          `json:"total_amount"`
+	CreatedAt     time.Time    `json:"created_at"`
+	Items         []RefundItem `json:"items"`
+}
+
+// RefundItem adalah baris transaksi yang dikembalikan. Amount dan CostAmount
+// adalah porsi TotalAmount dan HPP baris transaksi untuk Quantity unit ini.
+type RefundItem struct {
+	ID                int      `json:"id"`
+	RefundID          int      `json:"refund_id"`
+	TransactionItemID int      `json:"transaction_item_id"`
+	ProductID         int      `json:"product_id"`
+	ProductName       string   `json:"product_name"`
+	VariantID         *int     `json:"variant_id,omitempty"`
+	Quantity          Quantity `json:"quantity"`
+	Amount            int      `json:"amount"`
+	CostAmount        int      `json:"cost_amount"`
+}
+
+// RefundItemRequest adalah baris yang ingin dikembalikan
+type RefundItemRequest struct {
+	TransactionItemID int      `json:"transaction_item_id"`
+	Quantity          Quantity `json:"quantity"`
+}
+
+// RefundRequest adalah payload untuk POST /api/transaction/{id}/refund.
+// Items kosong berarti refund penuh untuk semua unit yang belum di-refund.
+// Restock mengembalikan unit yang di-refund ke stok produk.
+type RefundRequest struct {
+	Reason  string              `json:"reason"`
+	Note    string              `json:"note"`
+	Method  string              `json:"method"`
+	Restock bool                `json:"restock"`
+	Items   []RefundItemRequest `json:"items"`
+}
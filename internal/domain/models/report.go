@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// SalesReport adalah rekap penjualan, HPP dan margin kotor untuk satu
+// periode. NetSales adalah penjualan setelah diskon tanpa pajak dan service
+// charge; ServiceCharge dicatat terpisah sehingga NetSales + ServiceCharge
+// sama dengan total transaksi dikurangi pajak. Refund dikurangkan pada
+// periode transaksi asalnya. COGS adalah HPP barang terjual dikurangi HPP
+// barang refund yang kembali ke stok.
+type SalesReport struct {
+	From             time.Time      `json:"from"`
+	To               time.Time      `json:"to"`
+	TransactionCount int            `json:"transaction_count"`
+	NetSales         int            `json:"net_sales"`
+	ServiceCharge    int            `json:"service_charge"`
+	COGS             int            `json:"cogs"`
+	GrossProfit      int            `json:"gross_profit"`
+	MarginBps        int            `json:"margin_bps"`
+	Products         []ProductSales `json:"products"`
+}
+
+// ProductSales adalah baris SalesReport per produk, Quantity dalam satuan
+// dasar setelah dikurangi unit yang di-refund
+type ProductSales struct {
+	ProductID     int      `json:"product_id"`
+	ProductName   string   `json:"product_name"`
+	Quantity      Quantity `json:"quantity"`
+	NetSales      int      `json:"net_sales"`
+	ServiceCharge int      `json:"service_charge"`
+	COGS          int      `json:"cogs"`
+	GrossProfit   int      `json:"gross_profit"`
+	MarginBps     int      `json:"margin_bps"`
+}
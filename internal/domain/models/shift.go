@@ -0,0 +1,85 @@
+package models
+
+import "time"
+
+const (
+	ShiftStatusOpen   = "open"
+	ShiftStatusClosed = "closed"
+
+	CashEventIn  = "cash_in"
+	CashEventOut = "cash_out"
+)
+
+// Shift adalah sesi kerja kasir dengan laci kas sendiri. Saat ditutup,
+// ExpectedCash dibandingkan dengan CountedCash dan selisihnya disimpan di Variance.
+type Shift struct {
+	ID           int        `json:"id"`
+	UserID       int        `json:"user_id"`
+	Status       string     `json:"status"`
+	OpeningFloat int        `json:"opening_float"`
+	ExpectedCash *int       `json:"expected_cash,omitempty"`
+	CountedCash  *int       `json:"counted_cash,omitempty"`
+	Variance     *int       `json:"variance,omitempty"`
+	OpeningNote  string     `json:"opening_note,omitempty"`
+	ClosingNote  string     `json:"closing_note,omitempty"`
+	OpenedAt     time.Time  `json:"opened_at"`
+	ClosedAt     *time.Time `json:"closed_at,omitempty"`
+}
+
+// ShiftCashEvent adalah uang masuk/keluar laci di luar penjualan,
+// misalnya tambahan uang kembalian, petty cash atau setoran ke brankas
+type ShiftCashEvent struct {
+	ID        int       `json:"id"`
+	ShiftID   int       `json:"shift_id"`
+	Type      string    `json:"type"`
+	Amount    int       `json:"amount"`
+	Note      string    `json:"note,omitempty"`
+	UserID    *int      `json:"user_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ShiftPaymentSummary adalah total pembayaran per metode dalam satu shift
+type ShiftPaymentSummary struct {
+	Method string `json:"method"`
+	Count  int    `json:"count"`
+	Amount int    `json:"amount"`
+}
+
+// ShiftReport adalah rekap shift: penjualan, pergerakan kas dan
+// perbandingan kas yang seharusnya ada di laci dengan hasil hitung kasir.
+// ExpectedCash = OpeningFloat + CashSales - CashRefunds + CashIn - CashOut.
+type ShiftReport struct {
+	Shift            Shift                 `json:"shift"`
+	TransactionCount int                   `json:"transaction_count"`
+	SalesTotal       int                   `json:"sales_total"`
+	Payments         []ShiftPaymentSummary `json:"payments"`
+	CashSales        int                   `json:"cash_sales"`
+	RefundCount      int                   `json:"refund_count"`
+	RefundTotal      int                   `json:"refund_total"`
+	CashRefunds      int                   `json:"cash_refunds"`
+	CashIn           int                   `json:"cash_in"`
+	CashOut          int                   `json:"cash_out"`
+	ExpectedCash     int                   `json:"expected_cash"`
+	CountedCash      *int                  `json:"counted_cash,omitempty"`
+	Variance         *int                  `json:"variance,omitempty"`
+	CashEvents       []ShiftCashEvent      `json:"cash_events"`
+}
+
+// OpenShiftRequest adalah payload untuk POST /api/shift/open
+type OpenShiftRequest struct {
+	OpeningFloat int    `json:"opening_float"`
+	Note         string `json:"note"`
+}
+
+// CashEventRequest adalah payload untuk POST /api/shift/{id}/cash
+type CashEventRequest struct {
+	Type   string `json:"type"`
+	Amount int    `json:"amount"`
+	Note   string `json:"note"`
+}
+
+// CloseShiftRequest adalah payload untuk POST /api/shift/{id}/close
+type CloseShiftRequest struct {
+	CountedCash *int   `json:"counted_cash"`
+	Note        string `json:"note"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+const (
+	StockReasonSale       = "sale"
+	StockReasonRestock    = "restock"
+	StockReasonAdjustment = "adjustment"
+	StockReasonReturn     = "return"
+	StockReasonDamage     = "damage"
+)
+
+// StockMovement adalah satu baris ledger perubahan stok produk. ProductName
+// dan VariantName disimpan saat movement dicatat supaya ledger tetap terbaca
+// setelah produk atau variannya dihapus.
+type StockMovement struct {
+	ID          int      `json:"id"`
+	ProductID   int      `json:"product_id"`
+	ProductName string   `json:"product_name"`
+	VariantID   *int     `json:"variant_id,omitempty"`
+	VariantName string   `json:"variant_name,omitempty"`
+	Delta       Quantity `json:"delta"`
+	StockAfter  Quantity `json:"stock_after"`
+	Reason      string   `json:"reason"`
+	ReferenceID string   `json:"reference_id,omitempty"`
+	Note        string   `json:"note,omitempty"`
+	// UnitCost adalah harga pokok per satuan dasar barang masuk; nil berarti
+	// memakai harga pokok produk saat ini. CostAmount adalah perubahan nilai
+	// persediaan, negatif untuk barang keluar (HPP), dan selalu dihitung oleh
+	// repository.
+	UnitCost   *int `json:"unit_cost,omitempty"`
+	CostAmount int  `json:"cost_amount"`
+	// InboundValue diisi pemanggil jika nilai barang masuk sudah diketahui
+	// persis (nilai yang dibayar atau HPP yang dikembalikan) sehingga tidak
+	// dihitung ulang dari UnitCost yang sudah dibulatkan. nil berarti
+	// Delta x UnitCost; nol tetap berarti barang masuk tanpa nilai.
+	InboundValue *int      `json:"-"`
+	UserID       *int      `json:"user_id,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// StockAdjustmentRequest adalah payload untuk POST /api/product/{id}/stock.
+// Delta positif menambah stok, negatif mengurangi stok. VariantID wajib
+// untuk produk bervarian. Delta dan UnitCost dalam satuan Unit (default
+// satuan dasar); UnitCost hanya untuk delta positif.
+type StockAdjustmentRequest struct {
+	VariantID   *int     `json:"variant_id,omitempty"`
+	VariantName string   `json:"variant_name,omitempty"`
+	Delta       Quantity `json:"delta"`
+	Unit        string   `json:"unit,omitempty"`
+	UnitCost    *int     `json:"unit_cost,omitempty"`
+	Reason      string   `json:"reason"`
+	ReferenceID string   `json:"reference_id"`
+	Note        string   `json:"note"`
+}
+
+// StockMovementList adalah riwayat stok beserta metadata pagination
+type StockMovementList struct {
+	Movements []StockMovement
+	Meta      PaginationMeta
+}
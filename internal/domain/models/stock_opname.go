@@ -0,0 +1,107 @@
+package models
+
+import "time"
+
+const (
+	StockOpnameStatusOpen      = "open"
+	StockOpnameStatusApproved  = "approved"
+	StockOpnameStatusCancelled = "cancelled"
+)
+
+// StockOpname adalah satu sesi hitung fisik stok. Saat dibuka stok sistem
+// semua produk (atau satu kategori) di-snapshot; selama open stok produk
+// tersebut dibekukan dan penghitung mengirim hasil hitung; saat approve
+// setiap selisih diposting sebagai adjustment di ledger stok.
+type StockOpname struct {
+	ID         int        `json:"id"`
+	Status     string     `json:"status"`
+	Note       string     `json:"note,omitempty"`
+	CategoryID *int       `json:"category_id,omitempty"`
+	CreatedBy  *int       `json:"created_by,omitempty"`
+	ApprovedBy *int       `json:"approved_by,omitempty"`
+	ApprovedAt *time.Time `json:"approved_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	// ItemCount adalah jumlah item yang di-snapshot, CountedItems yang sudah
+	// punya hasil hitung
+	ItemCount    int `json:"item_count"`
+	CountedItems int `json:"counted_items"`
+}
+
+// StockOpnameItem adalah satu produk atau varian dalam sesi stock opname.
+// Expected adalah stok sistem saat sesi dibuka dan Counted jumlah hasil
+// hitung semua penghitung (nil jika belum dihitung). Variance = Counted -
+// Expected, VarianceValue dinilai dengan harga pokok saat sesi dibuka.
+type StockOpnameItem struct {
+	ProductID       int       `json:"product_id"`
+	ProductName     string    `json:"product_name"`
+	VariantID       *int      `json:"variant_id,omitempty"`
+	VariantName     string    `json:"variant_name,omitempty"`
+	Unit            string    `json:"unit"`
+	Expected        Quantity  `json:"expected"`
+	Counted         *Quantity `json:"counted"`
+	Counters        int       `json:"counters"`
+	Variance        Quantity  `json:"variance"`
+	UnitCost        int       `json:"unit_cost"`
+	VarianceValue   int       `json:"variance_value"`
+	StockMovementID *int      `json:"stock_movement_id,omitempty"`
+}
+
+// StockOpnameVariance adalah laporan selisih stock opname. Items berisi item
+// yang hasil hitungnya berbeda dari snapshot, Uncounted item yang belum
+// dihitung dan tidak akan disesuaikan saat approve.
+type StockOpnameVariance struct {
+	Opname           StockOpname       `json:"opname"`
+	DiscrepancyItems int               `json:"discrepancy_items"`
+	SurplusValue     int               `json:"surplus_value"`
+	ShortageValue    int               `json:"shortage_value"`
+	NetValue         int               `json:"net_value"`
+	Items            []StockOpnameItem `json:"items"`
+	Uncounted        []StockOpnameItem `json:"uncounted"`
+}
+
+// CreateStockOpnameRequest adalah payload untuk POST /api/stock-opnames.
+// CategoryID membatasi snapshot ke satu kategori, kosong berarti semua produk.
+type CreateStockOpnameRequest struct {
+	Note       string `json:"note"`
+	CategoryID *int   `json:"category_id,omitempty"`
+}
+
+// StockOpnameCountRequest adalah payload untuk POST /api/stock-opnames/{id}/counts.
+// Quantity menimpa hasil hitung penghitung yang sedang login untuk item
+// tersebut, hasil hitung penghitung lain tidak berubah.
+type StockOpnameCountRequest struct {
+	Counts []StockOpnameCountLine `json:"counts"`
+}
+
+// StockOpnameCountLine adalah hasil hitung satu produk atau varian. Unit
+// kosong berarti satuan dasar produk.
+type StockOpnameCountLine struct {
+	ProductID int      `json:"product_id"`
+	VariantID *int     `json:"variant_id,omitempty"`
+	Quantity  Quantity `json:"quantity"`
+	Unit      string   `json:"unit,omitempty"`
+}
+
+// StockOpnameScanRequest adalah payload untuk POST /api/stock-opnames/{id}/scan.
+// Setiap scan menambah hasil hitung penghitung sebanyak Quantity (default 1,
+// atau berat di label timbangan).
+type StockOpnameScanRequest struct {
+	Barcode  string    `json:"barcode"`
+	Quantity *Quantity `json:"quantity,omitempty"`
+}
+
+// StockOpnameCount adalah hasil hitung penghitung yang sedang login untuk
+// satu item setelah count atau scan
+type StockOpnameCount struct {
+	ProductID int      `json:"product_id"`
+	VariantID *int     `json:"variant_id,omitempty"`
+	UserID    int      `json:"user_id"`
+	Quantity  Quantity `json:"quantity"`
+}
+
+// StockOpnameList adalah daftar sesi stock opname beserta metadata pagination
+type StockOpnameList struct {
+	Opnames []StockOpname
+	Meta    PaginationMeta
+}
@@ -0,0 +1,84 @@
+package models
+
+import "time"
+
+// Transaction adalah header penjualan hasil checkout.
+//
+// TotalAmount adalah grand total yang dibayar pelanggan:
+// SubtotalAmount - DiscountAmount + ServiceChargeAmount, ditambah TaxAmount
+// jika harga belum termasuk pajak. Jika PricesIncludeTax true, TaxAmount
+// hanya porsi pajak yang sudah ada di dalam harga.
+type Transaction struct {
+	ID                  int                `json:"id"`
+	UserID              *int               `json:"user_id,omitempty"`
+	ShiftID             *int               `json:"shift_id,omitempty"`
+	OrderID             *int               `json:"order_id,omitempty"`
+	SubtotalAmount      int                `json:"subtotal_amount"`
+	DiscountAmount      int                `json:"discount_amount"`
+	ServiceChargeAmount int                `json:"service_charge_amount"`
+	TaxAmount           int                `json:"tax_amount"`
+	PricesIncludeTax    bool               `json:"prices_include_tax"`
+	TotalAmount         int                `json:"total_amount"`
+	RefundedAmount      int                `json:"refunded_amount"`
+	PaidAmount          int                `json:"paid_amount"`
+	ChangeAmount        int                `json:"change_amount"`
+	CreatedAt           time.Time          `json:"created_at"`
+	Details             []TransactionItem  `json:"details"`
+	Payments            []Payment          `json:"payments"`
+	Promotions          []AppliedPromotion `json:"promotions"`
+	Taxes               []TaxLine          `json:"taxes"`
+}
+
+// TaxLine adalah rincian pajak untuk satu tarif. TaxableAmount adalah dasar
+// pengenaan pajak (DPP), sudah termasuk service charge.
+type TaxLine struct {
+	RateBps       int `json:"rate_bps"`
+	TaxableAmount int `json:"taxable_amount"`
+	TaxAmount     int `json:"tax_amount"`
+}
+
+// TransactionItem adalah baris item dari sebuah transaksi. Discount mencakup
+// promo item/kategori dan porsi promo cart untuk baris ini, sehingga
+// Subtotal = Price*Quantity - Discount. TotalAmount adalah Subtotal ditambah
+// porsi service charge dan pajak baris ini, yaitu nilai yang dikembalikan
+// jika seluruh baris di-refund. ServiceChargeAmount dan TaxAmount adalah
+// porsi baris ini dari service charge dan pajak transaksi.
+type TransactionItem struct {
+	ID                  int      `json:"id"`
+	TransactionID       int      `json:"transaction_id"`
+	ProductID           int      `json:"product_id"`
+	ProductName         string   `json:"product_name"`
+	VariantID           *int     `json:"variant_id,omitempty"`
+	VariantName         string   `json:"variant_name,omitempty"`
+	Quantity            Quantity `json:"quantity"`
+	Price               int      `json:"price"`
+	Discount            int      `json:"discount"`
+	Subtotal            int      `json:"subtotal"`
+	TaxRateBps          int      `json:"tax_rate_bps"`
+	ServiceChargeAmount int      `json:"service_charge_amount"`
+	TaxAmount           int      `json:"tax_amount"`
+	TotalAmount         int      `json:"total_amount"`
+	// RefundedQuantity adalah jumlah unit yang sudah di-refund
+	RefundedQuantity Quantity `json:"refunded_quantity"`
+}
+
+// CheckoutItem adalah item keranjang yang dikirim oleh kasir. VariantID
+// wajib untuk produk bervarian dan harus kosong untuk produk tanpa varian.
+// Quantity dalam satuan Unit (default satuan dasar produk) dan dikonversi ke
+// satuan dasar sebelum dihitung harganya.
+type CheckoutItem struct {
+	ProductID int      `json:"product_id"`
+	VariantID *int     `json:"variant_id,omitempty"`
+	Quantity  Quantity `json:"quantity"`
+	Unit      string   `json:"unit,omitempty"`
+}
+
+// CheckoutRequest adalah payload untuk endpoint checkout. Jika OrderID diisi,
+// item diambil dari order draft tersebut dan Items harus kosong; PromoCode
+// kosong berarti memakai kode promo yang tersimpan di order.
+type CheckoutRequest struct {
+	OrderID   *int             `json:"order_id,omitempty"`
+	Items     []CheckoutItem   `json:"items"`
+	Payments  []PaymentRequest `json:"payments"`
+	PromoCode string           `json:"promo_code,omitempty"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultBaseUnit dipakai jika produk dibuat tanpa base_unit
+const DefaultBaseUnit = "pcs"
+
+// ProductUnit adalah satuan beli/jual tambahan produk. Factor adalah jumlah
+// satuan dasar dalam satu satuan ini, mis. box dengan factor 24 = 24 pcs.
+type ProductUnit struct {
+	Name   string `json:"name"`
+	Factor int    `json:"factor"`
+}
+
+// UnitFactor mengembalikan factor satuan unit terhadap satuan dasar produk.
+// Unit kosong atau sama dengan BaseUnit berarti satuan dasar (factor 1).
+// Nama satuan dicocokkan tanpa membedakan huruf besar/kecil.
+func (p *Product) UnitFactor(unit string) (int, error) {
+	unit = strings.TrimSpace(unit)
+	if unit == "" || strings.EqualFold(unit, p.BaseUnit) {
+		return 1, nil
+	}
+	names := []string{p.BaseUnit}
+	for _, u := range p.Units {
+		if strings.EqualFold(u.Name, unit) {
+			return u.Factor, nil
+		}
+		names = append(names, u.Name)
+	}
+	return 0, NewValidationError("unit", fmt.Sprintf("unknown unit %q for %s, valid units: %s", unit, p.Name, strings.Join(names, ", ")))
+}
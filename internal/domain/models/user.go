@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+const (
+	RoleAdmin   = "admin"
+	RoleCashier = "cashier"
+)
+
+// User adalah akun yang bisa login ke aplikasi kasir
+type User struct {
+	ID           int       `json:"id"`
+	Username     string    `json:"username"`
+	PasswordHash string    `json:"-"`
+	Role         string    `json:"role"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// AuthUser adalah identitas user yang diambil dari access token
+type AuthUser struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+// LoginRequest adalah payload untuk endpoint login
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// RefreshRequest adalah payload untuk endpoint refresh token
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// CreateUserRequest adalah payload untuk membuat user baru
+type CreateUserRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Role     string `json:"role"`
+}
+
+// AuthToken adalah pasangan token yang dikembalikan setelah login/refresh
+type AuthToken struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	User         *User  `json:"user"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import "strings"
+
+// ProductOption adalah satu sumbu varian produk, mis. size dengan nilai S, M, L
+type ProductOption struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// ProductVariant adalah satu kombinasi nilai opsi yang dijual dengan SKU,
+// barcode, harga dan stok sendiri. OptionValues berurutan sesuai
+// Product.Options. Varian yang kombinasinya sudah tidak ada di opsi produk
+// dinonaktifkan, bukan dihapus, supaya riwayat penjualan tetap utuh.
+type ProductVariant struct {
+	ID           int      `json:"id"`
+	ProductID    int      `json:"product_id"`
+	OptionValues []string `json:"option_values"`
+	Name         string   `json:"name"`
+	SKU          string   `json:"sku,omitempty"`
+	Barcodes     []string `json:"barcodes"`
+	Price        int      `json:"price"`
+	Stock        Quantity `json:"stock"`
+	Active       bool     `json:"active"`
+	// Margin dan MarginBps dihitung dari harga varian dikurangi harga pokok produk
+	Margin    int `json:"margin"`
+	MarginBps int `json:"margin_bps"`
+}
+
+// VariantName menyusun nama tampilan varian, mis. "L / Merah"
+func VariantName(optionValues []string) string {
+	return strings.Join(optionValues, " / ")
+}
+
+// SetProductOptionsRequest adalah payload untuk PUT /api/product/{id}/variants.
+// Varian dibuat untuk setiap kombinasi nilai opsi dengan harga awal sama
+// dengan harga produk.
+type SetProductOptionsRequest struct {
+	Options []ProductOption `json:"options"`
+}
+
+// UpdateVariantRequest adalah payload untuk PUT /api/product/{id}/variants/{variant_id}.
+// Field yang tidak dikirim tidak diubah; Barcodes kosong ([]) menghapus semua barcode varian.
+type UpdateVariantRequest struct {
+	SKU      *string  `json:"sku,omitempty"`
+	Price    *int     `json:"price,omitempty"`
+	Active   *bool    `json:"active,omitempty"`
+	Barcodes []string `json:"barcodes,omitempty"`
+}
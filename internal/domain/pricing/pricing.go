@@ -0,0 +1,333 @@
+// Package pricing menghitung harga keranjang beserta promo yang berlaku.
+// Semua fungsi di sini murni (tanpa database, tanpa jam sistem) sehingga
+// hasilnya hanya bergantung pada input dan mudah ditelusuri.
+package pricing
+
+import (
+	"kasir-api/internal/domain/models"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Line adalah satu baris keranjang dengan harga satuan saat ini
+type Line struct {
+	ProductID   int
+	ProductName string
+	// VariantID dan VariantName diisi untuk produk bervarian; promo item
+	// tetap dicocokkan per produk sehingga berlaku untuk semua variannya
+	VariantID   *int
+	VariantName string
+	CategoryID  int
+	Quantity    models.Quantity
+	UnitPrice   int
+	// TaxRateBps adalah tarif efektif hasil ResolveTaxRate
+	TaxRateBps int
+}
+
+// Cart adalah input perhitungan harga. Now harus sudah dalam zona waktu toko
+// karena jam happy hour dibandingkan dengan jam lokal.
+type Cart struct {
+	Lines     []Line
+	PromoCode string
+	Now       time.Time
+}
+
+// LineResult adalah hasil harga per baris. Discount = LineDiscount + CartDiscount,
+// Net = Gross - Discount. ServiceCharge dan TaxAmount adalah porsi baris ini
+// dari service charge dan pajak keranjang, sedangkan Total adalah nilai yang
+// dibayar pelanggan untuk baris ini (jumlah Total semua baris = Result.Total).
+type LineResult struct {
+	Line
+	Gross         int
+	LineDiscount  int
+	CartDiscount  int
+	Discount      int
+	Net           int
+	ServiceCharge int
+	TaxAmount     int
+	Total         int
+}
+
+// Result adalah hasil perhitungan harga keranjang. Total adalah grand total
+// yang harus dibayar, lihat applyTaxes.
+type Result struct {
+	Lines            []LineResult
+	Subtotal         int
+	DiscountAmount   int
+	ServiceCharge    int
+	TaxAmount        int
+	PricesIncludeTax bool
+	Total            int
+	Taxes            []models.TaxLine
+	Applied          []models.AppliedPromotion
+	// CodeApplied bernilai true jika PromoCode dari Cart terpakai
+	CodeApplied bool
+}
+
+// Calculate menghitung harga keranjang dengan aturan:
+//
+//  1. Setiap baris mendapat paling banyak satu promo item/kategori. Promo
+//     berkode yang cocok didahulukan, selain itu dipilih potongan terbesar.
+//  2. Setelah itu paling banyak satu promo cart dihitung dari total setelah
+//     potongan baris, dengan aturan pemilihan yang sama, lalu dibagi ke
+//     setiap baris secara proporsional.
+//  3. MinSpend dibandingkan dengan subtotal sebelum diskon.
+//  4. Service charge dan pajak dihitung dari harga setelah diskon (applyTaxes).
+//
+// Potongan tidak pernah membuat harga baris menjadi negatif.
+func Calculate(cart Cart, promotions []models.Promotion, tax TaxSettings) Result {
+	result := Result{
+		Lines:   make([]LineResult, len(cart.Lines)),
+		Applied: make([]models.AppliedPromotion, 0),
+	}
+
+	for i, line := range cart.Lines {
+		gross := line.Quantity.Amount(line.UnitPrice)
+		result.Lines[i] = LineResult{Line: line, Gross: gross}
+		result.Subtotal += gross
+	}
+
+	eligible := make([]models.Promotion, 0, len(promotions))
+	for _, p := range promotions {
+		if isEligible(p, cart, result.Subtotal) {
+			eligible = append(eligible, p)
+		}
+	}
+
+	applied := make(map[int]*models.AppliedPromotion)
+	order := make([]int, 0)
+	apply := func(p models.Promotion, amount int) {
+		if amount <= 0 {
+			return
+		}
+		if a, ok := applied[p.ID]; ok {
+			a.DiscountAmount += amount
+			return
+		}
+		applied[p.ID] = &models.AppliedPromotion{
+			PromotionID:    p.ID,
+			Name:           p.Name,
+			Code:           p.Code,
+			DiscountAmount: amount,
+		}
+		order = append(order, p.ID)
+		if p.Code != "" {
+			result.CodeApplied = true
+		}
+	}
+
+	// 1. promo item/kategori per baris
+	for i := range result.Lines {
+		line := &result.Lines[i]
+		best, amount := bestPromotion(eligible, func(p models.Promotion) (int, bool) {
+			if !appliesToLine(p, line.Line) {
+				return 0, false
+			}
+			return lineDiscount(p, line.Line), true
+		})
+		if best != nil {
+			line.LineDiscount = amount
+			apply(*best, amount)
+		}
+	}
+
+	afterLines := 0
+	for _, line := range result.Lines {
+		afterLines += line.Gross - line.LineDiscount
+	}
+
+	// 2. promo cart, dibagi proporsional ke setiap baris
+	best, cartAmount := bestPromotion(eligible, func(p models.Promotion) (int, bool) {
+		if p.Scope != models.PromotionScopeCart {
+			return 0, false
+		}
+		return cartDiscount(p, afterLines), true
+	})
+	if best != nil && cartAmount > 0 {
+		allocateCartDiscount(result.Lines, cartAmount)
+		apply(*best, cartAmount)
+	}
+
+	for i := range result.Lines {
+		line := &result.Lines[i]
+		line.Discount = line.LineDiscount + line.CartDiscount
+		line.Net = line.Gross - line.Discount
+		result.DiscountAmount += line.Discount
+	}
+
+	for _, id := range order {
+		result.Applied = append(result.Applied, *applied[id])
+	}
+
+	applyTaxes(&result, tax)
+
+	return result
+}
+
+// bestPromotion memilih promo dengan potongan terbesar di antara promo yang
+// relevan. Promo berkode selalu menang atas promo otomatis, dan jika potongan
+// sama dipilih ID terkecil supaya hasilnya deterministik.
+func bestPromotion(promotions []models.Promotion, discount func(models.Promotion) (int, bool)) (*models.Promotion, int) {
+	var best *models.Promotion
+	bestAmount := 0
+	for i := range promotions {
+		p := &promotions[i]
+		amount, ok := discount(*p)
+		if !ok || amount <= 0 {
+			continue
+		}
+		if best == nil || better(p, amount, best, bestAmount) {
+			best, bestAmount = p, amount
+		}
+	}
+	return best, bestAmount
+}
+
+func better(p *models.Promotion, amount int, current *models.Promotion, currentAmount int) bool {
+	pCoded, currentCoded := p.Code != "", current.Code != ""
+	if pCoded != currentCoded {
+		return pCoded
+	}
+	if amount != currentAmount {
+		return amount > currentAmount
+	}
+	return p.ID < current.ID
+}
+
+func isEligible(p models.Promotion, cart Cart, subtotal int) bool {
+	if !p.Active {
+		return false
+	}
+	if p.Code != "" && !strings.EqualFold(p.Code, strings.TrimSpace(cart.PromoCode)) {
+		return false
+	}
+	if p.StartsAt != nil && cart.Now.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !cart.Now.Before(*p.EndsAt) {
+		return false
+	}
+	if !withinDailyWindow(p.DailyStartTime, p.DailyEndTime, cart.Now) {
+		return false
+	}
+	return subtotal >= p.MinSpend
+}
+
+// withinDailyWindow mengecek jam berlaku harian "HH:MM"-"HH:MM" (akhir eksklusif).
+// Jendela yang melewati tengah malam (mis. 22:00-02:00) juga didukung.
+func withinDailyWindow(start, end string, now time.Time) bool {
+	if start == "" || end == "" {
+		return true
+	}
+	startMin, ok1 := ParseClock(start)
+	endMin, ok2 := ParseClock(end)
+	if !ok1 || !ok2 {
+		return false
+	}
+
+	current := now.Hour()*60 + now.Minute()
+	if startMin <= endMin {
+		return current >= startMin && current < endMin
+	}
+	return current >= startMin || current < endMin
+}
+
+// ParseClock mengubah "HH:MM" menjadi menit sejak tengah malam
+func ParseClock(value string) (int, bool) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+func appliesToLine(p models.Promotion, line Line) bool {
+	switch p.Scope {
+	case models.PromotionScopeItem:
+		return p.ProductID != nil && *p.ProductID == line.ProductID
+	case models.PromotionScopeCategory:
+		return p.CategoryID != nil && *p.CategoryID == line.CategoryID
+	default:
+		return false
+	}
+}
+
+func lineDiscount(p models.Promotion, line Line) int {
+	gross := line.Quantity.Amount(line.UnitPrice)
+	var amount int
+	switch p.Type {
+	case models.PromotionTypePercentage:
+		amount = gross * p.Value / 100
+	case models.PromotionTypeFixed:
+		amount = line.Quantity.Amount(min(p.Value, line.UnitPrice))
+	case models.PromotionTypeBuyXGetY:
+		group := p.BuyQuantity + p.GetQuantity
+		if p.BuyQuantity <= 0 || p.GetQuantity <= 0 {
+			return 0
+		}
+		// hanya unit utuh yang dihitung, pecahan barang timbang tidak ikut
+		free := (line.Quantity.WholeUnits() / group) * p.GetQuantity
+		amount = free * line.UnitPrice
+	}
+	return min(amount, gross)
+}
+
+func cartDiscount(p models.Promotion, base int) int {
+	var amount int
+	switch p.Type {
+	case models.PromotionTypePercentage:
+		amount = base * p.Value / 100
+	case models.PromotionTypeFixed:
+		amount = p.Value
+	}
+	return min(amount, base)
+}
+
+// allocateCartDiscount membagi potongan cart ke setiap baris sebanding dengan
+// nilai baris setelah potongan baris
+func allocateCartDiscount(lines []LineResult, amount int) {
+	weights := make([]int, len(lines))
+	for i := range lines {
+		weights[i] = lines[i].Gross - lines[i].LineDiscount
+	}
+	for i, share := range allocate(amount, weights) {
+		lines[i].CartDiscount = share
+	}
+}
+
+// allocate membagi amount sebanding dengan weights memakai metode sisa
+// terbesar: setiap bagian dibulatkan ke bawah, lalu sisa pembulatan diberikan
+// satu per satu ke bagian dengan pecahan terbesar (seri: indeks terkecil).
+// Jumlah hasil selalu sama dengan amount, dan jika amount <= total weights
+// tidak ada bagian yang melebihi weight-nya.
+func allocate(amount int, weights []int) []int {
+	shares := make([]int, len(weights))
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+	if total <= 0 || amount <= 0 {
+		return shares
+	}
+
+	remainders := make([]int, len(weights))
+	allocated := 0
+	for i, w := range weights {
+		shares[i] = amount * w / total
+		remainders[i] = amount * w % total
+		allocated += shares[i]
+	}
+
+	indexes := make([]int, len(weights))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(a, b int) bool {
+		return remainders[indexes[a]] > remainders[indexes[b]]
+	})
+	for _, i := range indexes[:amount-allocated] {
+		shares[i]++
+	}
+	return shares
+}
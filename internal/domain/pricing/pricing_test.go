@@ -0,0 +1,210 @@
+package pricing
+
+import (
+	"kasir-api/internal/domain/models"
+	"testing"
+	"time"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestCalculateBestPromotion(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	line := Line{ProductID: 1, CategoryID: 10, Quantity: models.WholeQuantity(2), UnitPrice: 10000}
+
+	percent := func(id, value int, code string) models.Promotion {
+		return models.Promotion{ID: id, Name: "promo", Type: models.PromotionTypePercentage, Scope: models.PromotionScopeItem,
+			ProductID: intPtr(1), Value: value, Code: code, Active: true}
+	}
+
+	tests := []struct {
+		name       string
+		promoCode  string
+		promotions []models.Promotion
+		wantID     int
+		wantAmount int
+	}{
+		{
+			name:       "largest automatic discount wins",
+			promotions: []models.Promotion{percent(1, 10, ""), percent(2, 25, "")},
+			wantID:     2,
+			wantAmount: 5000,
+		},
+		{
+			name:       "matching code beats larger automatic discount",
+			promoCode:  " hemat ",
+			promotions: []models.Promotion{percent(1, 50, ""), percent(2, 5, "HEMAT")},
+			wantID:     2,
+			wantAmount: 1000,
+		},
+		{
+			name:       "code that was not entered is ignored",
+			promotions: []models.Promotion{percent(1, 10, ""), percent(2, 50, "HEMAT")},
+			wantID:     1,
+			wantAmount: 2000,
+		},
+		{
+			name:       "tie goes to the lowest ID",
+			promotions: []models.Promotion{percent(7, 10, ""), percent(3, 10, ""), percent(5, 10, "")},
+			wantID:     3,
+			wantAmount: 2000,
+		},
+		{
+			name: "category promotion competes with item promotion",
+			promotions: []models.Promotion{percent(1, 10, ""), {ID: 2, Name: "kategori", Type: models.PromotionTypeFixed,
+				Scope: models.PromotionScopeCategory, CategoryID: intPtr(10), Value: 1500, Active: true}},
+			wantID:     2,
+			wantAmount: 3000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cart := Cart{Lines: []Line{line}, PromoCode: tt.promoCode, Now: now}
+			result := Calculate(cart, tt.promotions, TaxSettings{})
+
+			if len(result.Applied) != 1 {
+				t.Fatalf("applied promotions = %+v, want exactly one", result.Applied)
+			}
+			if got := result.Applied[0]; got.PromotionID != tt.wantID || got.DiscountAmount != tt.wantAmount {
+				t.Errorf("applied = promotion %d discount %d, want promotion %d discount %d",
+					got.PromotionID, got.DiscountAmount, tt.wantID, tt.wantAmount)
+			}
+			if result.Total != 20000-tt.wantAmount {
+				t.Errorf("total = %d, want %d", result.Total, 20000-tt.wantAmount)
+			}
+		})
+	}
+}
+
+func TestCalculateCartPromotionAllocation(t *testing.T) {
+	cart := Cart{
+		Lines: []Line{
+			{ProductID: 1, Quantity: models.WholeQuantity(1), UnitPrice: 10000},
+			{ProductID: 2, Quantity: models.WholeQuantity(1), UnitPrice: 10000},
+			{ProductID: 3, Quantity: models.WholeQuantity(1), UnitPrice: 10000},
+		},
+		Now: time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC),
+	}
+	promotions := []models.Promotion{{ID: 1, Type: models.PromotionTypeFixed, Scope: models.PromotionScopeCart, Value: 1000, Active: true}}
+
+	result := Calculate(cart, promotions, TaxSettings{})
+
+	want := []int{334, 333, 333}
+	sum := 0
+	for i, line := range result.Lines {
+		if line.CartDiscount != want[i] {
+			t.Errorf("line %d cart discount = %d, want %d", i, line.CartDiscount, want[i])
+		}
+		sum += line.Discount
+	}
+	if sum != 1000 || result.DiscountAmount != 1000 {
+		t.Errorf("line discounts = %d, discount amount = %d, want 1000", sum, result.DiscountAmount)
+	}
+}
+
+func TestLineDiscountBuyXGetY(t *testing.T) {
+	promotion := models.Promotion{Type: models.PromotionTypeBuyXGetY, Scope: models.PromotionScopeItem, ProductID: intPtr(1),
+		BuyQuantity: 2, GetQuantity: 1}
+
+	tests := []struct {
+		name     string
+		quantity models.Quantity
+		want     int
+	}{
+		{name: "less than one group", quantity: models.WholeQuantity(2), want: 0},
+		{name: "exactly one group", quantity: models.WholeQuantity(3), want: 5000},
+		{name: "one group and a remainder", quantity: models.WholeQuantity(5), want: 5000},
+		{name: "two groups and a remainder", quantity: models.WholeQuantity(7), want: 10000},
+		{name: "fraction does not complete a group", quantity: 5999, want: 5000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := Line{ProductID: 1, Quantity: tt.quantity, UnitPrice: 5000}
+			if got := lineDiscount(promotion, line); got != tt.want {
+				t.Errorf("lineDiscount(%s) = %d, want %d", tt.quantity, got, tt.want)
+			}
+		})
+	}
+
+	invalid := promotion
+	invalid.GetQuantity = 0
+	if got := lineDiscount(invalid, Line{ProductID: 1, Quantity: models.WholeQuantity(10), UnitPrice: 5000}); got != 0 {
+		t.Errorf("lineDiscount with get_quantity 0 = %d, want 0", got)
+	}
+}
+
+func TestWithinDailyWindow(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 10, 17, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name       string
+		start, end string
+		now        time.Time
+		want       bool
+	}{
+		{name: "no window", now: at(3, 0), want: true},
+		{name: "inside same-day window", start: "15:00", end: "17:00", now: at(16, 30), want: true},
+		{name: "start is inclusive", start: "15:00", end: "17:00", now: at(15, 0), want: true},
+		{name: "end is exclusive", start: "15:00", end: "17:00", now: at(17, 0), want: false},
+		{name: "before same-day window", start: "15:00", end: "17:00", now: at(14, 59), want: false},
+		{name: "overnight before midnight", start: "22:00", end: "02:00", now: at(23, 15), want: true},
+		{name: "overnight after midnight", start: "22:00", end: "02:00", now: at(1, 59), want: true},
+		{name: "overnight end is exclusive", start: "22:00", end: "02:00", now: at(2, 0), want: false},
+		{name: "overnight outside", start: "22:00", end: "02:00", now: at(12, 0), want: false},
+		{name: "invalid clock never matches", start: "25:00", end: "02:00", now: at(1, 0), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := withinDailyWindow(tt.start, tt.end, tt.now); got != tt.want {
+				t.Errorf("withinDailyWindow(%q, %q, %s) = %v, want %v", tt.start, tt.end, tt.now.Format("15:04"), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  int
+		weights []int
+		want    []int
+	}{
+		{name: "even split", amount: 300, weights: []int{1, 1, 1}, want: []int{100, 100, 100}},
+		{name: "remainder to lowest index on ties", amount: 100, weights: []int{1, 1, 1}, want: []int{34, 33, 33}},
+		{name: "remainder to largest fraction", amount: 10, weights: []int{1, 2, 4}, want: []int{1, 3, 6}},
+		{name: "zero weight gets nothing", amount: 7, weights: []int{0, 3, 3}, want: []int{0, 4, 3}},
+		{name: "zero amount", amount: 0, weights: []int{5, 5}, want: []int{0, 0}},
+		{name: "zero total weight", amount: 50, weights: []int{0, 0}, want: []int{0, 0}},
+		{name: "shares never exceed weights", amount: 3, weights: []int{1, 1, 1}, want: []int{1, 1, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := allocate(tt.amount, tt.weights)
+			sum := 0
+			for i := range got {
+				sum += got[i]
+				if got[i] != tt.want[i] {
+					t.Errorf("allocate(%d, %v) = %v, want %v", tt.amount, tt.weights, got, tt.want)
+					break
+				}
+			}
+			if tt.amount > 0 && totalOf(tt.weights) > 0 && sum != tt.amount {
+				t.Errorf("allocate(%d, %v) sums to %d", tt.amount, tt.weights, sum)
+			}
+		})
+	}
+}
+
+func totalOf(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
@@ -0,0 +1,114 @@
+package pricing
+
+import (
+	"kasir-api/internal/domain/models"
+	"sort"
+)
+
+// BasisPoints adalah penyebut tarif: 10000 bps = 100%
+const BasisPoints = 10000
+
+// TaxSettings adalah pengaturan pajak dan service charge toko
+type TaxSettings struct {
+	// DefaultRateBps dipakai jika produk dan kategorinya tidak punya tarif sendiri
+	DefaultRateBps int
+	// ServiceChargeBps dihitung dari total setelah diskon, 0 berarti tanpa service charge
+	ServiceChargeBps int
+	// PricesIncludeTax berarti Price produk sudah termasuk pajak (PPN)
+	PricesIncludeTax bool
+}
+
+// ResolveTaxRate menentukan tarif efektif sebuah produk dengan urutan:
+// bebas pajak, tarif produk, tarif kategori, lalu tarif default
+func ResolveTaxRate(defaultBps int, productBps, categoryBps *int, exempt bool) int {
+	switch {
+	case exempt:
+		return 0
+	case productBps != nil:
+		return *productBps
+	case categoryBps != nil:
+		return *categoryBps
+	default:
+		return defaultBps
+	}
+}
+
+// applyTaxes menghitung service charge dan pajak dari harga setelah diskon.
+//
+// Service charge = ServiceChargeBps dari total setelah diskon, lalu dibagi ke
+// setiap baris secara proporsional. Service charge ikut dikenai pajak sesuai
+// tarif baris yang menanggungnya.
+//
+// Pajak dihitung per tarif (bukan per baris) supaya selisih pembulatan
+// minimal: dasar = jumlah Net + ServiceCharge baris dengan tarif tersebut.
+// Pajak per tarif lalu dibagi lagi ke baris-barisnya untuk LineResult.Total.
+// Harga belum termasuk pajak: pajak = dasar × tarif, ditambahkan ke total.
+// Harga sudah termasuk pajak: pajak = dasar × tarif / (1 + tarif), hanya
+// dilaporkan dan tidak menambah total.
+//
+// Semua pembulatan ke rupiah terdekat, setengah dibulatkan ke atas.
+func applyTaxes(result *Result, settings TaxSettings) {
+	result.PricesIncludeTax = settings.PricesIncludeTax
+	result.Taxes = make([]models.TaxLine, 0)
+
+	afterDiscount := result.Subtotal - result.DiscountAmount
+	result.ServiceCharge = roundDiv(afterDiscount*settings.ServiceChargeBps, BasisPoints)
+
+	weights := make([]int, len(result.Lines))
+	for i := range result.Lines {
+		weights[i] = result.Lines[i].Net
+	}
+	for i, share := range allocate(result.ServiceCharge, weights) {
+		result.Lines[i].ServiceCharge = share
+	}
+
+	groups := make(map[int][]int)
+	for i, line := range result.Lines {
+		if line.TaxRateBps > 0 {
+			groups[line.TaxRateBps] = append(groups[line.TaxRateBps], i)
+		}
+	}
+	for rate, indexes := range groups {
+		base := 0
+		weights := make([]int, len(indexes))
+		for k, i := range indexes {
+			weights[k] = result.Lines[i].Net + result.Lines[i].ServiceCharge
+			base += weights[k]
+		}
+
+		taxLine := models.TaxLine{RateBps: rate}
+		if settings.PricesIncludeTax {
+			taxLine.TaxAmount = roundDiv(base*rate, BasisPoints+rate)
+			taxLine.TaxableAmount = base - taxLine.TaxAmount
+		} else {
+			taxLine.TaxAmount = roundDiv(base*rate, BasisPoints)
+			taxLine.TaxableAmount = base
+		}
+		result.TaxAmount += taxLine.TaxAmount
+		result.Taxes = append(result.Taxes, taxLine)
+
+		for k, share := range allocate(taxLine.TaxAmount, weights) {
+			result.Lines[indexes[k]].TaxAmount = share
+		}
+	}
+	sort.Slice(result.Taxes, func(i, j int) bool {
+		return result.Taxes[i].RateBps < result.Taxes[j].RateBps
+	})
+
+	result.Total = afterDiscount + result.ServiceCharge
+	if !settings.PricesIncludeTax {
+		result.Total += result.TaxAmount
+	}
+	for i := range result.Lines {
+		line := &result.Lines[i]
+		line.Total = line.Net + line.ServiceCharge
+		if !settings.PricesIncludeTax {
+			line.Total += line.TaxAmount
+		}
+	}
+}
+
+// roundDiv membagi n dengan d dan membulatkan setengah ke atas (n >= 0, d > 0)
+func roundDiv(n, d int) int {
+	return (2*n + d) / (2 * d)
+}
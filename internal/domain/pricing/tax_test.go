@@ -0,0 +1,150 @@
+package pricing
+
+import (
+	"kasir-api/internal/domain/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestApplyTaxes(t *testing.T) {
+	line := func(productID, price, rateBps int) Line {
+		return Line{ProductID: productID, Quantity: models.WholeQuantity(1), UnitPrice: price, TaxRateBps: rateBps}
+	}
+
+	tests := []struct {
+		name          string
+		lines         []Line
+		settings      TaxSettings
+		serviceCharge int
+		taxAmount     int
+		total         int
+		taxes         []models.TaxLine
+		lineTaxes     []int
+		lineTotals    []int
+	}{
+		{
+			name:       "exclusive tax is added to the total",
+			lines:      []Line{line(1, 10000, 1100), line(2, 5000, 1100)},
+			taxAmount:  1650,
+			total:      16650,
+			taxes:      []models.TaxLine{{RateBps: 1100, TaxableAmount: 15000, TaxAmount: 1650}},
+			lineTaxes:  []int{1100, 550},
+			lineTotals: []int{11100, 5550},
+		},
+		{
+			name:       "inclusive tax is extracted and does not change the total",
+			lines:      []Line{line(1, 10000, 1100), line(2, 5000, 1100)},
+			settings:   TaxSettings{PricesIncludeTax: true},
+			taxAmount:  1486,
+			total:      15000,
+			taxes:      []models.TaxLine{{RateBps: 1100, TaxableAmount: 13514, TaxAmount: 1486}},
+			lineTaxes:  []int{991, 495},
+			lineTotals: []int{10000, 5000},
+		},
+		{
+			name:          "service charge is taxed at the rate of the line that carries it",
+			lines:         []Line{line(1, 10000, 1000), line(2, 5000, 0)},
+			settings:      TaxSettings{ServiceChargeBps: 500},
+			serviceCharge: 750,
+			taxAmount:     1050,
+			total:         16800,
+			taxes:         []models.TaxLine{{RateBps: 1000, TaxableAmount: 10500, TaxAmount: 1050}},
+			lineTaxes:     []int{1050, 0},
+			lineTotals:    []int{11550, 5250},
+		},
+		{
+			name:          "inclusive tax with service charge",
+			lines:         []Line{line(1, 11100, 1100)},
+			settings:      TaxSettings{ServiceChargeBps: 1000, PricesIncludeTax: true},
+			serviceCharge: 1110,
+			taxAmount:     1210,
+			total:         12210,
+			taxes:         []models.TaxLine{{RateBps: 1100, TaxableAmount: 11000, TaxAmount: 1210}},
+			lineTaxes:     []int{1210},
+			lineTotals:    []int{12210},
+		},
+		{
+			name:       "half rupiah is rounded up",
+			lines:      []Line{line(1, 1005, 1000)},
+			taxAmount:  101,
+			total:      1106,
+			taxes:      []models.TaxLine{{RateBps: 1000, TaxableAmount: 1005, TaxAmount: 101}},
+			lineTaxes:  []int{101},
+			lineTotals: []int{1106},
+		},
+		{
+			name:       "tax is rounded once per rate, not per line",
+			lines:      []Line{line(1, 333, 1000), line(2, 333, 1000)},
+			taxAmount:  67,
+			total:      733,
+			taxes:      []models.TaxLine{{RateBps: 1000, TaxableAmount: 666, TaxAmount: 67}},
+			lineTaxes:  []int{34, 33},
+			lineTotals: []int{367, 366},
+		},
+		{
+			name:      "tax lines are sorted by rate",
+			lines:     []Line{line(1, 10000, 1100), line(2, 10000, 500), line(3, 10000, 0)},
+			taxAmount: 1600,
+			total:     31600,
+			taxes: []models.TaxLine{
+				{RateBps: 500, TaxableAmount: 10000, TaxAmount: 500},
+				{RateBps: 1100, TaxableAmount: 10000, TaxAmount: 1100},
+			},
+			lineTaxes:  []int{1100, 500, 0},
+			lineTotals: []int{11100, 10500, 10000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cart := Cart{Lines: tt.lines, Now: time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)}
+			result := Calculate(cart, nil, tt.settings)
+
+			if result.ServiceCharge != tt.serviceCharge || result.TaxAmount != tt.taxAmount || result.Total != tt.total {
+				t.Errorf("service charge, tax, total = %d, %d, %d, want %d, %d, %d",
+					result.ServiceCharge, result.TaxAmount, result.Total, tt.serviceCharge, tt.taxAmount, tt.total)
+			}
+			if !reflect.DeepEqual(result.Taxes, tt.taxes) {
+				t.Errorf("taxes = %+v, want %+v", result.Taxes, tt.taxes)
+			}
+
+			sumTax, sumTotal, sumService := 0, 0, 0
+			for i, line := range result.Lines {
+				if line.TaxAmount != tt.lineTaxes[i] || line.Total != tt.lineTotals[i] {
+					t.Errorf("line %d tax, total = %d, %d, want %d, %d", i, line.TaxAmount, line.Total, tt.lineTaxes[i], tt.lineTotals[i])
+				}
+				sumTax += line.TaxAmount
+				sumTotal += line.Total
+				sumService += line.ServiceCharge
+			}
+			if sumTax != result.TaxAmount || sumTotal != result.Total || sumService != result.ServiceCharge {
+				t.Errorf("line sums tax, total, service charge = %d, %d, %d, want %d, %d, %d",
+					sumTax, sumTotal, sumService, result.TaxAmount, result.Total, result.ServiceCharge)
+			}
+		})
+	}
+}
+
+func TestResolveTaxRate(t *testing.T) {
+	tests := []struct {
+		name        string
+		productBps  *int
+		categoryBps *int
+		exempt      bool
+		want        int
+	}{
+		{name: "default rate", want: 1100},
+		{name: "category rate", categoryBps: intPtr(500), want: 500},
+		{name: "product rate beats category", productBps: intPtr(0), categoryBps: intPtr(500), want: 0},
+		{name: "exempt beats everything", productBps: intPtr(1200), exempt: true, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResolveTaxRate(1100, tt.productBps, tt.categoryBps, tt.exempt); got != tt.want {
+				t.Errorf("ResolveTaxRate = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+// Package receipt menyusun struk penjualan dan merendernya sebagai teks
+// monospace, perintah ESC/POS untuk printer thermal, atau PDF. Seperti paket
+// pricing, semua fungsi di sini murni: data transaksi, kasir dan waktu sudah
+// disiapkan oleh pemanggil.
+package receipt
+
+import (
+	"fmt"
+	"kasir-api/internal/domain/models"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	FormatESCPOS = "escpos"
+	FormatText   = "text"
+	FormatPDF    = "pdf"
+)
+
+// lebar kertas printer thermal yang didukung, dalam milimeter
+const (
+	Paper58mm = 58
+	Paper80mm = 80
+)
+
+// Store adalah identitas toko yang dicetak di kepala dan kaki struk
+type Store struct {
+	Name    string
+	Address string
+	Phone   string
+	Footer  string
+}
+
+// Receipt adalah semua data yang dicetak di struk. IssuedAt harus sudah dalam
+// zona waktu toko.
+type Receipt struct {
+	Store       Store
+	Cashier     string
+	IssuedAt    time.Time
+	Transaction models.Transaction
+}
+
+// Columns mengembalikan jumlah karakter per baris untuk lebar kertas
+// paperMM (font A printer thermal). ok false jika lebar tidak didukung.
+func Columns(paperMM int) (columns int, ok bool) {
+	switch paperMM {
+	case Paper58mm:
+		return 32, true
+	case Paper80mm:
+		return 48, true
+	}
+	return 0, false
+}
+
+// line adalah satu baris struk yang sudah diratakan sepanjang lebar kolom
+type line struct {
+	text string
+	bold bool
+}
+
+// layout menyusun isi struk menjadi baris-baris selebar columns karakter.
+// Semua format memakai layout yang sama sehingga isinya selalu identik.
+func layout(r Receipt, columns int) []line {
+	var lines []line
+	add := func(bold bool, texts ...string) {
+		for _, text := range texts {
+			lines = append(lines, line{text: text, bold: bold})
+		}
+	}
+	separator := strings.Repeat("-", columns)
+	t := r.Transaction
+
+	for _, text := range wrap(r.Store.Name, columns) {
+		add(true, center(text, columns))
+	}
+	for _, text := range wrap(r.Store.Address, columns) {
+		add(false, center(text, columns))
+	}
+	if r.Store.Phone != "" {
+		for _, text := range wrap("Telp. "+r.Store.Phone, columns) {
+			add(false, center(text, columns))
+		}
+	}
+	add(false, separator)
+
+	add(false, row("No", fmt.Sprintf("TRX-%d", t.ID), columns)...)
+	if t.OrderID != nil {
+		add(false, row("Order", fmt.Sprintf("#%d", *t.OrderID), columns)...)
+	}
+	add(false, row("Kasir", r.Cashier, columns)...)
+	add(false, row("Waktu", r.IssuedAt.Format("02/01/2006 15:04"), columns)...)
+	add(false, separator)
+
+	for _, item := range t.Details {
+		name := item.ProductName
+		if item.VariantName != "" {
+			name += " (" + item.VariantName + ")"
+		}
+		add(false, wrap(name, columns)...)
+		qty := fmt.Sprintf("  %s x %s", formatQuantity(item.Quantity), formatAmount(item.Price))
+		add(false, row(qty, formatAmount(item.Quantity.Amount(item.Price)), columns)...)
+		if item.Discount > 0 {
+			add(false, row("  Diskon", formatAmount(-item.Discount), columns)...)
+		}
+	}
+	add(false, separator)
+
+	add(false, row("Subtotal", formatAmount(t.SubtotalAmount), columns)...)
+	if t.DiscountAmount > 0 {
+		add(false, row("Diskon", formatAmount(-t.DiscountAmount), columns)...)
+		for _, promo := range t.Promotions {
+			add(false, row("  "+promo.Name, formatAmount(-promo.DiscountAmount), columns)...)
+		}
+	}
+	if t.ServiceChargeAmount > 0 {
+		add(false, row("Service charge", formatAmount(t.ServiceChargeAmount), columns)...)
+	}
+	for _, tax := range t.Taxes {
+		if tax.TaxAmount == 0 {
+			continue
+		}
+		label := "PPN " + formatRate(tax.RateBps)
+		if t.PricesIncludeTax {
+			label += " (termasuk)"
+		}
+		add(false, row(label, formatAmount(tax.TaxAmount), columns)...)
+	}
+	add(true, row("TOTAL", formatAmount(t.TotalAmount), columns)...)
+	add(false, separator)
+
+	for _, payment := range t.Payments {
+		amount := payment.Amount
+		if payment.TenderedAmount != nil {
+			amount = *payment.TenderedAmount
+		}
+		add(false, row(paymentLabel(payment.Method), formatAmount(amount), columns)...)
+		if payment.Reference != "" {
+			add(false, row("  Ref: "+payment.Reference, "", columns)...)
+		}
+	}
+	if t.ChangeAmount > 0 {
+		add(false, row("Kembali", formatAmount(t.ChangeAmount), columns)...)
+	}
+	if t.RefundedAmount > 0 {
+		add(false, row("Refund", formatAmount(-t.RefundedAmount), columns)...)
+	}
+
+	if r.Store.Footer != "" {
+		add(false, separator)
+		for _, text := range wrap(r.Store.Footer, columns) {
+			add(false, center(text, columns))
+		}
+	}
+
+	return lines
+}
+
+// row menaruh left rata kiri dan right rata kanan di satu baris. Jika tidak
+// muat, left dipecah ke baris sendiri dan right diletakkan di baris terakhir.
+func row(left, right string, columns int) []string {
+	gap := columns - utf8.RuneCountInString(left) - utf8.RuneCountInString(right)
+	if gap >= 1 {
+		return []string{left + strings.Repeat(" ", gap) + right}
+	}
+	rows := wrap(left, columns)
+	return append(rows, strings.Repeat(" ", max(columns-utf8.RuneCountInString(right), 0))+right)
+}
+
+// wrap memecah s per kata supaya setiap baris paling panjang columns
+// karakter; kata yang lebih panjang dari satu baris dipotong paksa
+func wrap(s string, columns int) []string {
+	var rows []string
+	current := ""
+	for _, word := range strings.Fields(s) {
+		for utf8.RuneCountInString(word) > columns {
+			if current != "" {
+				rows = append(rows, current)
+				current = ""
+			}
+			runes := []rune(word)
+			rows = append(rows, string(runes[:columns]))
+			word = string(runes[columns:])
+		}
+		switch {
+		case current == "":
+			current = word
+		case utf8.RuneCountInString(current)+1+utf8.RuneCountInString(word) <= columns:
+			current += " " + word
+		default:
+			rows = append(rows, current)
+			current = word
+		}
+	}
+	if current != "" {
+		rows = append(rows, current)
+	}
+	return rows
+}
+
+func center(s string, columns int) string {
+	padding := (columns - utf8.RuneCountInString(s)) / 2
+	if padding <= 0 {
+		return s
+	}
+	return strings.Repeat(" ", padding) + s
+}
+
+// formatAmount menulis rupiah dengan pemisah ribuan titik, mis. -12.500
+func formatAmount(amount int) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	digits := strconv.Itoa(amount)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(d)
+	}
+	return sign + b.String()
+}
+
+// formatQuantity menulis quantity dengan koma desimal, mis. 0,35
+func formatQuantity(q models.Quantity) string {
+	return strings.Replace(q.String(), ".", ",", 1)
+}
+
+// formatRate menulis basis poin sebagai persen, mis. 1100 -> 11%, 1050 -> 10,5%
+func formatRate(bps int) string {
+	whole, frac := bps/100, bps%100
+	if frac == 0 {
+		return fmt.Sprintf("%d%%", whole)
+	}
+	return strings.TrimRight(fmt.Sprintf("%d,%02d", whole, frac), "0") + "%"
+}
+
+func paymentLabel(method string) string {
+	switch method {
+	case models.PaymentMethodCash:
+		return "Tunai"
+	case models.PaymentMethodDebitCard:
+		return "Kartu Debit"
+	case models.PaymentMethodCreditCard:
+		return "Kartu Kredit"
+	case models.PaymentMethodEWallet:
+		return "E-Wallet"
+	case models.PaymentMethodQRIS:
+		return "QRIS"
+	}
+	return method
+}
+
+// ascii mengganti karakter di luar ASCII dengan '?' karena code page printer
+// thermal dan font standar PDF tidak mengenal UTF-8. Panjang dalam rune tetap
+// sama sehingga perataan kolom tidak bergeser.
+func ascii(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e {
+			return '?'
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,248 @@
+package receipt
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"kasir-api/internal/domain/models"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// go test ./internal/domain/receipt -update menulis ulang file golden
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+func intPtr(v int) *int { return &v }
+
+// sampleReceipt berisi semua bagian struk: order, varian, diskon promo,
+// barang timbang, nama non-ASCII, kata yang lebih panjang dari 32 kolom,
+// service charge, pajak, split tender dan kembalian
+func sampleReceipt() Receipt {
+	return Receipt{
+		Store: Store{
+			Name:    "Toko Kasir Sejahtera",
+			Address: "Jl. Merdeka No. 17 (samping Masjid Raya), Bandung",
+			Phone:   "022-4201234",
+			Footer:  "Terima kasih atas kunjungan Anda! Barang yang sudah dibeli tidak dapat ditukar.",
+		},
+		Cashier:  "Siti Nurhaliza",
+		IssuedAt: time.Date(2026, 3, 14, 19, 5, 0, 0, time.FixedZone("WIB", 7*3600)),
+		Transaction: models.Transaction{
+			ID:                  1042,
+			OrderID:             intPtr(15),
+			SubtotalAmount:      126000,
+			DiscountAmount:      3600,
+			ServiceChargeAmount: 6120,
+			TaxAmount:           14137,
+			TotalAmount:         142657,
+			ChangeAmount:        20000,
+			Details: []models.TransactionItem{
+				{ProductName: "Kopi Susu Gula Aren", VariantName: "Large", Quantity: models.WholeQuantity(2), Price: 18000, Discount: 3600},
+				{ProductName: "Crème Brûlée Cheesecake", Quantity: models.WholeQuantity(1), Price: 32500},
+				{ProductName: "Daging Sapi Has Dalam", Quantity: 350, Price: 150000},
+				{ProductName: "MinumanSerbukRasaJerukNipisEkstraDingin", Quantity: models.WholeQuantity(1), Price: 5000},
+			},
+			Payments: []models.Payment{
+				{Method: models.PaymentMethodQRIS, Amount: 42657, Reference: "QR-889120"},
+				{Method: models.PaymentMethodCash, Amount: 100000, TenderedAmount: intPtr(120000), ChangeAmount: 20000},
+			},
+			Promotions: []models.AppliedPromotion{{PromotionID: 3, Name: "Happy Hour Kopi 10%", DiscountAmount: 3600}},
+			Taxes:      []models.TaxLine{{RateBps: 1100, TaxableAmount: 128520, TaxAmount: 14137}},
+		},
+	}
+}
+
+func TestTextGolden(t *testing.T) {
+	for _, paper := range []int{Paper58mm, Paper80mm} {
+		t.Run(fmt.Sprintf("%dmm", paper), func(t *testing.T) {
+			columns, ok := Columns(paper)
+			if !ok {
+				t.Fatalf("Columns(%d) not supported", paper)
+			}
+			got := Text(sampleReceipt(), columns)
+
+			golden := filepath.Join("testdata", fmt.Sprintf("receipt_%dmm.golden", paper))
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatalf("write golden: %v", err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("read golden: %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("Text() mismatch with %s, run with -update after checking the diff\ngot:\n%s", golden, got)
+			}
+
+			for i, text := range strings.Split(strings.TrimSuffix(string(got), "\n"), "\n") {
+				if n := utf8.RuneCountInString(text); n > columns {
+					t.Errorf("line %d is %d characters wide, want at most %d: %q", i+1, n, columns, text)
+				}
+			}
+		})
+	}
+}
+
+func TestESCPOS(t *testing.T) {
+	columns, _ := Columns(Paper58mm)
+	got := ESCPOS(sampleReceipt(), columns)
+
+	if !bytes.HasPrefix(got, escInit) {
+		t.Errorf("stream must start with ESC @")
+	}
+	if !bytes.HasSuffix(got, append(append([]byte{}, escFeed...), escCut...)) {
+		t.Errorf("stream must end with feed and partial cut")
+	}
+
+	body := got[len(escInit) : len(got)-len(escFeed)-len(escCut)]
+	// nama toko dan TOTAL dicetak tebal
+	for _, bold := range []string{"Toko Kasir Sejahtera", "TOTAL"} {
+		pattern := regexp.MustCompile(`\x1bE\x01[^\n]*` + regexp.QuoteMeta(bold) + `[^\n]*\x1bE\x00\n`)
+		if !pattern.Match(body) {
+			t.Errorf("%q is not wrapped in bold on/off", bold)
+		}
+	}
+
+	// di luar perintah ESC, hanya ASCII yang boleh dikirim ke printer; huruf
+	// non-ASCII diganti '?' satu per satu supaya kolom tidak bergeser
+	plain := strings.NewReplacer(string(escBoldOn), "", string(escBoldOff), "").Replace(string(body))
+	for i := 0; i < len(plain); i++ {
+		if c := plain[i]; c != '\n' && (c < 0x20 || c > 0x7e) {
+			t.Fatalf("non-printable byte 0x%02x at %d", c, i)
+		}
+	}
+	if !strings.Contains(plain, "\nCr?me Br?l?e Cheesecake\n") {
+		t.Errorf("non-ASCII product name not replaced rune by rune:\n%s", plain)
+	}
+	text := strings.Split(strings.TrimSuffix(string(Text(sampleReceipt(), columns)), "\n"), "\n")
+	lines := strings.Split(strings.TrimSuffix(plain, "\n"), "\n")
+	if len(lines) != len(text) {
+		t.Errorf("ESC/POS has %d lines, text has %d", len(lines), len(text))
+	}
+}
+
+func TestPDFStructure(t *testing.T) {
+	for _, paper := range []int{Paper58mm, Paper80mm} {
+		t.Run(fmt.Sprintf("%dmm", paper), func(t *testing.T) {
+			columns, _ := Columns(paper)
+			doc := PDF(sampleReceipt(), paper, columns)
+
+			if !bytes.HasPrefix(doc, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(doc, []byte("%%EOF\n")) {
+				t.Fatalf("missing PDF header or EOF marker")
+			}
+
+			// startxref menunjuk ke tabel xref
+			m := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(doc)
+			if m == nil {
+				t.Fatalf("startxref not found")
+			}
+			xref, _ := strconv.Atoi(string(m[1]))
+			if !bytes.HasPrefix(doc[xref:], []byte("xref\n0 7\n0000000000 65535 f \n")) {
+				t.Fatalf("startxref %d does not point to an xref table of 7 entries", xref)
+			}
+
+			// setiap entry xref menunjuk tepat ke "N 0 obj"
+			entries := regexp.MustCompile(`(\d{10}) 00000 n \n`).FindAllSubmatch(doc[xref:], -1)
+			if len(entries) != 6 {
+				t.Fatalf("xref has %d in-use entries, want 6", len(entries))
+			}
+			for i, entry := range entries {
+				offset, _ := strconv.Atoi(string(entry[1]))
+				header := fmt.Sprintf("%d 0 obj\n", i+1)
+				if !bytes.HasPrefix(doc[offset:], []byte(header)) {
+					t.Errorf("xref entry %d points to %q, want %q", i+1, doc[offset:min(offset+len(header), len(doc))], header)
+				}
+			}
+
+			// /Length sama dengan jumlah byte di antara stream dan endstream
+			m = regexp.MustCompile(`<< /Length (\d+) >>\nstream\n`).FindSubmatch(doc)
+			if m == nil {
+				t.Fatalf("content stream not found")
+			}
+			length, _ := strconv.Atoi(string(m[1]))
+			start := bytes.Index(doc, m[0]) + len(m[0])
+			end := bytes.Index(doc[start:], []byte("endstream"))
+			if end != length {
+				t.Errorf("/Length = %d, stream is %d bytes", length, end)
+			}
+
+			// kurung di alamat di-escape dan setiap baris layout menjadi satu Tj
+			stream := string(doc[start : start+end])
+			if !strings.Contains(stream, `\(samping`) {
+				t.Errorf("parentheses in store address are not escaped")
+			}
+			if got, want := strings.Count(stream, ") Tj T*\n"), len(layout(sampleReceipt(), columns)); got != want {
+				t.Errorf("stream has %d text lines, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		columns int
+		want    []string
+	}{
+		{name: "fits", s: "Teh Botol", columns: 10, want: []string{"Teh Botol"}},
+		{name: "exact width", s: "Teh Botol Sosro", columns: 15, want: []string{"Teh Botol Sosro"}},
+		{name: "word boundary", s: "Teh Botol Sosro", columns: 10, want: []string{"Teh Botol", "Sosro"}},
+		{name: "collapses spaces", s: "  Teh   Botol  ", columns: 20, want: []string{"Teh Botol"}},
+		{name: "empty", s: "   ", columns: 10, want: nil},
+		{name: "word longer than column", s: "Minuman SerbukRasaJeruk Dingin", columns: 8,
+			want: []string{"Minuman", "SerbukRa", "saJeruk", "Dingin"}},
+		{name: "long word exactly twice the column", s: "ABCDEFGHIJ", columns: 5, want: []string{"ABCDE", "FGHIJ"}},
+		{name: "long word after short word", s: "Es ABCDEFGHIJK", columns: 5, want: []string{"Es", "ABCDE", "FGHIJ", "K"}},
+		{name: "non-ASCII counted per rune", s: "Crème Brûlée Cheesecake", columns: 12, want: []string{"Crème Brûlée", "Cheesecake"}},
+		{name: "long non-ASCII word cut on rune boundary", s: "ÀÉÎÕÜÀÉÎÕÜÀ", columns: 5, want: []string{"ÀÉÎÕÜ", "ÀÉÎÕÜ", "À"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wrap(tt.s, tt.columns)
+			if fmt.Sprintf("%q", got) != fmt.Sprintf("%q", tt.want) {
+				t.Errorf("wrap(%q, %d) = %q, want %q", tt.s, tt.columns, got, tt.want)
+			}
+			for _, r := range got {
+				if !utf8.ValidString(r) || utf8.RuneCountInString(r) > tt.columns {
+					t.Errorf("row %q is invalid or wider than %d", r, tt.columns)
+				}
+			}
+		})
+	}
+}
+
+func TestRow(t *testing.T) {
+	tests := []struct {
+		name        string
+		left, right string
+		columns     int
+		want        []string
+	}{
+		{name: "fits", left: "Subtotal", right: "126.000", columns: 20, want: []string{"Subtotal     126.000"}},
+		{name: "one space minimum", left: "Subtotal", right: "126.000", columns: 16, want: []string{"Subtotal 126.000"}},
+		{name: "non-ASCII left aligned per rune", left: "Crème", right: "5.000", columns: 12, want: []string{"Crème  5.000"}},
+		{name: "right on its own row", left: "Subtotal", right: "126.000", columns: 15,
+			want: []string{"Subtotal", "        126.000"}},
+		{name: "long left wraps", left: "  Happy Hour Kopi 10%", right: "-3.600", columns: 12,
+			want: []string{"Happy Hour", "Kopi 10%", "      -3.600"}},
+		{name: "empty right", left: "  Ref: QR-889120", right: "", columns: 20, want: []string{"  Ref: QR-889120    "}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := row(tt.left, tt.right, tt.columns)
+			if fmt.Sprintf("%q", got) != fmt.Sprintf("%q", tt.want) {
+				t.Errorf("row(%q, %q, %d) = %q, want %q", tt.left, tt.right, tt.columns, got, tt.want)
+			}
+		})
+	}
+}
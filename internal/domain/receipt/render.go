@@ -0,0 +1,111 @@
+package receipt
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Text merender struk sebagai teks monospace UTF-8
+func Text(r Receipt, columns int) []byte {
+	var b bytes.Buffer
+	for _, l := range layout(r, columns) {
+		b.WriteString(strings.TrimRight(l.text, " "))
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
+
+// perintah ESC/POS yang dipakai
+var (
+	escInit    = []byte{0x1b, 0x40}             // ESC @: reset printer
+	escBoldOn  = []byte{0x1b, 0x45, 0x01}       // ESC E 1
+	escBoldOff = []byte{0x1b, 0x45, 0x00}       // ESC E 0
+	escFeed    = []byte{0x1b, 0x64, 0x04}       // ESC d 4: maju 4 baris sebelum dipotong
+	escCut     = []byte{0x1d, 0x56, 0x42, 0x00} // GS V 66 0: partial cut
+)
+
+// ESCPOS merender struk sebagai byte stream ESC/POS yang bisa dikirim
+// langsung ke printer thermal
+func ESCPOS(r Receipt, columns int) []byte {
+	var b bytes.Buffer
+	b.Write(escInit)
+	for _, l := range layout(r, columns) {
+		if l.bold {
+			b.Write(escBoldOn)
+		}
+		b.WriteString(strings.TrimRight(ascii(l.text), " "))
+		if l.bold {
+			b.Write(escBoldOff)
+		}
+		b.WriteByte('\n')
+	}
+	b.Write(escFeed)
+	b.Write(escCut)
+	return b.Bytes()
+}
+
+const (
+	pointsPerMM = 72 / 25.4
+	pdfMargin   = 10.0
+	// lebar glyph Courier adalah 600/1000 dari ukuran font
+	courierWidth = 0.6
+)
+
+// PDF merender struk sebagai dokumen PDF satu halaman selebar kertas paperMM
+// dengan tinggi mengikuti jumlah baris. Font Courier dipakai supaya perataan
+// kolom sama dengan versi teks.
+func PDF(r Receipt, paperMM, columns int) []byte {
+	lines := layout(r, columns)
+
+	width := float64(paperMM) * pointsPerMM
+	fontSize := (width - 2*pdfMargin) / (float64(columns) * courierWidth)
+	leading := fontSize * 1.25
+	height := 2*pdfMargin + float64(len(lines))*leading
+
+	var content bytes.Buffer
+	fmt.Fprintf(&content, "BT\n%s TL\n%s %s Td\n", pdfNumber(leading), pdfNumber(pdfMargin), pdfNumber(height-pdfMargin-fontSize))
+	for _, l := range lines {
+		font := "/F1"
+		if l.bold {
+			font = "/F2"
+		}
+		fmt.Fprintf(&content, "%s %s Tf (%s) Tj T*\n", font, pdfNumber(fontSize), pdfEscape(ascii(l.text)))
+	}
+	content.WriteString("ET\n")
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Contents 4 0 R /Resources << /Font << /F1 5 0 R /F2 6 0 R >> >> >>",
+			pdfNumber(width), pdfNumber(height)),
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>",
+	}
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return b.Bytes()
+}
+
+func pdfNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', 2, 64)
+}
+
+// pdfEscape meng-escape karakter khusus di dalam string literal PDF
+func pdfEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`).Replace(s)
+}
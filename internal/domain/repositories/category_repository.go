@@ -1,17 +1,17 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
-	"errors"
 	"kasir-api/internal/domain/models"
 )
 
 type CategoryRepository interface {
-	GetAllCategory() ([]models.Category, error)
-	CreateCategory(category *models.Category) error
-	GetCategoryByID(id int) (*models.Category, error)
-	UpdateCategory(category *models.Category) error
-	DeleteCategory(id int) error
+	GetAllCategory(ctx context.Context) ([]models.Category, error)
+	CreateCategory(ctx context.Context, category *models.Category) error
+	GetCategoryByID(ctx context.Context, id int) (*models.Category, error)
+	UpdateCategory(ctx context.Context, category *models.Category) error
+	DeleteCategory(ctx context.Context, id int) error
 }
 
 type categoryRepository struct {
@@ -22,9 +22,9 @@ func NewCategoryRepository(db *sql.DB) CategoryRepository {
 	return &categoryRepository{db: db}
 }
 
-func (repo *categoryRepository) GetAllCategory() ([]models.Category, error) {
-	query := "SELECT id, name, description FROM categories"
-	rows, err := repo.db.Query(query)
+func (repo *categoryRepository) GetAllCategory(ctx context.Context) ([]models.Category, error) {
+	query := "SELECT id, name, description, tax_rate_bps FROM categories"
+	rows, err := repo.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -33,45 +33,49 @@ func (repo *categoryRepository) GetAllCategory() ([]models.Category, error) {
 	categories := make([]models.Category, 0)
 	for rows.Next() {
 		var category models.Category
-		if err := rows.Scan(&category.ID, &category.Name, &category.Description); err != nil {
+		var taxRate sql.NullInt64
+		if err := rows.Scan(&category.ID, &category.Name, &category.Description, &taxRate); err != nil {
 			return nil, err
 		}
+		category.TaxRateBps = nullIntPtr(taxRate)
 		categories = append(categories, category)
 	}
 	return categories, nil
 }
-func (repo *categoryRepository) CreateCategory(category *models.Category) error {
-	query := "INSERT INTO categories (name, description) VALUES ($1, $2) RETURNING id"
-	err := repo.db.QueryRow(query, category.Name, category.Description).Scan(&category.ID)
-	if err != nil {
-		return err
-	}
-	return nil
+func (repo *categoryRepository) CreateCategory(ctx context.Context, category *models.Category) error {
+	query := "INSERT INTO categories (name, description, tax_rate_bps) VALUES ($1, $2, $3) RETURNING id"
+	err := repo.db.QueryRowContext(ctx, query, category.Name, category.Description, category.TaxRateBps).Scan(&category.ID)
+	return mapDBError(ctx, err)
 }
-func (repo *categoryRepository) GetCategoryByID(id int) (*models.Category, error) {
-	query := "SELECT id, name, description FROM categories WHERE id = $1"
+func (repo *categoryRepository) GetCategoryByID(ctx context.Context, id int) (*models.Category, error) {
+	query := "SELECT id, name, description, tax_rate_bps FROM categories WHERE id = $1"
 
 	var p models.Category
-	err := repo.db.QueryRow(query, id).Scan(&p.ID, &p.Name, &p.Description)
+	var taxRate sql.NullInt64
+	err := repo.db.QueryRowContext(ctx, query, id).Scan(&p.ID, &p.Name, &p.Description, &taxRate)
 	if err == sql.ErrNoRows {
-		return nil, errors.New("Category not found")
+		return nil, models.NewNotFoundError("category")
 	}
 	if err != nil {
 		return nil, err
 	}
+	p.TaxRateBps = nullIntPtr(taxRate)
 
 	return &p, nil
 }
-func (repo *categoryRepository) UpdateCategory(category *models.Category) error {
-	query := "UPDATE categories SET name = $1, description = $2 WHERE id = $3"
-	err := repo.db.QueryRow(query, category.Name, category.Description, category.ID).Scan(&category.ID)
+func (repo *categoryRepository) UpdateCategory(ctx context.Context, category *models.Category) error {
+	query := "UPDATE categories SET name = $1, description = $2, tax_rate_bps = $3 WHERE id = $4"
+	result, err := repo.db.ExecContext(ctx, query, category.Name, category.Description, category.TaxRateBps, category.ID)
 	if err != nil {
-		return err
+		return mapDBError(ctx, err)
 	}
-	return nil
+	return notFoundIfNoRows(result, "category")
 }
-func (repo *categoryRepository) DeleteCategory(id int) error {
+func (repo *categoryRepository) DeleteCategory(ctx context.Context, id int) error {
 	query := "DELETE FROM categories WHERE id = $1"
-	_, err := repo.db.Exec(query, id)
-	return err
+	result, err := repo.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return mapDBError(ctx, err)
+	}
+	return notFoundIfNoRows(result, "category")
 }
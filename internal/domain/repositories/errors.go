@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/pkg"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/sirupsen/logrus"
+)
+
+// Kode error PostgreSQL yang dipetakan ke error domain
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgCheckViolation      = "23514"
+)
+
+// mapDBError mengubah error constraint PostgreSQL menjadi error domain.
+// Detail dari PostgreSQL (nilai yang bentrok, nama tabel dan constraint)
+// hanya dicatat di log; client menerima pesan generik. Error lain
+// dikembalikan apa adanya.
+func mapDBError(ctx context.Context, err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	var mapped error
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		mapped = models.NewConflictError("duplicate value, the data already exists")
+	case pgForeignKeyViolation:
+		mapped = models.NewConflictError("referenced data conflict, the data is still in use or refers to missing data")
+	case pgCheckViolation:
+		field := pgErr.ColumnName
+		if field == "" {
+			field = "value"
+		}
+		mapped = models.NewValidationError(field, "value is not allowed")
+	default:
+		return err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"repository": "database",
+		"code":       pgErr.Code,
+		"table":      pgErr.TableName,
+		"constraint": pgErr.ConstraintName,
+		"detail":     pgErr.Detail,
+	}).Warn("Database constraint violated")
+	return mapped
+}
+
+// isUniqueViolation dipakai jika pesan conflict perlu lebih spesifik
+// daripada pesan generik dari mapDBError
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation
+}
+
+// isForeignKeyViolation dipakai jika data masih dirujuk tabel lain dan
+// pesan conflict perlu menjelaskan alasannya
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation
+}
+
+// notFoundIfNoRows mengembalikan NotFoundError jika UPDATE/DELETE tidak mengenai baris apapun
+func notFoundIfNoRows(result sql.Result, resource string) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return models.NewNotFoundError(resource)
+	}
+	return nil
+}
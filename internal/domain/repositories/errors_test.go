@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"io"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/pkg"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestMapDBError(t *testing.T) {
+	pkg.InitLogger()
+	pkg.Log.SetOutput(io.Discard)
+
+	detail := "Key (sku)=(KS-250) already exists."
+	tests := []struct {
+		name   string
+		err    error
+		want   error
+		field  string
+		passed bool
+	}{
+		{name: "unique violation", err: &pgconn.PgError{Code: pgUniqueViolation, Detail: detail, ConstraintName: "products_sku_key"},
+			want: models.ErrConflict},
+		{name: "foreign key violation", err: &pgconn.PgError{Code: pgForeignKeyViolation, Detail: "Key (id)=(3) is still referenced."},
+			want: models.ErrConflict},
+		{name: "check violation with column", err: &pgconn.PgError{Code: pgCheckViolation, ColumnName: "price", ConstraintName: "products_price_check"},
+			want: models.ErrValidation, field: "price"},
+		{name: "check violation without column", err: &pgconn.PgError{Code: pgCheckViolation, ConstraintName: "products_price_check"},
+			want: models.ErrValidation, field: "value"},
+		{name: "other database error", err: &pgconn.PgError{Code: "40001"}, passed: true},
+		{name: "non database error", err: errors.New("boom"), passed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mapDBError(context.Background(), tt.err)
+			if tt.passed {
+				if got != tt.err {
+					t.Errorf("mapDBError = %v, want the error unchanged", got)
+				}
+				return
+			}
+			if !errors.Is(got, tt.want) {
+				t.Fatalf("mapDBError = %v, want %v", got, tt.want)
+			}
+			for _, leaked := range []string{"Key (", "_check", "_key", "KS-250"} {
+				if strings.Contains(got.Error(), leaked) {
+					t.Errorf("message %q leaks %q", got.Error(), leaked)
+				}
+			}
+			var validationErr *models.ValidationError
+			if tt.field != "" && (!errors.As(got, &validationErr) || validationErr.Fields[0].Field != tt.field) {
+				t.Errorf("validation field = %+v, want %q", validationErr, tt.field)
+			}
+		})
+	}
+}
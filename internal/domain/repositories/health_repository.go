@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"kasir-api/internal/database"
+)
+
+type HealthRepository interface {
+	Ping(ctx context.Context) error
+	Stats() sql.DBStats
+	MigrationVersion(ctx context.Context) (current int, latest int, err error)
+}
+
+type healthRepository struct {
+	db *sql.DB
+}
+
+func NewHealthRepository(db *sql.DB) HealthRepository {
+	return &healthRepository{db: db}
+}
+
+func (repo *healthRepository) Ping(ctx context.Context) error {
+	return repo.db.PingContext(ctx)
+}
+
+func (repo *healthRepository) Stats() sql.DBStats {
+	return repo.db.Stats()
+}
+
+func (repo *healthRepository) MigrationVersion(ctx context.Context) (int, int, error) {
+	latest, err := database.LatestMigrationVersion()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var current int
+	err = repo.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&current)
+	if err != nil {
+		return 0, latest, err
+	}
+
+	return current, latest, nil
+}
@@ -0,0 +1,245 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"kasir-api/internal/domain/models"
+	"slices"
+)
+
+type OrderRepository interface {
+	CreateOrder(ctx context.Context, order *models.Order) error
+	GetOrderByID(ctx context.Context, id int) (*models.Order, error)
+	// GetOrders mengembalikan order dengan status tertentu, terbaru dulu.
+	// Status kosong berarti order yang masih terbuka (draft dan held).
+	GetOrders(ctx context.Context, status string, page, perPage int) ([]models.Order, error)
+	// UpdateOrder menimpa quantity produk di order draft, quantity 0 menghapus barisnya
+	UpdateOrder(ctx context.Context, id int, note, promoCode *string, items []models.CheckoutItem) error
+	// SetOrderStatus mengubah status order jika status saat ini termasuk from
+	SetOrderStatus(ctx context.Context, id int, to string, from ...string) error
+	VoidOrder(ctx context.Context, id int, reason string, voidedBy int) error
+}
+
+type orderRepository struct {
+	db *sql.DB
+}
+
+func NewOrderRepository(db *sql.DB) OrderRepository {
+	return &orderRepository{db: db}
+}
+
+const orderColumns = `o.id, o.user_id, o.status, o.note, o.promo_code, o.transaction_id, o.void_reason, o.voided_by,
+	o.voided_at, o.created_at, o.updated_at`
+
+func (repo *orderRepository) CreateOrder(ctx context.Context, order *models.Order) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO orders (user_id, status, note, promo_code) VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at`
+	err = tx.QueryRowContext(ctx, query, order.UserID, order.Status, order.Note, order.PromoCode).
+		Scan(&order.ID, &order.CreatedAt, &order.UpdatedAt)
+	if err != nil {
+		return mapDBError(ctx, err)
+	}
+
+	for _, item := range order.Items {
+		query := "INSERT INTO order_items (order_id, product_id, variant_id, quantity) VALUES ($1, $2, $3, $4)"
+		if _, err := tx.ExecContext(ctx, query, order.ID, item.ProductID, item.VariantID, item.Quantity); err != nil {
+			return mapDBError(ctx, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (repo *orderRepository) GetOrderByID(ctx context.Context, id int) (*models.Order, error) {
+	orders, err := repo.queryOrders(ctx, "SELECT "+orderColumns+" FROM orders o WHERE o.id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return nil, models.NewNotFoundError("order")
+	}
+	return &orders[0], nil
+}
+
+func (repo *orderRepository) GetOrders(ctx context.Context, status string, page, perPage int) ([]models.Order, error) {
+	where := "o.status IN ('draft', 'held')"
+	args := []any{perPage, (page - 1) * perPage}
+	if status != "" {
+		where = "o.status = $3"
+		args = append(args, status)
+	}
+	query := "SELECT " + orderColumns + " FROM orders o WHERE " + where + " ORDER BY o.updated_at DESC, o.id DESC LIMIT $1 OFFSET $2"
+	return repo.queryOrders(ctx, query, args...)
+}
+
+// queryOrders menjalankan query header order lalu memuat item semua order
+// tersebut dengan satu query tambahan
+func (repo *orderRepository) queryOrders(ctx context.Context, query string, args ...any) ([]models.Order, error) {
+	rows, err := repo.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := make([]models.Order, 0)
+	index := make(map[int]int)
+	for rows.Next() {
+		var o models.Order
+		var userID, transactionID, voidedBy sql.NullInt64
+		var voidedAt sql.NullTime
+		err := rows.Scan(&o.ID, &userID, &o.Status, &o.Note, &o.PromoCode, &transactionID, &o.VoidReason, &voidedBy,
+			&voidedAt, &o.CreatedAt, &o.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		o.UserID = nullIntPtr(userID)
+		o.TransactionID = nullIntPtr(transactionID)
+		o.VoidedBy = nullIntPtr(voidedBy)
+		if voidedAt.Valid {
+			o.VoidedAt = &voidedAt.Time
+		}
+		o.Items = make([]models.OrderItem, 0)
+		index[o.ID] = len(orders)
+		orders = append(orders, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return orders, nil
+	}
+
+	ids := make([]int64, 0, len(orders))
+	for _, o := range orders {
+		ids = append(ids, int64(o.ID))
+	}
+	itemQuery := `SELECT oi.order_id, oi.product_id, p.name, oi.variant_id, v.option_values, COALESCE(v.price, p.price), oi.quantity
+		FROM order_items oi JOIN products p ON p.id = oi.product_id
+		LEFT JOIN product_variants v ON v.id = oi.variant_id
+		WHERE oi.order_id = ANY($1) ORDER BY oi.order_id, oi.product_id, COALESCE(oi.variant_id, 0)`
+	itemRows, err := repo.db.QueryContext(ctx, itemQuery, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer itemRows.Close()
+
+	for itemRows.Next() {
+		var orderID int
+		var item models.OrderItem
+		var variantID sql.NullInt64
+		var optionValues []string
+		if err := itemRows.Scan(&orderID, &item.ProductID, &item.ProductName, &variantID, textArray(&optionValues),
+			&item.Price, &item.Quantity); err != nil {
+			return nil, err
+		}
+		item.VariantID = nullIntPtr(variantID)
+		if item.VariantID != nil {
+			item.VariantName = models.VariantName(optionValues)
+		}
+		o := &orders[index[orderID]]
+		o.Items = append(o.Items, item)
+	}
+	return orders, itemRows.Err()
+}
+
+// lockOrder mengunci order dengan FOR UPDATE dan mengembalikan statusnya
+func lockOrder(ctx context.Context, tx *sql.Tx, id int) (string, error) {
+	var status string
+	err := tx.QueryRowContext(ctx, "SELECT status FROM orders WHERE id = $1 FOR UPDATE", id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return "", models.NewNotFoundError("order")
+	}
+	return status, err
+}
+
+func (repo *orderRepository) UpdateOrder(ctx context.Context, id int, note, promoCode *string, items []models.CheckoutItem) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, err := lockOrder(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if status != models.OrderStatusDraft {
+		return models.NewConflictError(fmt.Sprintf("order is %s, only draft orders can be modified", status))
+	}
+
+	for _, item := range items {
+		if item.Quantity == 0 {
+			query := "DELETE FROM order_items WHERE order_id = $1 AND product_id = $2 AND variant_id IS NOT DISTINCT FROM $3"
+			if _, err := tx.ExecContext(ctx, query, id, item.ProductID, item.VariantID); err != nil {
+				return err
+			}
+			continue
+		}
+		query := `INSERT INTO order_items (order_id, product_id, variant_id, quantity) VALUES ($1, $2, $3, $4)
+			ON CONFLICT (order_id, product_id, (COALESCE(variant_id, 0))) DO UPDATE SET quantity = EXCLUDED.quantity`
+		if _, err := tx.ExecContext(ctx, query, id, item.ProductID, item.VariantID, item.Quantity); err != nil {
+			return mapDBError(ctx, err)
+		}
+	}
+
+	query := `UPDATE orders SET note = COALESCE($2, note), promo_code = COALESCE($3, promo_code), updated_at = NOW()
+		WHERE id = $1`
+	if _, err := tx.ExecContext(ctx, query, id, note, promoCode); err != nil {
+		return mapDBError(ctx, err)
+	}
+
+	return tx.Commit()
+}
+
+func (repo *orderRepository) SetOrderStatus(ctx context.Context, id int, to string, from ...string) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, err := lockOrder(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(from, status) {
+		return models.NewConflictError(fmt.Sprintf("order is %s, cannot change it to %s", status, to))
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE orders SET status = $2, updated_at = NOW() WHERE id = $1", id, to); err != nil {
+		return mapDBError(ctx, err)
+	}
+
+	return tx.Commit()
+}
+
+func (repo *orderRepository) VoidOrder(ctx context.Context, id int, reason string, voidedBy int) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, err := lockOrder(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if status != models.OrderStatusDraft && status != models.OrderStatusHeld {
+		return models.NewConflictError(fmt.Sprintf("order is %s, only draft or held orders can be voided", status))
+	}
+
+	query := `UPDATE orders SET status = 'voided', void_reason = $2, voided_by = $3, voided_at = NOW(), updated_at = NOW()
+		WHERE id = $1`
+	if _, err := tx.ExecContext(ctx, query, id, reason, voidedBy); err != nil {
+		return mapDBError(ctx, err)
+	}
+
+	return tx.Commit()
+}
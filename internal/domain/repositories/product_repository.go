@@ -1,18 +1,40 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
-	"errors"
+	"fmt"
+	"kasir-api/internal/domain/barcode"
 	"kasir-api/internal/domain/models"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 // Interface digunakan sebagai kontrak
 type ProductRepository interface {
-	GetAllProduct(name string) ([]models.Product, error)
-	GetProductByID(id int) (*models.Product, error)
-	CreateProduct(product *models.Product) error
-	UpdateProduct(product *models.Product) error
-	DeleteProduct(id int) error
+	// GetAllProduct mengembalikan maksimal PerPage+1 produk (baris ekstra menandakan
+	// masih ada halaman berikutnya) beserta total produk yang cocok dengan filter
+	GetAllProduct(ctx context.Context, filter models.ProductFilter) ([]models.Product, int, error)
+	GetProductByID(ctx context.Context, id int) (*models.Product, error)
+	CreateProduct(ctx context.Context, product *models.Product) error
+	// UpdateProduct tidak mengubah stok; product.Stock diisi stok saat ini.
+	// Stok hanya berubah lewat ledger (ApplyMovement, checkout, dst).
+	UpdateProduct(ctx context.Context, product *models.Product) error
+	DeleteProduct(ctx context.Context, id int) error
+	// GetProductByBarcode mencari produk dari barcode yang sudah dinormalisasi
+	GetProductByBarcode(ctx context.Context, code string) (*models.Product, error)
+	// GetProductByPLU mencari produk dari kode PLU timbangan
+	GetProductByPLU(ctx context.Context, plu string) (*models.Product, error)
+	// AddInternalBarcode menambahkan barcode internal berprefix 04 ke produk
+	AddInternalBarcode(ctx context.Context, productID int) (string, error)
+	// SetProductOptions mengganti sumbu opsi produk dan memastikan ada satu
+	// varian aktif untuk setiap kombinasi; varian lain dinonaktifkan
+	SetProductOptions(ctx context.Context, productID int, options []models.ProductOption, combinations [][]string) error
+	// UpdateVariant mengubah SKU, harga, status aktif dan (jika tidak nil)
+	// barcode varian
+	UpdateVariant(ctx context.Context, variant *models.ProductVariant) error
 }
 
 // CONCRETE IMPLEMENTATION
@@ -25,62 +47,640 @@ func NewProductRepository(db *sql.DB) ProductRepository {
 	return &productRepository{db: db}
 }
 
-func (repo *productRepository) GetAllProduct(name string) ([]models.Product, error) {
-	query := "SELECT id, name, price, stock, category_id FROM products"
+// productSortColumns memetakan parameter sort ke kolom database
+var productSortColumns = map[string]string{
+	"name":  "name",
+	"price": "price",
+	"stock": "stock",
+}
+
+func (repo *productRepository) GetAllProduct(ctx context.Context, filter models.ProductFilter) ([]models.Product, int, error) {
+	conditions := []string{}
 	args := []interface{}{}
-	if name != "" {
-		query += " WHERE name ILIKE $1"
-		args = append(args, "%"+name+"%")
+	addCondition := func(condition string, values ...interface{}) {
+		placeholders := make([]interface{}, len(values))
+		for i, value := range values {
+			args = append(args, value)
+			placeholders[i] = len(args)
+		}
+		conditions = append(conditions, fmt.Sprintf(condition, placeholders...))
 	}
 
-	rows, err := repo.db.Query(query, args...)
+	if filter.Name != "" {
+		addCondition("name ILIKE $%d", "%"+filter.Name+"%")
+	}
+	if filter.CategoryID > 0 {
+		addCondition("category_id = $%d", filter.CategoryID)
+	}
+	if filter.MinPrice != nil {
+		addCondition("price >= $%d", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		addCondition("price <= $%d", *filter.MaxPrice)
+	}
+	if filter.InStock {
+		conditions = append(conditions, "stock > 0")
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	err := repo.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM products"+where, args...).Scan(&total)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	sortField := strings.TrimPrefix(filter.Sort, "-")
+	descending := strings.HasPrefix(filter.Sort, "-")
+	column := productSortColumns[sortField]
+
+	direction, operator := "ASC", ">"
+	if descending {
+		direction, operator = "DESC", "<"
+	}
+
+	if filter.Cursor != nil {
+		if column == "" {
+			addCondition("id "+operator+" $%d", filter.Cursor.ID)
+		} else {
+			var value interface{} = filter.Cursor.Value
+			switch column {
+			case "price":
+				number, err := strconv.Atoi(filter.Cursor.Value)
+				if err != nil {
+					return nil, 0, fmt.Errorf("invalid cursor value: %w", err)
+				}
+				value = number
+			case "stock":
+				stock, err := models.ParseQuantity(filter.Cursor.Value)
+				if err != nil {
+					return nil, 0, fmt.Errorf("invalid cursor value: %w", err)
+				}
+				value = stock
+			}
+			addCondition("("+column+", id) "+operator+" ($%d, $%d)", value, filter.Cursor.ID)
+		}
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	orderBy := " ORDER BY id " + direction
+	if column != "" {
+		orderBy = " ORDER BY " + column + " " + direction + ", id " + direction
+	}
+
+	query := `SELECT id, name, price, stock, category_id, tax_rate_bps, tax_exempt, sku, base_unit, weighable, plu, cost_price, costing_method
+		FROM products` + where + orderBy
+	args = append(args, filter.PerPage+1)
+	query += fmt.Sprintf(" LIMIT $%d", len(args))
+	if filter.Cursor == nil && filter.Page > 1 {
+		args = append(args, (filter.Page-1)*filter.PerPage)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	rows, err := repo.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
 	}
 	defer rows.Close()
 
 	products := make([]models.Product, 0)
 	for rows.Next() {
 		var p models.Product
-		if err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.Stock, &p.CategoryID); err != nil {
-			return nil, err
+		var taxRate sql.NullInt64
+		var sku, plu sql.NullString
+		p.CostPrice = new(int)
+		if err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.Stock, &p.CategoryID, &taxRate, &p.TaxExempt, &sku, &p.BaseUnit,
+			&p.Weighable, &plu, p.CostPrice, &p.CostingMethod); err != nil {
+			return nil, 0, err
 		}
+		p.TaxRateBps = nullIntPtr(taxRate)
+		p.SKU = sku.String
+		p.PLU = plu.String
+		p.FillMargins()
 		products = append(products, p)
 	}
-	return products, nil
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	ids := make([]int64, 0, len(products))
+	for _, p := range products {
+		ids = append(ids, int64(p.ID))
+	}
+	barcodes, err := repo.getBarcodes(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	units, err := repo.getUnits(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range products {
+		products[i].Barcodes = barcodes[products[i].ID]
+		if products[i].Barcodes == nil {
+			products[i].Barcodes = make([]string, 0)
+		}
+		products[i].Units = units[products[i].ID]
+		if products[i].Units == nil {
+			products[i].Units = make([]models.ProductUnit, 0)
+		}
+	}
+
+	return products, total, nil
 }
 
-func (repo productRepository) CreateProduct(product *models.Product) error {
-	query := "INSERT INTO products (name, price, stock, category_id) VALUES ($1, $2, $3, $4) RETURNING id"
-	err := repo.db.QueryRow(query, product.Name, product.Price, product.Stock, product.CategoryID).Scan(&product.ID)
+func (repo productRepository) CreateProduct(ctx context.Context, product *models.Product) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Produk dibuat dengan stok 0, stok awal dicatat sebagai restock di ledger
+	query := `INSERT INTO products (name, price, stock, category_id, tax_rate_bps, tax_exempt, sku, base_unit, weighable, plu,
+		cost_price, costing_method)
+		VALUES ($1, $2, 0, $3, $4, $5, $6, $7, $8, $9, COALESCE($10, 0), $11) RETURNING id`
+	err = tx.QueryRowContext(ctx, query, product.Name, product.Price, product.CategoryID, product.TaxRateBps, product.TaxExempt,
+		nullString(product.SKU), product.BaseUnit, product.Weighable, nullString(product.PLU), product.CostPrice,
+		product.CostingMethod).Scan(&product.ID)
+	if err != nil {
+		return mapDBError(ctx, err)
+	}
+
+	if err := replaceBarcodes(ctx, tx, product.ID, nil, product.Barcodes); err != nil {
+		return err
+	}
+	if err := replaceUnits(ctx, tx, product.ID, product.Units); err != nil {
+		return err
+	}
+
+	if product.Stock > 0 {
+		err = applyStockMovement(ctx, tx, &models.StockMovement{
+			ProductID: product.ID,
+			Delta:     product.Stock,
+			Reason:    models.StockReasonRestock,
+			Note:      "initial stock",
+		})
+		if err != nil {
+			return err
+		}
+	}
 
-	return err
+	return tx.Commit()
 }
 
-func (repo *productRepository) GetProductByID(id int) (*models.Product, error) {
-	query := "SELECT p.id, p.name, p.price, p.stock, p.category_id, c.id,  c.name, c.description FROM products p JOIN categories c ON c.id = p.category_id WHERE p.id = $1"
+func (repo *productRepository) GetProductByID(ctx context.Context, id int) (*models.Product, error) {
+	query := `SELECT p.id, p.name, p.price, p.stock, p.category_id, p.tax_rate_bps, p.tax_exempt, p.sku, p.base_unit, p.weighable, p.plu,
+		p.cost_price, p.costing_method, c.id, c.name, c.description, c.tax_rate_bps
+		FROM products p JOIN categories c ON c.id = p.category_id WHERE p.id = $1`
 
 	var p models.Product
+	var taxRate, categoryTaxRate sql.NullInt64
+	var sku, plu sql.NullString
 	p.Category = &models.Category{}
-	err := repo.db.QueryRow(query, id).Scan(&p.ID, &p.Name, &p.Price, &p.Stock, &p.CategoryID, &p.Category.ID, &p.Category.Name, &p.Category.Description)
+	p.CostPrice = new(int)
+	err := repo.db.QueryRowContext(ctx, query, id).Scan(&p.ID, &p.Name, &p.Price, &p.Stock, &p.CategoryID, &taxRate, &p.TaxExempt, &sku, &p.BaseUnit,
+		&p.Weighable, &plu, p.CostPrice, &p.CostingMethod, &p.Category.ID, &p.Category.Name, &p.Category.Description, &categoryTaxRate)
 	if err == sql.ErrNoRows {
-		return nil, errors.New("Product not found")
+		return nil, models.NewNotFoundError("product")
 	}
 	if err != nil {
 		return nil, err
 	}
+	p.TaxRateBps = nullIntPtr(taxRate)
+	p.Category.TaxRateBps = nullIntPtr(categoryTaxRate)
+	p.SKU = sku.String
+	p.PLU = plu.String
+
+	barcodes, err := repo.getBarcodes(ctx, []int64{int64(p.ID)})
+	if err != nil {
+		return nil, err
+	}
+	p.Barcodes = barcodes[p.ID]
+	if p.Barcodes == nil {
+		p.Barcodes = make([]string, 0)
+	}
+
+	units, err := repo.getUnits(ctx, []int64{int64(p.ID)})
+	if err != nil {
+		return nil, err
+	}
+	p.Units = units[p.ID]
+	if p.Units == nil {
+		p.Units = make([]models.ProductUnit, 0)
+	}
+
+	if p.Options, err = repo.getOptions(ctx, p.ID); err != nil {
+		return nil, err
+	}
+	if p.Variants, err = repo.getVariants(ctx, p.ID); err != nil {
+		return nil, err
+	}
+	p.FillMargins()
 
 	return &p, nil
 }
 
-func (repo productRepository) UpdateProduct(product *models.Product) error {
-	query := "UPDATE products SET name = $2, price = $3, stock = $4, category_id = $5 WHERE id = $1"
-	_, err := repo.db.Exec(query, product.ID, product.Name, product.Price, product.Stock, product.CategoryID)
-	return err
+func (repo productRepository) UpdateProduct(ctx context.Context, product *models.Product) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var currentStock models.Quantity
+	err = tx.QueryRowContext(ctx, "SELECT stock FROM products WHERE id = $1 FOR UPDATE", product.ID).Scan(&currentStock)
+	if err == sql.ErrNoRows {
+		return models.NewNotFoundError("product")
+	}
+	if err != nil {
+		return err
+	}
+
+	// cost_price yang dikirim menimpa harga pokok rata-rata (revaluasi),
+	// layer FIFO yang masih ada tetap dengan harga penerimaannya
+	query := `UPDATE products SET name = $2, price = $3, category_id = $4, tax_rate_bps = $5, tax_exempt = $6, sku = $7, base_unit = $8,
+		weighable = $9, plu = $10, cost_price = COALESCE($11, cost_price), costing_method = $12 WHERE id = $1`
+	if _, err := tx.ExecContext(ctx, query, product.ID, product.Name, product.Price, product.CategoryID, product.TaxRateBps, product.TaxExempt,
+		nullString(product.SKU), product.BaseUnit, product.Weighable, nullString(product.PLU), product.CostPrice, product.CostingMethod); err != nil {
+		return mapDBError(ctx, err)
+	}
+
+	if product.Barcodes != nil {
+		if err := replaceBarcodes(ctx, tx, product.ID, nil, product.Barcodes); err != nil {
+			return err
+		}
+	}
+	if product.Units != nil {
+		if err := replaceUnits(ctx, tx, product.ID, product.Units); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	product.Stock = currentStock
+	return nil
 }
 
-func (repo productRepository) DeleteProduct(id int) error {
+func (repo productRepository) DeleteProduct(ctx context.Context, id int) error {
 	query := "DELETE FROM products WHERE id = $1"
-	_, err := repo.db.Exec(query, id)
-	return err
+	result, err := repo.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return mapDBError(ctx, err)
+	}
+	return notFoundIfNoRows(result, "product")
+}
+
+func (repo *productRepository) GetProductByBarcode(ctx context.Context, code string) (*models.Product, error) {
+	var productID int
+	var variantID sql.NullInt64
+	err := repo.db.QueryRowContext(ctx, "SELECT product_id, variant_id FROM product_barcodes WHERE code = $1", code).Scan(&productID, &variantID)
+	if err == sql.ErrNoRows {
+		return nil, models.NewNotFoundError("product")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := repo.GetProductByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	product.VariantID = nullIntPtr(variantID)
+	return product, nil
+}
+
+func (repo *productRepository) GetProductByPLU(ctx context.Context, plu string) (*models.Product, error) {
+	var productID int
+	err := repo.db.QueryRowContext(ctx, "SELECT id FROM products WHERE plu = $1", plu).Scan(&productID)
+	if err == sql.ErrNoRows {
+		return nil, models.NewNotFoundError("product")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return repo.GetProductByID(ctx, productID)
+}
+
+func (repo *productRepository) AddInternalBarcode(ctx context.Context, productID int) (string, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRowContext(ctx, "SELECT id FROM products WHERE id = $1 FOR UPDATE", productID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", models.NewNotFoundError("product")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var sequence int64
+	if err := tx.QueryRowContext(ctx, "SELECT nextval('internal_barcode_seq')").Scan(&sequence); err != nil {
+		return "", err
+	}
+	code, err := barcode.Internal(sequence)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := tx.ExecContext(ctx, "INSERT INTO product_barcodes (code, product_id) VALUES ($1, $2)", code, productID); err != nil {
+		if isUniqueViolation(err) {
+			return "", models.NewConflictError(fmt.Sprintf("barcode %s is already assigned to another product", code))
+		}
+		return "", mapDBError(ctx, err)
+	}
+
+	return code, tx.Commit()
+}
+
+// getBarcodes memuat barcode beberapa produk sekaligus, dikelompokkan per produk
+func (repo *productRepository) getBarcodes(ctx context.Context, productIDs []int64) (map[int][]string, error) {
+	barcodes := make(map[int][]string)
+	if len(productIDs) == 0 {
+		return barcodes, nil
+	}
+
+	query := `SELECT product_id, code FROM product_barcodes WHERE product_id = ANY($1) AND variant_id IS NULL
+		ORDER BY product_id, created_at, code`
+	rows, err := repo.db.QueryContext(ctx, query, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID int
+		var code string
+		if err := rows.Scan(&productID, &code); err != nil {
+			return nil, err
+		}
+		barcodes[productID] = append(barcodes[productID], code)
+	}
+	return barcodes, rows.Err()
+}
+
+// replaceBarcodes menyamakan barcode produk (atau varian jika variantID
+// tidak nil) dengan codes: barcode yang tidak ada di codes dihapus, yang baru
+// ditambahkan. Barcode milik produk atau varian lain ditolak dengan
+// ConflictError.
+func replaceBarcodes(ctx context.Context, tx *sql.Tx, productID int, variantID *int, codes []string) error {
+	query := "SELECT code FROM product_barcodes WHERE product_id = $1 AND variant_id IS NOT DISTINCT FROM $2"
+	rows, err := tx.QueryContext(ctx, query, productID, variantID)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			rows.Close()
+			return err
+		}
+		existing[code] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if codes == nil {
+		codes = make([]string, 0)
+	}
+	query = "DELETE FROM product_barcodes WHERE product_id = $1 AND variant_id IS NOT DISTINCT FROM $2 AND NOT (code = ANY($3))"
+	if _, err := tx.ExecContext(ctx, query, productID, variantID, codes); err != nil {
+		return err
+	}
+
+	for _, code := range codes {
+		if existing[code] {
+			continue
+		}
+		query := "INSERT INTO product_barcodes (code, product_id, variant_id) VALUES ($1, $2, $3)"
+		if _, err := tx.ExecContext(ctx, query, code, productID, variantID); err != nil {
+			if isUniqueViolation(err) {
+				return models.NewConflictError(fmt.Sprintf("barcode %s is already assigned to another product", code))
+			}
+			return mapDBError(ctx, err)
+		}
+	}
+	return nil
+}
+
+// getUnits memuat satuan tambahan beberapa produk sekaligus, dikelompokkan
+// per produk dan diurutkan dari factor terkecil
+func (repo *productRepository) getUnits(ctx context.Context, productIDs []int64) (map[int][]models.ProductUnit, error) {
+	units := make(map[int][]models.ProductUnit)
+	if len(productIDs) == 0 {
+		return units, nil
+	}
+
+	query := "SELECT product_id, name, factor FROM product_units WHERE product_id = ANY($1) ORDER BY product_id, factor, name"
+	rows, err := repo.db.QueryContext(ctx, query, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID int
+		var unit models.ProductUnit
+		if err := rows.Scan(&productID, &unit.Name, &unit.Factor); err != nil {
+			return nil, err
+		}
+		units[productID] = append(units[productID], unit)
+	}
+	return units, rows.Err()
+}
+
+// replaceUnits mengganti semua satuan tambahan produk dengan units. Stok
+// selalu dalam satuan dasar, jadi mengubah konversi tidak mengubah stok.
+func replaceUnits(ctx context.Context, tx *sql.Tx, productID int, units []models.ProductUnit) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM product_units WHERE product_id = $1", productID); err != nil {
+		return err
+	}
+	for _, unit := range units {
+		query := "INSERT INTO product_units (product_id, name, factor) VALUES ($1, $2, $3)"
+		if _, err := tx.ExecContext(ctx, query, productID, unit.Name, unit.Factor); err != nil {
+			return mapDBError(ctx, err)
+		}
+	}
+	return nil
+}
+
+func (repo *productRepository) SetProductOptions(ctx context.Context, productID int, options []models.ProductOption, combinations [][]string) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var price int
+	var stock models.Quantity
+	err = tx.QueryRowContext(ctx, "SELECT price, stock FROM products WHERE id = $1 FOR UPDATE", productID).Scan(&price, &stock)
+	if err == sql.ErrNoRows {
+		return models.NewNotFoundError("product")
+	}
+	if err != nil {
+		return err
+	}
+
+	// Stok produk tanpa varian tidak bisa dibagi otomatis ke varian, jadi
+	// harus dikosongkan lewat adjustment dulu supaya ledger tetap konsisten
+	hasVariants, err := productHasVariants(ctx, tx, productID)
+	if err != nil {
+		return err
+	}
+	if !hasVariants && stock > 0 {
+		return models.NewConflictError(fmt.Sprintf("product still has %s units of stock without variant, adjust it to 0 first", stock))
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM product_options WHERE product_id = $1", productID); err != nil {
+		return err
+	}
+	for i, option := range options {
+		query := "INSERT INTO product_options (product_id, name, position, option_values) VALUES ($1, $2, $3, $4)"
+		if _, err := tx.ExecContext(ctx, query, productID, option.Name, i, option.Values); err != nil {
+			return mapDBError(ctx, err)
+		}
+	}
+
+	// Kombinasi yang sudah pernah ada diaktifkan kembali dengan harga dan stok
+	// lamanya, kombinasi baru mulai dari harga produk dan stok 0
+	keep := make([]int64, 0, len(combinations))
+	for _, values := range combinations {
+		var id int64
+		query := `INSERT INTO product_variants (product_id, option_values, price) VALUES ($1, $2, $3)
+			ON CONFLICT (product_id, option_values) DO UPDATE SET active = TRUE RETURNING id`
+		if err := tx.QueryRowContext(ctx, query, productID, values, price).Scan(&id); err != nil {
+			return mapDBError(ctx, err)
+		}
+		keep = append(keep, id)
+	}
+
+	query := "UPDATE product_variants SET active = FALSE WHERE product_id = $1 AND NOT (id = ANY($2))"
+	if _, err := tx.ExecContext(ctx, query, productID, keep); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (repo *productRepository) UpdateVariant(ctx context.Context, variant *models.ProductVariant) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := "UPDATE product_variants SET sku = $3, price = $4, active = $5 WHERE id = $1 AND product_id = $2"
+	result, err := tx.ExecContext(ctx, query, variant.ID, variant.ProductID, nullString(variant.SKU), variant.Price, variant.Active)
+	if err != nil {
+		return mapDBError(ctx, err)
+	}
+	if err := notFoundIfNoRows(result, "variant"); err != nil {
+		return err
+	}
+
+	if variant.Barcodes != nil {
+		if err := replaceBarcodes(ctx, tx, variant.ProductID, &variant.ID, variant.Barcodes); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// getOptions memuat sumbu opsi produk sesuai urutan position
+func (repo *productRepository) getOptions(ctx context.Context, productID int) ([]models.ProductOption, error) {
+	query := "SELECT name, option_values FROM product_options WHERE product_id = $1 ORDER BY position"
+	rows, err := repo.db.QueryContext(ctx, query, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var options []models.ProductOption
+	for rows.Next() {
+		var option models.ProductOption
+		if err := rows.Scan(&option.Name, textArray(&option.Values)); err != nil {
+			return nil, err
+		}
+		options = append(options, option)
+	}
+	return options, rows.Err()
+}
+
+// getVariants memuat matriks varian produk beserta barcode masing-masing.
+// Varian nonaktif ikut dimuat supaya stok sisanya tetap terlihat.
+func (repo *productRepository) getVariants(ctx context.Context, productID int) ([]models.ProductVariant, error) {
+	query := `SELECT id, product_id, option_values, sku, price, stock, active FROM product_variants
+		WHERE product_id = $1 ORDER BY active DESC, id`
+	rows, err := repo.db.QueryContext(ctx, query, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	var variants []models.ProductVariant
+	index := make(map[int]int)
+	for rows.Next() {
+		var v models.ProductVariant
+		var sku sql.NullString
+		if err := rows.Scan(&v.ID, &v.ProductID, textArray(&v.OptionValues), &sku, &v.Price, &v.Stock, &v.Active); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		v.Name = models.VariantName(v.OptionValues)
+		v.SKU = sku.String
+		v.Barcodes = make([]string, 0)
+		index[v.ID] = len(variants)
+		variants = append(variants, v)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(variants) == 0 {
+		return nil, nil
+	}
+
+	query = "SELECT variant_id, code FROM product_barcodes WHERE product_id = $1 AND variant_id IS NOT NULL ORDER BY created_at, code"
+	rows, err = repo.db.QueryContext(ctx, query, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var variantID int
+		var code string
+		if err := rows.Scan(&variantID, &code); err != nil {
+			return nil, err
+		}
+		if i, ok := index[variantID]; ok {
+			variants[i].Barcodes = append(variants[i].Barcodes, code)
+		}
+	}
+	return variants, rows.Err()
+}
+
+// nullString menyimpan string kosong sebagai NULL supaya unique index
+// parsial tidak menganggap dua nilai kosong sebagai duplikat
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// textArray membungkus dest sebagai sql.Scanner untuk kolom TEXT[], karena
+// database/sql tidak bisa men-scan array PostgreSQL langsung ke []string.
+// Map dibuat per pemanggilan karena pgtype.Map tidak aman dipakai bersamaan.
+func textArray(dest *[]string) sql.Scanner {
+	return pgtype.NewMap().SQLScanner(dest)
 }
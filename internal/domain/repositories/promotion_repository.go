@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"kasir-api/internal/domain/models"
+	"time"
+)
+
+type PromotionRepository interface {
+	GetAllPromotions(ctx context.Context) ([]models.Promotion, error)
+	// GetActivePromotions mengembalikan promo aktif yang periode tanggalnya
+	// mencakup now. Jam harian, kode dan min spend dicek oleh package pricing.
+	GetActivePromotions(ctx context.Context, now time.Time) ([]models.Promotion, error)
+	GetPromotionByID(ctx context.Context, id int) (*models.Promotion, error)
+	CreatePromotion(ctx context.Context, promotion *models.Promotion) error
+	UpdatePromotion(ctx context.Context, promotion *models.Promotion) error
+	DeletePromotion(ctx context.Context, id int) error
+}
+
+type promotionRepository struct {
+	db *sql.DB
+}
+
+func NewPromotionRepository(db *sql.DB) PromotionRepository {
+	return &promotionRepository{db: db}
+}
+
+const promotionColumns = `id, name, type, scope, product_id, category_id, value, buy_quantity, get_quantity,
+	min_spend, starts_at, ends_at, daily_start_time, daily_end_time, code, active, created_at`
+
+func scanPromotion(row interface{ Scan(...any) error }) (*models.Promotion, error) {
+	var p models.Promotion
+	var productID, categoryID sql.NullInt64
+	var startsAt, endsAt sql.NullTime
+	err := row.Scan(&p.ID, &p.Name, &p.Type, &p.Scope, &productID, &categoryID, &p.Value, &p.BuyQuantity, &p.GetQuantity,
+		&p.MinSpend, &startsAt, &endsAt, &p.DailyStartTime, &p.DailyEndTime, &p.Code, &p.Active, &p.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	p.ProductID = nullIntPtr(productID)
+	p.CategoryID = nullIntPtr(categoryID)
+	if startsAt.Valid {
+		p.StartsAt = &startsAt.Time
+	}
+	if endsAt.Valid {
+		p.EndsAt = &endsAt.Time
+	}
+	return &p, nil
+}
+
+func (repo *promotionRepository) queryPromotions(ctx context.Context, query string, args ...any) ([]models.Promotion, error) {
+	rows, err := repo.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	promotions := make([]models.Promotion, 0)
+	for rows.Next() {
+		p, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, *p)
+	}
+	return promotions, rows.Err()
+}
+
+func (repo *promotionRepository) GetAllPromotions(ctx context.Context) ([]models.Promotion, error) {
+	return repo.queryPromotions(ctx, "SELECT "+promotionColumns+" FROM promotions ORDER BY id")
+}
+
+func (repo *promotionRepository) GetActivePromotions(ctx context.Context, now time.Time) ([]models.Promotion, error) {
+	query := "SELECT " + promotionColumns + ` FROM promotions
+		WHERE active AND (starts_at IS NULL OR starts_at <= $1) AND (ends_at IS NULL OR ends_at > $1)
+		ORDER BY id`
+	return repo.queryPromotions(ctx, query, now)
+}
+
+func (repo *promotionRepository) GetPromotionByID(ctx context.Context, id int) (*models.Promotion, error) {
+	p, err := scanPromotion(repo.db.QueryRowContext(ctx, "SELECT "+promotionColumns+" FROM promotions WHERE id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, models.NewNotFoundError("promotion")
+	}
+	return p, err
+}
+
+func (repo *promotionRepository) CreatePromotion(ctx context.Context, p *models.Promotion) error {
+	query := `INSERT INTO promotions (name, type, scope, product_id, category_id, value, buy_quantity, get_quantity,
+		min_spend, starts_at, ends_at, daily_start_time, daily_end_time, code, active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		RETURNING id, created_at`
+	err := repo.db.QueryRowContext(ctx, query, p.Name, p.Type, p.Scope, p.ProductID, p.CategoryID, p.Value, p.BuyQuantity,
+		p.GetQuantity, p.MinSpend, p.StartsAt, p.EndsAt, p.DailyStartTime, p.DailyEndTime, p.Code, p.Active).
+		Scan(&p.ID, &p.CreatedAt)
+	if isUniqueViolation(err) {
+		return models.NewConflictError("promo code already exists")
+	}
+	return mapDBError(ctx, err)
+}
+
+func (repo *promotionRepository) UpdatePromotion(ctx context.Context, p *models.Promotion) error {
+	query := `UPDATE promotions SET name = $2, type = $3, scope = $4, product_id = $5, category_id = $6, value = $7,
+		buy_quantity = $8, get_quantity = $9, min_spend = $10, starts_at = $11, ends_at = $12,
+		daily_start_time = $13, daily_end_time = $14, code = $15, active = $16
+		WHERE id = $1`
+	result, err := repo.db.ExecContext(ctx, query, p.ID, p.Name, p.Type, p.Scope, p.ProductID, p.CategoryID, p.Value,
+		p.BuyQuantity, p.GetQuantity, p.MinSpend, p.StartsAt, p.EndsAt, p.DailyStartTime, p.DailyEndTime, p.Code, p.Active)
+	if isUniqueViolation(err) {
+		return models.NewConflictError("promo code already exists")
+	}
+	if err != nil {
+		return mapDBError(ctx, err)
+	}
+	return notFoundIfNoRows(result, "promotion")
+}
+
+func (repo *promotionRepository) DeletePromotion(ctx context.Context, id int) error {
+	result, err := repo.db.ExecContext(ctx, "DELETE FROM promotions WHERE id = $1", id)
+	if err != nil {
+		return mapDBError(ctx, err)
+	}
+	return notFoundIfNoRows(result, "promotion")
+}
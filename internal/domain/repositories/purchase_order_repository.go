@@ -0,0 +1,432 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"kasir-api/internal/domain/models"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type PurchaseOrderRepository interface {
+	CreatePurchaseOrder(ctx context.Context, po *models.PurchaseOrder) error
+	// GetPurchaseOrderByID mengembalikan PO beserta baris dan riwayat goods receipt
+	GetPurchaseOrderByID(ctx context.Context, id int) (*models.PurchaseOrder, error)
+	// GetPurchaseOrders mengembalikan PO terbaru dulu beserta total untuk pagination
+	GetPurchaseOrders(ctx context.Context, filter models.PurchaseOrderFilter) ([]models.PurchaseOrder, int, error)
+	// UpdatePurchaseOrder menimpa supplier, note dan semua baris PO draft
+	UpdatePurchaseOrder(ctx context.Context, po *models.PurchaseOrder) error
+	// SetPurchaseOrderStatus mengubah status PO jika status saat ini termasuk from
+	SetPurchaseOrderStatus(ctx context.Context, id int, to string, from ...string) error
+	// ReceiveGoods mencatat goods receipt, menambah stok lewat ledger dan
+	// memperbarui status PO dalam satu database transaction
+	ReceiveGoods(ctx context.Context, receipt *models.GoodsReceipt) error
+}
+
+type purchaseOrderRepository struct {
+	db *sql.DB
+}
+
+func NewPurchaseOrderRepository(db *sql.DB) PurchaseOrderRepository {
+	return &purchaseOrderRepository{db: db}
+}
+
+const purchaseOrderColumns = `po.id, po.supplier_id, s.name, po.user_id, po.status, po.note, po.total_amount, po.sent_at,
+	po.created_at, po.updated_at`
+
+func (repo *purchaseOrderRepository) CreatePurchaseOrder(ctx context.Context, po *models.PurchaseOrder) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO purchase_orders (supplier_id, user_id, status, note, total_amount) VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at`
+	err = tx.QueryRowContext(ctx, query, po.SupplierID, po.UserID, po.Status, po.Note, po.TotalAmount).
+		Scan(&po.ID, &po.CreatedAt, &po.UpdatedAt)
+	if err != nil {
+		return mapDBError(ctx, err)
+	}
+
+	if err := insertPurchaseOrderLines(ctx, tx, po.ID, po.Lines); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func insertPurchaseOrderLines(ctx context.Context, tx *sql.Tx, purchaseOrderID int, lines []models.PurchaseOrderLine) error {
+	query := `INSERT INTO purchase_order_lines (purchase_order_id, product_id, variant_id, unit, factor, quantity, unit_cost)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	for _, line := range lines {
+		_, err := tx.ExecContext(ctx, query, purchaseOrderID, line.ProductID, line.VariantID, line.Unit, line.Factor, line.Quantity, line.UnitCost)
+		if err != nil {
+			return mapDBError(ctx, err)
+		}
+	}
+	return nil
+}
+
+func (repo *purchaseOrderRepository) GetPurchaseOrderByID(ctx context.Context, id int) (*models.PurchaseOrder, error) {
+	query := "SELECT " + purchaseOrderColumns + " FROM purchase_orders po JOIN suppliers s ON s.id = po.supplier_id WHERE po.id = $1"
+	orders, err := repo.queryPurchaseOrders(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return nil, models.NewNotFoundError("purchase order")
+	}
+
+	po := &orders[0]
+	po.Receipts, err = repo.getGoodsReceipts(ctx, po.ID)
+	if err != nil {
+		return nil, err
+	}
+	return po, nil
+}
+
+func (repo *purchaseOrderRepository) GetPurchaseOrders(ctx context.Context, filter models.PurchaseOrderFilter) ([]models.PurchaseOrder, int, error) {
+	conditions := []string{"TRUE"}
+	args := make([]any, 0, 4)
+	if filter.SupplierID != 0 {
+		args = append(args, filter.SupplierID)
+		conditions = append(conditions, "po.supplier_id = $"+strconv.Itoa(len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, "po.status = $"+strconv.Itoa(len(args)))
+	}
+	where := strings.Join(conditions, " AND ")
+
+	var total int
+	if err := repo.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM purchase_orders po WHERE "+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.PerPage, (filter.Page-1)*filter.PerPage)
+	query := "SELECT " + purchaseOrderColumns + " FROM purchase_orders po JOIN suppliers s ON s.id = po.supplier_id WHERE " + where +
+		fmt.Sprintf(" ORDER BY po.created_at DESC, po.id DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+	orders, err := repo.queryPurchaseOrders(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return orders, total, nil
+}
+
+// queryPurchaseOrders menjalankan query header PO lalu memuat baris semua PO
+// tersebut dengan satu query tambahan
+func (repo *purchaseOrderRepository) queryPurchaseOrders(ctx context.Context, query string, args ...any) ([]models.PurchaseOrder, error) {
+	rows, err := repo.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := make([]models.PurchaseOrder, 0)
+	index := make(map[int]int)
+	for rows.Next() {
+		var po models.PurchaseOrder
+		var userID sql.NullInt64
+		var sentAt sql.NullTime
+		err := rows.Scan(&po.ID, &po.SupplierID, &po.SupplierName, &userID, &po.Status, &po.Note, &po.TotalAmount, &sentAt,
+			&po.CreatedAt, &po.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		po.UserID = nullIntPtr(userID)
+		if sentAt.Valid {
+			po.SentAt = &sentAt.Time
+		}
+		po.Lines = make([]models.PurchaseOrderLine, 0)
+		index[po.ID] = len(orders)
+		orders = append(orders, po)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return orders, nil
+	}
+
+	ids := make([]int64, 0, len(orders))
+	for _, po := range orders {
+		ids = append(ids, int64(po.ID))
+	}
+	lineQuery := `SELECT l.purchase_order_id, l.id, l.product_id, p.name, l.variant_id, v.option_values, l.unit, l.factor,
+		l.quantity, l.unit_cost, l.received_quantity
+		FROM purchase_order_lines l JOIN products p ON p.id = l.product_id
+		LEFT JOIN product_variants v ON v.id = l.variant_id
+		WHERE l.purchase_order_id = ANY($1) ORDER BY l.purchase_order_id, l.id`
+	lineRows, err := repo.db.QueryContext(ctx, lineQuery, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer lineRows.Close()
+
+	for lineRows.Next() {
+		var purchaseOrderID int
+		var line models.PurchaseOrderLine
+		var variantID sql.NullInt64
+		var optionValues []string
+		if err := lineRows.Scan(&purchaseOrderID, &line.ID, &line.ProductID, &line.ProductName, &variantID, textArray(&optionValues),
+			&line.Unit, &line.Factor, &line.Quantity, &line.UnitCost, &line.ReceivedQuantity); err != nil {
+			return nil, err
+		}
+		line.VariantID = nullIntPtr(variantID)
+		if line.VariantID != nil {
+			line.VariantName = models.VariantName(optionValues)
+		}
+		line.Subtotal = line.Quantity.Amount(line.UnitCost)
+		po := &orders[index[purchaseOrderID]]
+		po.Lines = append(po.Lines, line)
+	}
+	return orders, lineRows.Err()
+}
+
+// getGoodsReceipts memuat semua goods receipt sebuah PO, yang paling lama dulu
+func (repo *purchaseOrderRepository) getGoodsReceipts(ctx context.Context, purchaseOrderID int) ([]models.GoodsReceipt, error) {
+	rows, err := repo.db.QueryContext(ctx, `SELECT id, user_id, note, created_at FROM goods_receipts
+		WHERE purchase_order_id = $1 ORDER BY id`, purchaseOrderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	receipts := make([]models.GoodsReceipt, 0)
+	index := make(map[int]int)
+	for rows.Next() {
+		receipt := models.GoodsReceipt{PurchaseOrderID: purchaseOrderID, Lines: make([]models.GoodsReceiptLine, 0)}
+		var userID sql.NullInt64
+		if err := rows.Scan(&receipt.ID, &userID, &receipt.Note, &receipt.CreatedAt); err != nil {
+			return nil, err
+		}
+		receipt.UserID = nullIntPtr(userID)
+		index[receipt.ID] = len(receipts)
+		receipts = append(receipts, receipt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(receipts) == 0 {
+		return receipts, nil
+	}
+
+	lineQuery := `SELECT rl.goods_receipt_id, rl.purchase_order_line_id, l.product_id, l.variant_id, rl.quantity, rl.unit_cost,
+		rl.stock_movement_id
+		FROM goods_receipt_lines rl
+		JOIN goods_receipts r ON r.id = rl.goods_receipt_id
+		JOIN purchase_order_lines l ON l.id = rl.purchase_order_line_id
+		WHERE r.purchase_order_id = $1 ORDER BY rl.goods_receipt_id, rl.id`
+	lineRows, err := repo.db.QueryContext(ctx, lineQuery, purchaseOrderID)
+	if err != nil {
+		return nil, err
+	}
+	defer lineRows.Close()
+
+	for lineRows.Next() {
+		var receiptID int
+		var line models.GoodsReceiptLine
+		var variantID, movementID sql.NullInt64
+		if err := lineRows.Scan(&receiptID, &line.LineID, &line.ProductID, &variantID, &line.Quantity, &line.UnitCost,
+			&movementID); err != nil {
+			return nil, err
+		}
+		line.VariantID = nullIntPtr(variantID)
+		line.StockMovementID = nullIntPtr(movementID)
+		receipt := &receipts[index[receiptID]]
+		receipt.Lines = append(receipt.Lines, line)
+	}
+	return receipts, lineRows.Err()
+}
+
+// lockPurchaseOrder mengunci PO dengan FOR UPDATE dan mengembalikan statusnya
+func lockPurchaseOrder(ctx context.Context, tx *sql.Tx, id int) (string, error) {
+	var status string
+	err := tx.QueryRowContext(ctx, "SELECT status FROM purchase_orders WHERE id = $1 FOR UPDATE", id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return "", models.NewNotFoundError("purchase order")
+	}
+	return status, err
+}
+
+func (repo *purchaseOrderRepository) UpdatePurchaseOrder(ctx context.Context, po *models.PurchaseOrder) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, err := lockPurchaseOrder(ctx, tx, po.ID)
+	if err != nil {
+		return err
+	}
+	if status != models.PurchaseOrderStatusDraft {
+		return models.NewConflictError(fmt.Sprintf("purchase order is %s, only draft purchase orders can be modified", status))
+	}
+
+	query := `UPDATE purchase_orders SET supplier_id = $2, note = $3, total_amount = $4, updated_at = NOW() WHERE id = $1`
+	if _, err := tx.ExecContext(ctx, query, po.ID, po.SupplierID, po.Note, po.TotalAmount); err != nil {
+		return mapDBError(ctx, err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM purchase_order_lines WHERE purchase_order_id = $1", po.ID); err != nil {
+		return err
+	}
+	if err := insertPurchaseOrderLines(ctx, tx, po.ID, po.Lines); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (repo *purchaseOrderRepository) SetPurchaseOrderStatus(ctx context.Context, id int, to string, from ...string) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, err := lockPurchaseOrder(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(from, status) {
+		return models.NewConflictError(fmt.Sprintf("purchase order is %s, cannot change it to %s", status, to))
+	}
+
+	query := `UPDATE purchase_orders SET status = $2, updated_at = NOW(),
+		sent_at = CASE WHEN $2 = 'sent' THEN NOW() ELSE sent_at END
+		WHERE id = $1`
+	if _, err := tx.ExecContext(ctx, query, id, to); err != nil {
+		return mapDBError(ctx, err)
+	}
+
+	return tx.Commit()
+}
+
+// receivableLine adalah baris PO yang dikunci saat goods receipt
+type receivableLine struct {
+	productID int
+	variantID *int
+	factor    int
+	quantity  models.Quantity
+	received  models.Quantity
+}
+
+func (repo *purchaseOrderRepository) ReceiveGoods(ctx context.Context, receipt *models.GoodsReceipt) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, err := lockPurchaseOrder(ctx, tx, receipt.PurchaseOrderID)
+	if err != nil {
+		return err
+	}
+	if status != models.PurchaseOrderStatusSent && status != models.PurchaseOrderStatusPartiallyReceived {
+		return models.NewConflictError(fmt.Sprintf("purchase order is %s, only sent or partially received purchase orders can be received", status))
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT id, product_id, variant_id, factor, quantity, received_quantity
+		FROM purchase_order_lines WHERE purchase_order_id = $1 ORDER BY id FOR UPDATE`, receipt.PurchaseOrderID)
+	if err != nil {
+		return err
+	}
+	lines := make(map[int]*receivableLine)
+	for rows.Next() {
+		var id int
+		var line receivableLine
+		var variantID sql.NullInt64
+		if err := rows.Scan(&id, &line.productID, &variantID, &line.factor, &line.quantity, &line.received); err != nil {
+			rows.Close()
+			return err
+		}
+		line.variantID = nullIntPtr(variantID)
+		lines[id] = &line
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO goods_receipts (purchase_order_id, user_id, note) VALUES ($1, $2, $3) RETURNING id, created_at`
+	err = tx.QueryRowContext(ctx, query, receipt.PurchaseOrderID, receipt.UserID, receipt.Note).Scan(&receipt.ID, &receipt.CreatedAt)
+	if err != nil {
+		return mapDBError(ctx, err)
+	}
+
+	// Stok diubah urut produk supaya urutan lock baris produk sama dengan checkout
+	for i := range receipt.Lines {
+		line, ok := lines[receipt.Lines[i].LineID]
+		if !ok {
+			return models.NewNotFoundError(fmt.Sprintf("line %d of purchase order %d", receipt.Lines[i].LineID, receipt.PurchaseOrderID))
+		}
+		receipt.Lines[i].ProductID = line.productID
+		receipt.Lines[i].VariantID = line.variantID
+	}
+	sort.SliceStable(receipt.Lines, func(i, j int) bool {
+		a, b := receipt.Lines[i], receipt.Lines[j]
+		if a.ProductID != b.ProductID {
+			return a.ProductID < b.ProductID
+		}
+		return variantIDOrZero(a.VariantID) < variantIDOrZero(b.VariantID)
+	})
+
+	for i := range receipt.Lines {
+		receiptLine := &receipt.Lines[i]
+		line := lines[receiptLine.LineID]
+		if remaining := line.quantity - line.received; receiptLine.Quantity > remaining {
+			return models.NewConflictError(fmt.Sprintf("line %d only has %s left to receive, got %s", receiptLine.LineID, remaining, receiptLine.Quantity))
+		}
+
+		// Harga per satuan dasar dibulatkan dan hanya dipakai untuk harga
+		// pokok rata-rata; nilai persediaan dan layer FIFO memakai nilai yang
+		// dibayar, mis. 1 karton isi 12 seharga 10.000 tetap bernilai 10.000
+		unitCost := models.BaseUnitCost(receiptLine.UnitCost, line.factor)
+		paid := receiptLine.Quantity.Amount(receiptLine.UnitCost)
+		movement := &models.StockMovement{
+			ProductID:    line.productID,
+			VariantID:    line.variantID,
+			Delta:        receiptLine.Quantity * models.Quantity(line.factor),
+			Reason:       models.StockReasonRestock,
+			ReferenceID:  fmt.Sprintf("PO-%d", receipt.PurchaseOrderID),
+			Note:         fmt.Sprintf("goods receipt #%d", receipt.ID),
+			UnitCost:     &unitCost,
+			InboundValue: &paid,
+			UserID:       receipt.UserID,
+		}
+		if err := applyStockMovement(ctx, tx, movement); err != nil {
+			return err
+		}
+		receiptLine.StockMovementID = &movement.ID
+		line.received += receiptLine.Quantity
+
+		query := "UPDATE purchase_order_lines SET received_quantity = $2 WHERE id = $1"
+		if _, err := tx.ExecContext(ctx, query, receiptLine.LineID, line.received); err != nil {
+			return mapDBError(ctx, err)
+		}
+		query = `INSERT INTO goods_receipt_lines (goods_receipt_id, purchase_order_line_id, quantity, unit_cost, stock_movement_id)
+			VALUES ($1, $2, $3, $4, $5)`
+		if _, err := tx.ExecContext(ctx, query, receipt.ID, receiptLine.LineID, receiptLine.Quantity, receiptLine.UnitCost, movement.ID); err != nil {
+			return mapDBError(ctx, err)
+		}
+	}
+
+	newStatus := models.PurchaseOrderStatusReceived
+	for _, line := range lines {
+		if line.received < line.quantity {
+			newStatus = models.PurchaseOrderStatusPartiallyReceived
+			break
+		}
+	}
+	query = "UPDATE purchase_orders SET status = $2, updated_at = NOW() WHERE id = $1"
+	if _, err := tx.ExecContext(ctx, query, receipt.PurchaseOrderID, newStatus); err != nil {
+		return mapDBError(ctx, err)
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,296 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/pkg"
+
+	"github.com/sirupsen/logrus"
+)
+
+type RefundRepository interface {
+	// CreateRefund menyimpan refund untuk transaksi refund.TransactionID atas
+	// nama userID. refund.Items hanya perlu berisi TransactionItemID dan
+	// Quantity; Items kosong berarti semua unit yang belum di-refund.
+	CreateRefund(ctx context.Context, userID int, refund *models.Refund) error
+	GetRefundByID(ctx context.Context, id int) (*models.Refund, error)
+}
+
+type refundRepository struct {
+	db *sql.DB
+}
+
+func NewRefundRepository(db *sql.DB) RefundRepository {
+	return &refundRepository{db: db}
+}
+
+// refundableLine adalah baris transaksi beserta jumlah yang sudah di-refund
+type refundableLine struct {
+	id             int
+	productID      int
+	variantID      *int
+	productName    string
+	weighable      bool
+	quantity       models.Quantity
+	totalAmount    int
+	costAmount     int
+	refundedQty    models.Quantity
+	refundedAmount int
+	refundedCost   int
+}
+
+// refundAmount menghitung nilai refund untuk quantity unit sebuah baris secara
+// proporsional (dibulatkan ke bawah). Refund yang menghabiskan sisa unit
+// mendapat sisa nilai baris, sehingga total semua refund sebuah baris selalu
+// tepat sama dengan yang dibayar pelanggan.
+func refundAmount(line refundableLine, quantity models.Quantity) int {
+	if line.refundedQty+quantity == line.quantity {
+		return line.totalAmount - line.refundedAmount
+	}
+	return int(int64(line.totalAmount) * int64(quantity) / int64(line.quantity))
+}
+
+// refundCost menghitung porsi HPP baris untuk quantity unit, dengan
+// pembagian yang sama seperti refundAmount
+func refundCost(line refundableLine, quantity models.Quantity) int {
+	if line.refundedQty+quantity == line.quantity {
+		return line.costAmount - line.refundedCost
+	}
+	return int(int64(line.costAmount) * int64(quantity) / int64(line.quantity))
+}
+
+// CreateRefund mengunci transaksi dengan FOR UPDATE supaya dua refund untuk
+// transaksi yang sama tidak bisa melewati batas jumlah terjual bersamaan
+func (repo *refundRepository) CreateRefund(ctx context.Context, userID int, refund *models.Refund) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// uang refund keluar dari laci shift kasir yang sedang dibuka
+	var shiftID int
+	err = tx.QueryRowContext(ctx, "SELECT id FROM shifts WHERE user_id = $1 AND status = 'open' FOR SHARE", userID).Scan(&shiftID)
+	if err == sql.ErrNoRows {
+		return models.NewConflictError("no open shift, open a shift before refund")
+	}
+	if err != nil {
+		return err
+	}
+
+	var transactionID int
+	err = tx.QueryRowContext(ctx, "SELECT id FROM transactions WHERE id = $1 FOR UPDATE", refund.TransactionID).Scan(&transactionID)
+	if err == sql.ErrNoRows {
+		return models.NewNotFoundError("transaction")
+	}
+	if err != nil {
+		return err
+	}
+
+	lines, err := getRefundableLines(ctx, tx, refund.TransactionID)
+	if err != nil {
+		return err
+	}
+
+	items, err := resolveRefundItems(lines, refund.Items)
+	if err != nil {
+		return err
+	}
+
+	refund.UserID = &userID
+	refund.ShiftID = &shiftID
+	refund.Items = items
+	refund.TotalAmount = 0
+	for _, item := range items {
+		refund.TotalAmount += item.Amount
+	}
+
+	query := `INSERT INTO refunds (transaction_id, user_id, shift_id, reason, note, method, restock, total_amount)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at`
+	err = tx.QueryRowContext(ctx, query, refund.TransactionID, userID, shiftID, refund.Reason, refund.Note, refund.Method,
+		refund.Restock, refund.TotalAmount).Scan(&refund.ID, &refund.CreatedAt)
+	if err != nil {
+		return mapDBError(ctx, err)
+	}
+
+	for i := range refund.Items {
+		item := &refund.Items[i]
+		item.RefundID = refund.ID
+		query := `INSERT INTO refund_items (refund_id, transaction_item_id, product_id, variant_id, quantity, amount, cost_amount)
+			VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+		err := tx.QueryRowContext(ctx, query, refund.ID, item.TransactionItemID, item.ProductID, item.VariantID, item.Quantity,
+			item.Amount, item.CostAmount).Scan(&item.ID)
+		if err != nil {
+			return mapDBError(ctx, err)
+		}
+
+		if refund.Restock {
+			if err := applyStockMovement(ctx, tx, restockMovement(refund, item)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"repository":     "refund",
+		"action":         "create_refund",
+		"refund_id":      refund.ID,
+		"transaction_id": refund.TransactionID,
+		"shift_id":       shiftID,
+		"items":          len(refund.Items),
+		"total_amount":   refund.TotalAmount,
+		"restock":        refund.Restock,
+	}).Info("Refund committed")
+
+	return nil
+}
+
+func getRefundableLines(ctx context.Context, tx *sql.Tx, transactionID int) ([]refundableLine, error) {
+	query := `SELECT ti.id, ti.product_id, ti.variant_id, p.name, p.weighable, ti.quantity, ti.total_amount, ti.cost_amount,
+		COALESCE(SUM(ri.quantity), 0), COALESCE(SUM(ri.amount), 0), COALESCE(SUM(ri.cost_amount), 0)
+		FROM transaction_items ti
+		JOIN products p ON p.id = ti.product_id
+		LEFT JOIN refund_items ri ON ri.transaction_item_id = ti.id
+		WHERE ti.transaction_id = $1
+		GROUP BY ti.id, p.name, p.weighable
+		ORDER BY ti.id`
+	rows, err := tx.QueryContext(ctx, query, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lines := make([]refundableLine, 0)
+	for rows.Next() {
+		var line refundableLine
+		var variantID sql.NullInt64
+		if err := rows.Scan(&line.id, &line.productID, &variantID, &line.productName, &line.weighable, &line.quantity, &line.totalAmount,
+			&line.costAmount, &line.refundedQty, &line.refundedAmount, &line.refundedCost); err != nil {
+			return nil, err
+		}
+		line.variantID = nullIntPtr(variantID)
+		lines = append(lines, line)
+	}
+	return lines, rows.Err()
+}
+
+// restockMovement membuat movement yang mengembalikan barang refund ke
+// persediaan dengan HPP saat dijual. Harga per satuan dibulatkan dan hanya
+// dipakai untuk harga pokok rata-rata; nilai persediaan dan layer FIFO
+// memakai HPP yang dikembalikan.
+func restockMovement(refund *models.Refund, item *models.RefundItem) *models.StockMovement {
+	unitCost := int((int64(item.CostAmount)*models.QuantityScale + int64(item.Quantity)/2) / int64(item.Quantity))
+	return &models.StockMovement{
+		ProductID:    item.ProductID,
+		VariantID:    item.VariantID,
+		Delta:        item.Quantity,
+		Reason:       models.StockReasonReturn,
+		ReferenceID:  fmt.Sprintf("RFD-%d", refund.ID),
+		Note:         fmt.Sprintf("refund of TRX-%d", refund.TransactionID),
+		UnitCost:     &unitCost,
+		InboundValue: &item.CostAmount,
+	}
+}
+
+// resolveRefundItems mencocokkan permintaan dengan baris transaksi dan
+// menghitung nilai refund setiap baris. Hasil terurut berdasarkan ID baris
+// transaksi supaya urutan lock produk sama dengan checkout.
+func resolveRefundItems(lines []refundableLine, requested []models.RefundItem) ([]models.RefundItem, error) {
+	lineIDs := make(map[int]bool, len(lines))
+	for _, line := range lines {
+		lineIDs[line.id] = true
+	}
+
+	quantities := make(map[int]models.Quantity)
+	for _, item := range requested {
+		if !lineIDs[item.TransactionItemID] {
+			return nil, models.NewValidationError("transaction_item_id", fmt.Sprintf("item %d does not belong to this transaction", item.TransactionItemID))
+		}
+		quantities[item.TransactionItemID] += item.Quantity
+	}
+	if len(requested) == 0 {
+		for _, line := range lines {
+			if remaining := line.quantity - line.refundedQty; remaining > 0 {
+				quantities[line.id] = remaining
+			}
+		}
+		if len(quantities) == 0 {
+			return nil, models.NewConflictError("transaction has been fully refunded")
+		}
+	}
+
+	items := make([]models.RefundItem, 0, len(quantities))
+	for _, line := range lines {
+		quantity, ok := quantities[line.id]
+		if !ok {
+			continue
+		}
+		if !line.weighable && !quantity.IsWhole() {
+			return nil, models.NewValidationError("quantity", fmt.Sprintf("%s is sold per whole unit, quantity %s is not allowed", line.productName, quantity))
+		}
+		if remaining := line.quantity - line.refundedQty; quantity > remaining {
+			return nil, models.NewConflictError(fmt.Sprintf("only %s of %s can still be refunded", remaining, line.productName))
+		}
+		items = append(items, models.RefundItem{
+			TransactionItemID: line.id,
+			ProductID:         line.productID,
+			VariantID:         line.variantID,
+			ProductName:       line.productName,
+			Quantity:          quantity,
+			Amount:            refundAmount(line, quantity),
+			CostAmount:        refundCost(line, quantity),
+		})
+	}
+
+	return items, nil
+}
+
+func (repo *refundRepository) GetRefundByID(ctx context.Context, id int) (*models.Refund, error) {
+	var refund models.Refund
+	var userID, shiftID sql.NullInt64
+	query := `SELECT id, transaction_id, user_id, shift_id, reason, note, method, restock, total_amount, created_at
+		FROM refunds WHERE id = $1`
+	err := repo.db.QueryRowContext(ctx, query, id).Scan(&refund.ID, &refund.TransactionID, &userID, &shiftID, &refund.Reason,
+		&refund.Note, &refund.Method, &refund.Restock, &refund.TotalAmount, &refund.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, models.NewNotFoundError("refund")
+	}
+	if err != nil {
+		return nil, err
+	}
+	refund.UserID = nullIntPtr(userID)
+	refund.ShiftID = nullIntPtr(shiftID)
+
+	query = `SELECT ri.id, ri.refund_id, ri.transaction_item_id, ri.product_id, ri.variant_id, COALESCE(p.name, ''), ri.quantity, ri.amount,
+		ri.cost_amount
+		FROM refund_items ri LEFT JOIN products p ON p.id = ri.product_id
+		WHERE ri.refund_id = $1 ORDER BY ri.id`
+	rows, err := repo.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	refund.Items = make([]models.RefundItem, 0)
+	for rows.Next() {
+		var item models.RefundItem
+		var variantID sql.NullInt64
+		if err := rows.Scan(&item.ID, &item.RefundID, &item.TransactionItemID, &item.ProductID, &variantID, &item.ProductName,
+			&item.Quantity, &item.Amount, &item.CostAmount); err != nil {
+			return nil, err
+		}
+		item.VariantID = nullIntPtr(variantID)
+		refund.Items = append(refund.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &refund, nil
+}
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"errors"
+	"kasir-api/internal/domain/models"
+	"testing"
+)
+
+func TestResolveRefundItems(t *testing.T) {
+	// 3 pcs dibayar 10.000 (HPP 7.000) dan 1.5 kg dibayar 45.000 (HPP 30.001)
+	lines := []refundableLine{
+		{id: 10, productID: 1, productName: "Sabun", quantity: models.WholeQuantity(3), totalAmount: 10000, costAmount: 7000},
+		{id: 11, productID: 2, productName: "Daging Sapi", weighable: true, quantity: 1500, totalAmount: 45000, costAmount: 30001},
+	}
+
+	items, err := resolveRefundItems(lines, []models.RefundItem{
+		{TransactionItemID: 11, Quantity: 500},
+		{TransactionItemID: 10, Quantity: models.WholeQuantity(1)},
+		{TransactionItemID: 10, Quantity: models.WholeQuantity(1)},
+	})
+	if err != nil {
+		t.Fatalf("resolveRefundItems() error = %v", err)
+	}
+	if len(items) != 2 || items[0].TransactionItemID != 10 || items[1].TransactionItemID != 11 {
+		t.Fatalf("items = %+v, want lines 10 and 11 in order", items)
+	}
+	// permintaan baris yang sama digabung, nilai proporsional dibulatkan ke bawah
+	if items[0].Quantity != models.WholeQuantity(2) || items[0].Amount != 6666 || items[0].CostAmount != 4666 {
+		t.Errorf("line 10 = qty %s amount %d cost %d, want 2, 6666, 4666", items[0].Quantity, items[0].Amount, items[0].CostAmount)
+	}
+	if items[1].Amount != 15000 || items[1].CostAmount != 10000 {
+		t.Errorf("line 11 = amount %d cost %d, want 15000, 10000", items[1].Amount, items[1].CostAmount)
+	}
+
+	// refund yang menghabiskan sisa unit mendapat sisa nilai baris, sehingga
+	// total semua refund sama persis dengan yang dibayar
+	lines[0].refundedQty, lines[0].refundedAmount, lines[0].refundedCost = items[0].Quantity, items[0].Amount, items[0].CostAmount
+	lines[1].refundedQty, lines[1].refundedAmount, lines[1].refundedCost = items[1].Quantity, items[1].Amount, items[1].CostAmount
+	rest, err := resolveRefundItems(lines, nil)
+	if err != nil {
+		t.Fatalf("resolveRefundItems() error = %v", err)
+	}
+	if len(rest) != 2 {
+		t.Fatalf("full refund = %d items, want 2", len(rest))
+	}
+	if rest[0].Quantity != models.WholeQuantity(1) || rest[0].Amount != 3334 || rest[0].CostAmount != 2334 {
+		t.Errorf("rest of line 10 = qty %s amount %d cost %d, want 1, 3334, 2334", rest[0].Quantity, rest[0].Amount, rest[0].CostAmount)
+	}
+	if rest[1].Quantity != 1000 || rest[1].Amount != 30000 || rest[1].CostAmount != 20001 {
+		t.Errorf("rest of line 11 = qty %s amount %d cost %d, want 1, 30000, 20001", rest[1].Quantity, rest[1].Amount, rest[1].CostAmount)
+	}
+
+	lines[0].refundedQty = lines[0].quantity
+	lines[1].refundedQty = lines[1].quantity
+	if _, err := resolveRefundItems(lines, nil); !errors.Is(err, models.ErrConflict) {
+		t.Errorf("refund of fully refunded transaction error = %v, want conflict", err)
+	}
+}
+
+func TestResolveRefundItemsRejects(t *testing.T) {
+	lines := []refundableLine{
+		{id: 10, productID: 1, productName: "Sabun", quantity: models.WholeQuantity(3), refundedQty: models.WholeQuantity(2),
+			totalAmount: 9000, refundedAmount: 6000},
+	}
+	tests := []struct {
+		name string
+		item models.RefundItem
+		want error
+	}{
+		{name: "line of another transaction", item: models.RefundItem{TransactionItemID: 99, Quantity: models.WholeQuantity(1)},
+			want: models.ErrValidation},
+		{name: "more than remaining", item: models.RefundItem{TransactionItemID: 10, Quantity: models.WholeQuantity(2)},
+			want: models.ErrConflict},
+		{name: "fraction of whole unit product", item: models.RefundItem{TransactionItemID: 10, Quantity: 500},
+			want: models.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := resolveRefundItems(lines, []models.RefundItem{tt.item})
+			if !errors.Is(err, tt.want) {
+				t.Errorf("resolveRefundItems() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestockMovement(t *testing.T) {
+	refund := &models.Refund{ID: 5, TransactionID: 42}
+	item := &models.RefundItem{ProductID: 1, Quantity: models.WholeQuantity(3), CostAmount: 10000}
+
+	movement := restockMovement(refund, item)
+	if movement.Delta != models.WholeQuantity(3) || movement.Reason != models.StockReasonReturn || movement.ReferenceID != "RFD-5" {
+		t.Errorf("movement = delta %s reason %s reference %s, want 3, return, RFD-5", movement.Delta, movement.Reason, movement.ReferenceID)
+	}
+	// harga per satuan dibulatkan (3333.33 -> 3333) tapi nilai yang masuk ke
+	// persediaan tetap HPP yang dikembalikan, bukan 3 x 3333
+	if movement.UnitCost == nil || *movement.UnitCost != 3333 {
+		t.Errorf("unit cost = %v, want 3333", movement.UnitCost)
+	}
+	inboundCost(movement, models.WholeQuantity(7), 3000)
+	if movement.CostAmount != 10000 {
+		t.Errorf("inbound value = %d, want 10000", movement.CostAmount)
+	}
+}
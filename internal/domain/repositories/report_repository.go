@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"kasir-api/internal/domain/models"
+	"time"
+)
+
+type ReportRepository interface {
+	// GetSalesReport merekap transaksi dengan created_at di [from, to)
+	GetSalesReport(ctx context.Context, from, to time.Time) (*models.SalesReport, error)
+}
+
+type reportRepository struct {
+	db *sql.DB
+}
+
+func NewReportRepository(db *sql.DB) ReportRepository {
+	return &reportRepository{db: db}
+}
+
+// GetSalesReport menghitung penjualan bersih dan HPP per produk. Penjualan
+// bersih baris adalah total baris dikurangi porsi pajak dan service charge
+// yang disimpan saat checkout, lalu dikurangi bagian unit yang di-refund
+// secara proporsional.
+func (repo *reportRepository) GetSalesReport(ctx context.Context, from, to time.Time) (*models.SalesReport, error) {
+	report := &models.SalesReport{
+		From:     from,
+		To:       to,
+		Products: make([]models.ProductSales, 0),
+	}
+
+	query := "SELECT COUNT(*) FROM transactions WHERE created_at >= $1 AND created_at < $2"
+	if err := repo.db.QueryRowContext(ctx, query, from, to).Scan(&report.TransactionCount); err != nil {
+		return nil, err
+	}
+
+	query = `WITH lines AS (
+			SELECT ti.product_id,
+				ti.quantity - COALESCE(r.quantity, 0) AS quantity,
+				(ti.total_amount - ti.tax_amount - ti.service_charge_amount) * (ti.quantity - COALESCE(r.quantity, 0)) / ti.quantity AS net_sales,
+				ti.service_charge_amount * (ti.quantity - COALESCE(r.quantity, 0)) / ti.quantity AS service_charge,
+				ti.cost_amount - COALESCE(r.restocked_cost, 0) AS cogs
+			FROM transaction_items ti
+			JOIN transactions t ON t.id = ti.transaction_id
+			LEFT JOIN (
+				SELECT ri.transaction_item_id, SUM(ri.quantity) AS quantity,
+					SUM(ri.cost_amount) FILTER (WHERE rf.restock) AS restocked_cost
+				FROM refund_items ri JOIN refunds rf ON rf.id = ri.refund_id
+				GROUP BY ri.transaction_item_id
+			) r ON r.transaction_item_id = ti.id
+			WHERE t.created_at >= $1 AND t.created_at < $2
+		)
+		SELECT l.product_id, p.name, SUM(l.quantity), ROUND(SUM(l.net_sales))::BIGINT, ROUND(SUM(l.service_charge))::BIGINT, SUM(l.cogs)
+		FROM lines l JOIN products p ON p.id = l.product_id
+		GROUP BY l.product_id, p.name
+		ORDER BY 4 DESC, l.product_id`
+	rows, err := repo.db.QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line models.ProductSales
+		if err := rows.Scan(&line.ProductID, &line.ProductName, &line.Quantity, &line.NetSales, &line.ServiceCharge, &line.COGS); err != nil {
+			return nil, err
+		}
+		line.GrossProfit = line.NetSales - line.COGS
+		line.MarginBps = models.MarginBps(line.GrossProfit, line.NetSales)
+		report.NetSales += line.NetSales
+		report.ServiceCharge += line.ServiceCharge
+		report.COGS += line.COGS
+		report.Products = append(report.Products, line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	report.GrossProfit = report.NetSales - report.COGS
+	report.MarginBps = models.MarginBps(report.GrossProfit, report.NetSales)
+
+	return report, nil
+}
@@ -0,0 +1,266 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/pkg"
+
+	"github.com/sirupsen/logrus"
+)
+
+type ShiftRepository interface {
+	CreateShift(ctx context.Context, shift *models.Shift) error
+	GetShiftByID(ctx context.Context, id int) (*models.Shift, error)
+	GetOpenShiftByUserID(ctx context.Context, userID int) (*models.Shift, error)
+	AddCashEvent(ctx context.Context, event *models.ShiftCashEvent) error
+	GetShiftReport(ctx context.Context, id int) (*models.ShiftReport, error)
+	// CloseShift menghitung expected cash, menyimpan hasil hitung kasir dan
+	// menutup shift dalam satu database transaction
+	CloseShift(ctx context.Context, id, countedCash int, note string) (*models.ShiftReport, error)
+}
+
+type shiftRepository struct {
+	db *sql.DB
+}
+
+func NewShiftRepository(db *sql.DB) ShiftRepository {
+	return &shiftRepository{db: db}
+}
+
+// queryer adalah method yang dimiliki *sql.DB dan *sql.Tx, supaya query
+// rekap bisa dipakai di dalam maupun di luar database transaction
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+const shiftColumns = `id, user_id, status, opening_float, expected_cash, counted_cash, variance,
+	opening_note, closing_note, opened_at, closed_at`
+
+func scanShift(row interface{ Scan(...any) error }) (*models.Shift, error) {
+	var s models.Shift
+	var expected, counted, variance sql.NullInt64
+	var closedAt sql.NullTime
+	err := row.Scan(&s.ID, &s.UserID, &s.Status, &s.OpeningFloat, &expected, &counted, &variance,
+		&s.OpeningNote, &s.ClosingNote, &s.OpenedAt, &closedAt)
+	if err != nil {
+		return nil, err
+	}
+	s.ExpectedCash = nullIntPtr(expected)
+	s.CountedCash = nullIntPtr(counted)
+	s.Variance = nullIntPtr(variance)
+	if closedAt.Valid {
+		s.ClosedAt = &closedAt.Time
+	}
+	return &s, nil
+}
+
+func nullIntPtr(n sql.NullInt64) *int {
+	if !n.Valid {
+		return nil
+	}
+	v := int(n.Int64)
+	return &v
+}
+
+func (repo *shiftRepository) CreateShift(ctx context.Context, shift *models.Shift) error {
+	query := `INSERT INTO shifts (user_id, opening_float, opening_note) VALUES ($1, $2, $3)
+		RETURNING id, status, opened_at`
+	err := repo.db.QueryRowContext(ctx, query, shift.UserID, shift.OpeningFloat, shift.OpeningNote).
+		Scan(&shift.ID, &shift.Status, &shift.OpenedAt)
+	if isUniqueViolation(err) {
+		return models.NewConflictError("user already has an open shift")
+	}
+	return mapDBError(ctx, err)
+}
+
+func (repo *shiftRepository) GetShiftByID(ctx context.Context, id int) (*models.Shift, error) {
+	shift, err := scanShift(repo.db.QueryRowContext(ctx, "SELECT "+shiftColumns+" FROM shifts WHERE id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, models.NewNotFoundError("shift")
+	}
+	return shift, err
+}
+
+func (repo *shiftRepository) GetOpenShiftByUserID(ctx context.Context, userID int) (*models.Shift, error) {
+	query := "SELECT " + shiftColumns + " FROM shifts WHERE user_id = $1 AND status = 'open'"
+	shift, err := scanShift(repo.db.QueryRowContext(ctx, query, userID))
+	if err == sql.ErrNoRows {
+		return nil, models.NewNotFoundError("open shift")
+	}
+	return shift, err
+}
+
+func (repo *shiftRepository) AddCashEvent(ctx context.Context, event *models.ShiftCashEvent) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Kunci shift supaya event tidak masuk bersamaan dengan penutupan shift
+	if _, err := lockOpenShift(ctx, tx, event.ShiftID); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO shift_cash_events (shift_id, type, amount, note, user_id) VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+	err = tx.QueryRowContext(ctx, query, event.ShiftID, event.Type, event.Amount, event.Note, event.UserID).
+		Scan(&event.ID, &event.CreatedAt)
+	if err != nil {
+		return mapDBError(ctx, err)
+	}
+
+	return tx.Commit()
+}
+
+func (repo *shiftRepository) GetShiftReport(ctx context.Context, id int) (*models.ShiftReport, error) {
+	shift, err := repo.GetShiftByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return buildShiftReport(ctx, repo.db, shift)
+}
+
+func (repo *shiftRepository) CloseShift(ctx context.Context, id, countedCash int, note string) (*models.ShiftReport, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// FOR UPDATE menunggu checkout/refund yang sedang berjalan (yang memegang FOR SHARE)
+	// sehingga semua penjualan shift ini ikut terhitung
+	shift, err := lockOpenShift(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := buildShiftReport(ctx, tx, shift)
+	if err != nil {
+		return nil, err
+	}
+
+	variance := countedCash - report.ExpectedCash
+	query := `UPDATE shifts SET status = 'closed', expected_cash = $2, counted_cash = $3, variance = $4,
+		closing_note = $5, closed_at = NOW() WHERE id = $1 RETURNING ` + shiftColumns
+	closed, err := scanShift(tx.QueryRowContext(ctx, query, id, report.ExpectedCash, countedCash, variance, note))
+	if err != nil {
+		return nil, mapDBError(ctx, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	report.Shift = *closed
+	report.CountedCash = closed.CountedCash
+	report.Variance = closed.Variance
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"repository":    "shift",
+		"action":        "close_shift",
+		"shift_id":      id,
+		"expected_cash": report.ExpectedCash,
+		"counted_cash":  countedCash,
+		"variance":      variance,
+	}).Info("Shift closed")
+
+	return report, nil
+}
+
+// lockOpenShift mengunci baris shift dan memastikan statusnya masih open
+func lockOpenShift(ctx context.Context, tx *sql.Tx, id int) (*models.Shift, error) {
+	shift, err := scanShift(tx.QueryRowContext(ctx, "SELECT "+shiftColumns+" FROM shifts WHERE id = $1 FOR UPDATE", id))
+	if err == sql.ErrNoRows {
+		return nil, models.NewNotFoundError("shift")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if shift.Status != models.ShiftStatusOpen {
+		return nil, models.NewConflictError("shift is already closed")
+	}
+	return shift, nil
+}
+
+// buildShiftReport merekap penjualan, pembayaran per metode dan kas masuk/keluar
+// sebuah shift. Untuk shift yang sudah ditutup, expected cash dan variance
+// diambil dari nilai yang disimpan saat penutupan.
+func buildShiftReport(ctx context.Context, q queryer, shift *models.Shift) (*models.ShiftReport, error) {
+	report := &models.ShiftReport{
+		Shift:       *shift,
+		Payments:    make([]models.ShiftPaymentSummary, 0),
+		CashEvents:  make([]models.ShiftCashEvent, 0),
+		CountedCash: shift.CountedCash,
+		Variance:    shift.Variance,
+	}
+
+	err := q.QueryRowContext(ctx, "SELECT COUNT(*), COALESCE(SUM(total_amount), 0) FROM transactions WHERE shift_id = $1", shift.ID).
+		Scan(&report.TransactionCount, &report.SalesTotal)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT p.method, COUNT(*), COALESCE(SUM(p.amount), 0)
+		FROM payments p JOIN transactions t ON t.id = p.transaction_id
+		WHERE t.shift_id = $1 GROUP BY p.method ORDER BY p.method`
+	rows, err := q.QueryContext(ctx, query, shift.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var summary models.ShiftPaymentSummary
+		if err := rows.Scan(&summary.Method, &summary.Count, &summary.Amount); err != nil {
+			return nil, err
+		}
+		if summary.Method == models.PaymentMethodCash {
+			report.CashSales = summary.Amount
+		}
+		report.Payments = append(report.Payments, summary)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	query = `SELECT COUNT(*), COALESCE(SUM(total_amount), 0), COALESCE(SUM(total_amount) FILTER (WHERE method = 'cash'), 0)
+		FROM refunds WHERE shift_id = $1`
+	err = q.QueryRowContext(ctx, query, shift.ID).Scan(&report.RefundCount, &report.RefundTotal, &report.CashRefunds)
+	if err != nil {
+		return nil, err
+	}
+
+	query = `SELECT id, shift_id, type, amount, note, user_id, created_at
+		FROM shift_cash_events WHERE shift_id = $1 ORDER BY created_at, id`
+	eventRows, err := q.QueryContext(ctx, query, shift.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer eventRows.Close()
+	for eventRows.Next() {
+		var event models.ShiftCashEvent
+		var userID sql.NullInt64
+		if err := eventRows.Scan(&event.ID, &event.ShiftID, &event.Type, &event.Amount, &event.Note, &userID, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		event.UserID = nullIntPtr(userID)
+		if event.Type == models.CashEventIn {
+			report.CashIn += event.Amount
+		} else {
+			report.CashOut += event.Amount
+		}
+		report.CashEvents = append(report.CashEvents, event)
+	}
+	if err := eventRows.Err(); err != nil {
+		return nil, err
+	}
+
+	report.ExpectedCash = shift.OpeningFloat + report.CashSales - report.CashRefunds + report.CashIn - report.CashOut
+	if shift.ExpectedCash != nil {
+		report.ExpectedCash = *shift.ExpectedCash
+	}
+
+	return report, nil
+}
@@ -0,0 +1,378 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"kasir-api/internal/domain/models"
+)
+
+type StockOpnameRepository interface {
+	// CreateStockOpname membuka sesi dan men-snapshot stok serta harga pokok
+	// semua produk (atau satu kategori). Selama sesi open stok produk yang
+	// di-snapshot dibekukan, lihat checkStockOpnameFreeze.
+	CreateStockOpname(ctx context.Context, opname *models.StockOpname) error
+	GetStockOpnameByID(ctx context.Context, id int) (*models.StockOpname, error)
+	// GetStockOpnames mengembalikan sesi terbaru dulu beserta total untuk pagination
+	GetStockOpnames(ctx context.Context, status string, page, perPage int) ([]models.StockOpname, int, error)
+	// GetStockOpnameItems mengembalikan snapshot dan hasil hitung semua item sesi
+	GetStockOpnameItems(ctx context.Context, id int) ([]models.StockOpnameItem, error)
+	// SaveCounts menimpa (atau menambah jika add) hasil hitung userID untuk
+	// setiap item; Quantity di counts diisi dengan hasil hitung terbaru
+	SaveCounts(ctx context.Context, id, userID int, counts []models.StockOpnameCount, add bool) error
+	// ApproveStockOpname memposting adjustment untuk setiap selisih dan
+	// menutup sesi dalam satu database transaction. Mengembalikan jumlah
+	// adjustment yang diposting.
+	ApproveStockOpname(ctx context.Context, id, approvedBy int) (int, error)
+	CancelStockOpname(ctx context.Context, id int) error
+}
+
+type stockOpnameRepository struct {
+	db *sql.DB
+}
+
+func NewStockOpnameRepository(db *sql.DB) StockOpnameRepository {
+	return &stockOpnameRepository{db: db}
+}
+
+const stockOpnameColumns = `o.id, o.status, o.note, o.category_id, o.created_by, o.approved_by, o.approved_at, o.created_at, o.updated_at,
+	(SELECT COUNT(*) FROM stock_opname_items i WHERE i.stock_opname_id = o.id),
+	(SELECT COUNT(*) FROM stock_opname_items i WHERE i.stock_opname_id = o.id AND (i.counted_quantity IS NOT NULL
+		OR EXISTS (SELECT 1 FROM stock_opname_counts c WHERE c.stock_opname_item_id = i.id)))`
+
+func (repo *stockOpnameRepository) CreateStockOpname(ctx context.Context, opname *models.StockOpname) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO stock_opnames (status, note, category_id, created_by) VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at`
+	err = tx.QueryRowContext(ctx, query, opname.Status, opname.Note, opname.CategoryID, opname.CreatedBy).
+		Scan(&opname.ID, &opname.CreatedAt, &opname.UpdatedAt)
+	if isUniqueViolation(err) {
+		return models.NewConflictError("another stock opname is still open")
+	}
+	if err != nil {
+		return mapDBError(ctx, err)
+	}
+
+	// Baris produk dikunci urut id seperti checkout supaya movement yang
+	// sedang berjalan selesai dulu dan masuk ke snapshot; movement berikutnya
+	// menunggu sampai sesi tersimpan lalu ditolak oleh checkStockOpnameFreeze
+	query = "SELECT 1 FROM products WHERE $1::INTEGER IS NULL OR category_id = $1 ORDER BY id FOR SHARE"
+	if _, err := tx.ExecContext(ctx, query, opname.CategoryID); err != nil {
+		return err
+	}
+
+	// Produk bervarian dihitung per varian; varian nonaktif hanya ikut jika
+	// masih ada stoknya
+	query = `INSERT INTO stock_opname_items (stock_opname_id, product_id, variant_id, expected_quantity, unit_cost)
+		SELECT $1, p.id, v.id, COALESCE(v.stock, p.stock), p.cost_price
+		FROM products p
+		LEFT JOIN product_variants v ON v.product_id = p.id AND (v.active OR v.stock <> 0)
+		WHERE ($2::INTEGER IS NULL OR p.category_id = $2)
+			AND (v.id IS NOT NULL OR NOT EXISTS (SELECT 1 FROM product_variants pv WHERE pv.product_id = p.id))`
+	result, err := tx.ExecContext(ctx, query, opname.ID, opname.CategoryID)
+	if err != nil {
+		return mapDBError(ctx, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	opname.ItemCount = int(affected)
+
+	return tx.Commit()
+}
+
+func scanStockOpname(row interface{ Scan(...any) error }, o *models.StockOpname) error {
+	var categoryID, createdBy, approvedBy sql.NullInt64
+	var approvedAt sql.NullTime
+	err := row.Scan(&o.ID, &o.Status, &o.Note, &categoryID, &createdBy, &approvedBy, &approvedAt, &o.CreatedAt, &o.UpdatedAt,
+		&o.ItemCount, &o.CountedItems)
+	if err != nil {
+		return err
+	}
+	o.CategoryID = nullIntPtr(categoryID)
+	o.CreatedBy = nullIntPtr(createdBy)
+	o.ApprovedBy = nullIntPtr(approvedBy)
+	if approvedAt.Valid {
+		o.ApprovedAt = &approvedAt.Time
+	}
+	return nil
+}
+
+func (repo *stockOpnameRepository) GetStockOpnameByID(ctx context.Context, id int) (*models.StockOpname, error) {
+	var o models.StockOpname
+	err := scanStockOpname(repo.db.QueryRowContext(ctx, "SELECT "+stockOpnameColumns+" FROM stock_opnames o WHERE o.id = $1", id), &o)
+	if err == sql.ErrNoRows {
+		return nil, models.NewNotFoundError("stock opname")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (repo *stockOpnameRepository) GetStockOpnames(ctx context.Context, status string, page, perPage int) ([]models.StockOpname, int, error) {
+	var total int
+	query := "SELECT COUNT(*) FROM stock_opnames WHERE $1 = '' OR status = $1"
+	if err := repo.db.QueryRowContext(ctx, query, status).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query = "SELECT " + stockOpnameColumns + ` FROM stock_opnames o WHERE $1 = '' OR o.status = $1
+		ORDER BY o.created_at DESC, o.id DESC LIMIT $2 OFFSET $3`
+	rows, err := repo.db.QueryContext(ctx, query, status, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	opnames := make([]models.StockOpname, 0)
+	for rows.Next() {
+		var o models.StockOpname
+		if err := scanStockOpname(rows, &o); err != nil {
+			return nil, 0, err
+		}
+		opnames = append(opnames, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return opnames, total, nil
+}
+
+func (repo *stockOpnameRepository) GetStockOpnameItems(ctx context.Context, id int) ([]models.StockOpnameItem, error) {
+	// Setelah approve hasil hitung dibaca dari counted_quantity yang dibekukan
+	query := `SELECT i.product_id, p.name, i.variant_id, v.option_values, p.base_unit, i.expected_quantity,
+		i.counted_quantity IS NOT NULL OR c.total IS NOT NULL, COALESCE(i.counted_quantity, c.total), COALESCE(c.counters, 0),
+		i.unit_cost, i.stock_movement_id
+		FROM stock_opname_items i
+		JOIN products p ON p.id = i.product_id
+		LEFT JOIN product_variants v ON v.id = i.variant_id
+		LEFT JOIN (
+			SELECT stock_opname_item_id, SUM(quantity) AS total, COUNT(*) AS counters
+			FROM stock_opname_counts GROUP BY stock_opname_item_id
+		) c ON c.stock_opname_item_id = i.id
+		WHERE i.stock_opname_id = $1
+		ORDER BY p.name, i.product_id, COALESCE(i.variant_id, 0)`
+	rows, err := repo.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.StockOpnameItem, 0)
+	for rows.Next() {
+		var item models.StockOpnameItem
+		var variantID, movementID sql.NullInt64
+		var optionValues []string
+		var counted bool
+		var countedQuantity models.Quantity
+		if err := rows.Scan(&item.ProductID, &item.ProductName, &variantID, textArray(&optionValues), &item.Unit, &item.Expected,
+			&counted, &countedQuantity, &item.Counters, &item.UnitCost, &movementID); err != nil {
+			return nil, err
+		}
+		item.VariantID = nullIntPtr(variantID)
+		if item.VariantID != nil {
+			item.VariantName = models.VariantName(optionValues)
+		}
+		item.StockMovementID = nullIntPtr(movementID)
+		if counted {
+			item.Counted = &countedQuantity
+			item.Variance = countedQuantity - item.Expected
+			item.VarianceValue = item.Variance.Amount(item.UnitCost)
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// lockOpenStockOpname memastikan sesi masih open. Pencatatan hasil hitung
+// memakai FOR SHARE supaya banyak penghitung bisa berjalan bersamaan tetapi
+// tetap menunggu approve/cancel yang memakai FOR UPDATE.
+func lockOpenStockOpname(ctx context.Context, tx *sql.Tx, id int, lock string) error {
+	var status string
+	err := tx.QueryRowContext(ctx, "SELECT status FROM stock_opnames WHERE id = $1 "+lock, id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return models.NewNotFoundError("stock opname")
+	}
+	if err != nil {
+		return err
+	}
+	if status != models.StockOpnameStatusOpen {
+		return models.NewConflictError(fmt.Sprintf("stock opname is %s, only open stock opnames can be changed", status))
+	}
+	return nil
+}
+
+func (repo *stockOpnameRepository) SaveCounts(ctx context.Context, id, userID int, counts []models.StockOpnameCount, add bool) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenStockOpname(ctx, tx, id, "FOR SHARE"); err != nil {
+		return err
+	}
+
+	update := "quantity = EXCLUDED.quantity"
+	if add {
+		update = "quantity = stock_opname_counts.quantity + EXCLUDED.quantity"
+	}
+	upsert := `INSERT INTO stock_opname_counts (stock_opname_item_id, user_id, quantity) VALUES ($1, $2, $3)
+		ON CONFLICT (stock_opname_item_id, user_id) DO UPDATE SET ` + update + `, updated_at = NOW()
+		RETURNING quantity`
+
+	for i := range counts {
+		count := &counts[i]
+		var itemID int
+		query := `SELECT id FROM stock_opname_items
+			WHERE stock_opname_id = $1 AND product_id = $2 AND variant_id IS NOT DISTINCT FROM $3`
+		err := tx.QueryRowContext(ctx, query, id, count.ProductID, count.VariantID).Scan(&itemID)
+		if err == sql.ErrNoRows {
+			return models.NewNotFoundError(fmt.Sprintf("product %d in stock opname %d", count.ProductID, id))
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := tx.QueryRowContext(ctx, upsert, itemID, userID, count.Quantity).Scan(&count.Quantity); err != nil {
+			return mapDBError(ctx, err)
+		}
+		count.UserID = userID
+	}
+
+	return tx.Commit()
+}
+
+// opnameDiscrepancy adalah item yang hasil hitungnya akan dibekukan saat approve
+type opnameDiscrepancy struct {
+	itemID    int
+	productID int
+	variantID *int
+	expected  models.Quantity
+	counted   models.Quantity
+}
+
+func (repo *stockOpnameRepository) ApproveStockOpname(ctx context.Context, id, approvedBy int) (int, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenStockOpname(ctx, tx, id, "FOR UPDATE"); err != nil {
+		return 0, err
+	}
+
+	// Item yang belum dihitung tidak disesuaikan. Urut produk supaya urutan
+	// lock baris produk sama dengan checkout.
+	query := `SELECT i.id, i.product_id, i.variant_id, i.expected_quantity, c.total
+		FROM stock_opname_items i
+		JOIN (
+			SELECT stock_opname_item_id, SUM(quantity) AS total
+			FROM stock_opname_counts GROUP BY stock_opname_item_id
+		) c ON c.stock_opname_item_id = i.id
+		WHERE i.stock_opname_id = $1
+		ORDER BY i.product_id, COALESCE(i.variant_id, 0)`
+	rows, err := tx.QueryContext(ctx, query, id)
+	if err != nil {
+		return 0, err
+	}
+	items := make([]opnameDiscrepancy, 0)
+	for rows.Next() {
+		var item opnameDiscrepancy
+		var variantID sql.NullInt64
+		if err := rows.Scan(&item.itemID, &item.productID, &variantID, &item.expected, &item.counted); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		item.variantID = nullIntPtr(variantID)
+		items = append(items, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	// Sesi ditutup dulu supaya stok tidak lagi dibekukan untuk adjustment di
+	// bawah. Stok tidak berubah selama sesi open, jadi selisih terhadap
+	// snapshot sama dengan selisih terhadap stok saat ini.
+	query = `UPDATE stock_opnames SET status = 'approved', approved_by = $2, approved_at = NOW(), updated_at = NOW()
+		WHERE id = $1`
+	if _, err := tx.ExecContext(ctx, query, id, approvedBy); err != nil {
+		return 0, mapDBError(ctx, err)
+	}
+
+	adjustments := 0
+	for _, item := range items {
+		var movementID *int
+		if delta := item.counted - item.expected; delta != 0 {
+			movement := &models.StockMovement{
+				ProductID:   item.productID,
+				VariantID:   item.variantID,
+				Delta:       delta,
+				Reason:      models.StockReasonAdjustment,
+				ReferenceID: fmt.Sprintf("OPN-%d", id),
+				Note:        fmt.Sprintf("stock opname #%d (expected %s, counted %s)", id, item.expected, item.counted),
+				UserID:      &approvedBy,
+			}
+			if err := applyStockMovement(ctx, tx, movement); err != nil {
+				return 0, err
+			}
+			movementID = &movement.ID
+			adjustments++
+		}
+
+		query := "UPDATE stock_opname_items SET counted_quantity = $2, stock_movement_id = $3 WHERE id = $1"
+		if _, err := tx.ExecContext(ctx, query, item.itemID, item.counted, movementID); err != nil {
+			return 0, mapDBError(ctx, err)
+		}
+	}
+
+	return adjustments, tx.Commit()
+}
+
+func (repo *stockOpnameRepository) CancelStockOpname(ctx context.Context, id int) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenStockOpname(ctx, tx, id, "FOR UPDATE"); err != nil {
+		return err
+	}
+
+	query := "UPDATE stock_opnames SET status = 'cancelled', updated_at = NOW() WHERE id = $1"
+	if _, err := tx.ExecContext(ctx, query, id); err != nil {
+		return mapDBError(ctx, err)
+	}
+
+	return tx.Commit()
+}
+
+// checkStockOpnameFreeze menolak perubahan stok produk yang sedang dihitung
+// di sesi stock opname yang masih open, supaya stok tetap sama dengan
+// snapshot sampai sesi di-approve atau dibatalkan. Baris produk harus sudah
+// dikunci pemanggil.
+func checkStockOpnameFreeze(ctx context.Context, tx *sql.Tx, productID int, name string) error {
+	var opnameID int
+	query := `SELECT o.id FROM stock_opnames o
+		JOIN stock_opname_items i ON i.stock_opname_id = o.id
+		WHERE o.status = 'open' AND i.product_id = $1
+		LIMIT 1`
+	err := tx.QueryRowContext(ctx, query, productID).Scan(&opnameID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return models.NewConflictError(fmt.Sprintf("stock of %s is frozen while stock opname #%d is open", name, opnameID))
+}
@@ -0,0 +1,329 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/pkg"
+
+	"github.com/sirupsen/logrus"
+)
+
+type StockRepository interface {
+	ApplyMovement(ctx context.Context, movement *models.StockMovement) error
+	GetMovementsByProductID(ctx context.Context, productID, limit, offset int) ([]models.StockMovement, int, error)
+}
+
+type stockRepository struct {
+	db *sql.DB
+}
+
+func NewStockRepository(db *sql.DB) StockRepository {
+	return &stockRepository{db: db}
+}
+
+func (repo *stockRepository) ApplyMovement(ctx context.Context, movement *models.StockMovement) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := applyStockMovement(ctx, tx, movement); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (repo *stockRepository) GetMovementsByProductID(ctx context.Context, productID, limit, offset int) ([]models.StockMovement, int, error) {
+	var total int
+	err := repo.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM stock_movements WHERE product_id = $1", productID).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT id, product_id, product_name, variant_id, variant_name, delta, stock_after, reason, COALESCE(reference_id, ''), note,
+		unit_cost, cost_amount, user_id, created_at
+		FROM stock_movements WHERE product_id = $1
+		ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3`
+	rows, err := repo.db.QueryContext(ctx, query, productID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	movements := make([]models.StockMovement, 0)
+	for rows.Next() {
+		var m models.StockMovement
+		var variantID, unitCost, userID sql.NullInt64
+		if err := rows.Scan(&m.ID, &m.ProductID, &m.ProductName, &variantID, &m.VariantName, &m.Delta, &m.StockAfter, &m.Reason, &m.ReferenceID, &m.Note, &unitCost, &m.CostAmount,
+			&userID, &m.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		m.VariantID = nullIntPtr(variantID)
+		m.UnitCost = nullIntPtr(unitCost)
+		if userID.Valid {
+			id := int(userID.Int64)
+			m.UserID = &id
+		}
+		movements = append(movements, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return movements, total, nil
+}
+
+// applyStockMovement adalah satu-satunya jalur untuk mengubah stok produk
+// dan varian. Baris produk dikunci, stok baru dihitung dan divalidasi, lalu
+// movement dicatat di ledger. Untuk produk bervarian movement wajib menyebut
+// VariantID: stok varian diubah dan products.stock ikut berubah sebagai total
+// semua varian. Nilai persediaan ikut dicatat: barang masuk memperbarui harga
+// pokok rata-rata dan membuka layer FIFO, barang keluar mendapat HPP sesuai
+// costing method produk di CostAmount. Produk yang sedang di-stock opname
+// ditolak (409). Harus dipanggil di dalam database transaction.
+func applyStockMovement(ctx context.Context, tx *sql.Tx, movement *models.StockMovement) error {
+	var name, costingMethod string
+	var productStock models.Quantity
+	var weighable bool
+	var costPrice int
+	query := "SELECT name, stock, weighable, cost_price, costing_method FROM products WHERE id = $1 FOR UPDATE"
+	err := tx.QueryRowContext(ctx, query, movement.ProductID).Scan(&name, &productStock, &weighable, &costPrice, &costingMethod)
+	if err == sql.ErrNoRows {
+		return models.NewNotFoundError(fmt.Sprintf("product %d", movement.ProductID))
+	}
+	if err != nil {
+		return err
+	}
+	movement.ProductName = name
+	if err := checkStockOpnameFreeze(ctx, tx, movement.ProductID, name); err != nil {
+		return err
+	}
+	if !weighable && !movement.Delta.IsWhole() {
+		return models.NewValidationError("quantity", fmt.Sprintf("%s is sold per whole unit, quantity %s is not allowed", name, movement.Delta))
+	}
+
+	hasVariants, err := productHasVariants(ctx, tx, movement.ProductID)
+	if err != nil {
+		return err
+	}
+
+	stock := productStock
+	switch {
+	case movement.VariantID != nil:
+		var optionValues []string
+		query := "SELECT option_values, stock FROM product_variants WHERE id = $1 AND product_id = $2 FOR UPDATE"
+		err := tx.QueryRowContext(ctx, query, *movement.VariantID, movement.ProductID).Scan(textArray(&optionValues), &stock)
+		if err == sql.ErrNoRows {
+			return models.NewNotFoundError(fmt.Sprintf("variant %d of product %d", *movement.VariantID, movement.ProductID))
+		}
+		if err != nil {
+			return err
+		}
+		movement.VariantName = models.VariantName(optionValues)
+		name += " " + movement.VariantName
+	case hasVariants:
+		return models.NewValidationError("variant_id", fmt.Sprintf("%s has variants, variant_id is required", name))
+	}
+
+	newStock := stock + movement.Delta
+	if newStock < 0 {
+		return models.NewConflictError(fmt.Sprintf("insufficient stock for %s (available %s, requested %s)", name, stock, -movement.Delta))
+	}
+
+	if movement.VariantID != nil {
+		if _, err := tx.ExecContext(ctx, "UPDATE product_variants SET stock = $1 WHERE id = $2", newStock, *movement.VariantID); err != nil {
+			return err
+		}
+	}
+
+	// Harga pokok rata-rata hanya berubah saat barang masuk; barang keluar
+	// menghabiskan layer FIFO paling lama apapun metodenya supaya layer tetap
+	// sesuai stok jika costing method produk diganti
+	newCostPrice := costPrice
+	var fifoCost int
+	if movement.Delta > 0 {
+		newCostPrice = inboundCost(movement, productStock, costPrice)
+	} else {
+		movement.UnitCost = nil
+		fifoCost, err = consumeCostLayers(ctx, tx, movement.ProductID, productStock, costPrice, -movement.Delta)
+		if err != nil {
+			return err
+		}
+		movement.CostAmount = -(-movement.Delta).Amount(costPrice)
+		if costingMethod == models.CostingFIFO {
+			movement.CostAmount = -fifoCost
+		}
+	}
+
+	query = "UPDATE products SET stock = $1, cost_price = $2 WHERE id = $3"
+	if _, err := tx.ExecContext(ctx, query, productStock+movement.Delta, newCostPrice, movement.ProductID); err != nil {
+		return err
+	}
+
+	// Catat user yang sedang login jika pemanggil tidak mengisi secara eksplisit
+	if movement.UserID == nil {
+		if user, ok := pkg.AuthUserFromContext(ctx); ok {
+			movement.UserID = &user.ID
+		}
+	}
+
+	var referenceID sql.NullString
+	if movement.ReferenceID != "" {
+		referenceID = sql.NullString{String: movement.ReferenceID, Valid: true}
+	}
+
+	movement.StockAfter = newStock
+	query = `INSERT INTO stock_movements (product_id, product_name, variant_id, variant_name, delta, stock_after, reason, reference_id, note,
+		unit_cost, cost_amount, user_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, created_at`
+	err = tx.QueryRowContext(ctx, query, movement.ProductID, movement.ProductName, movement.VariantID, movement.VariantName, movement.Delta,
+		movement.StockAfter, movement.Reason, referenceID, movement.Note, movement.UnitCost, movement.CostAmount, movement.UserID).
+		Scan(&movement.ID, &movement.CreatedAt)
+	if err != nil {
+		return mapDBError(ctx, err)
+	}
+
+	if movement.Delta > 0 {
+		query := "INSERT INTO cost_layers (product_id, movement_id, unit_cost, remaining, remaining_value) VALUES ($1, $2, $3, $4, $5)"
+		if _, err := tx.ExecContext(ctx, query, movement.ProductID, movement.ID, *movement.UnitCost, movement.Delta, movement.CostAmount); err != nil {
+			return mapDBError(ctx, err)
+		}
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"repository":   "stock",
+		"action":       "apply_stock_movement",
+		"product_id":   movement.ProductID,
+		"variant_id":   movement.VariantID,
+		"delta":        movement.Delta,
+		"stock_after":  movement.StockAfter,
+		"reason":       movement.Reason,
+		"reference_id": movement.ReferenceID,
+		"cost_amount":  movement.CostAmount,
+	}).Info("Stock movement recorded")
+
+	return nil
+}
+
+// productHasVariants mengecek apakah stok dan harga produk dikelola per varian
+func productHasVariants(ctx context.Context, tx *sql.Tx, productID int) (bool, error) {
+	var exists bool
+	err := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM product_variants WHERE product_id = $1)", productID).Scan(&exists)
+	return exists, err
+}
+
+// movingAverageCost menghitung harga pokok rata-rata setelah quantity barang
+// masuk dengan unitCost, dibulatkan ke rupiah terdekat. Stok kosong berarti
+// harga pokok baru sama dengan unitCost.
+func movingAverageCost(stock models.Quantity, costPrice int, quantity models.Quantity, unitCost int) int {
+	if stock <= 0 {
+		return unitCost
+	}
+	value := int64(stock)*int64(costPrice) + int64(quantity)*int64(unitCost)
+	total := int64(stock + quantity)
+	return int((value + total/2) / total)
+}
+
+// inboundCost mengisi UnitCost dan CostAmount barang masuk lalu
+// mengembalikan harga pokok rata-rata baru. UnitCost kosong memakai harga
+// pokok produk, dan CostAmount memakai InboundValue jika pemanggil mengisinya.
+func inboundCost(movement *models.StockMovement, stock models.Quantity, costPrice int) int {
+	if movement.UnitCost == nil {
+		movement.UnitCost = &costPrice
+	}
+	movement.CostAmount = movement.Delta.Amount(*movement.UnitCost)
+	if movement.InboundValue != nil {
+		movement.CostAmount = *movement.InboundValue
+	}
+	return movingAverageCost(stock, costPrice, movement.Delta, *movement.UnitCost)
+}
+
+// costLayer adalah satu layer FIFO yang masih bersisa
+type costLayer struct {
+	id, unitCost, remainingValue int
+	remaining                    models.Quantity
+}
+
+// consumeCostLayers mengurangi quantity dari layer FIFO paling lama dan
+// mengembalikan nilai pokoknya. Stok yang lebih tua dari semua layer (stok
+// sebelum pencatatan harga pokok) dianggap keluar lebih dulu dengan harga
+// pokok produk, begitu juga kekurangan jika layer habis.
+func consumeCostLayers(ctx context.Context, tx *sql.Tx, productID int, stock models.Quantity, costPrice int, quantity models.Quantity) (int, error) {
+	var layered models.Quantity
+	err := tx.QueryRowContext(ctx, "SELECT COALESCE(SUM(remaining), 0) FROM cost_layers WHERE product_id = $1 AND remaining > 0", productID).
+		Scan(&layered)
+	if err != nil {
+		return 0, err
+	}
+
+	cost := 0
+	if untracked := min(stock-layered, quantity); untracked > 0 {
+		cost += untracked.Amount(costPrice)
+		quantity -= untracked
+	}
+	if quantity <= 0 {
+		return cost, nil
+	}
+
+	query := `SELECT id, unit_cost, remaining, remaining_value FROM cost_layers
+		WHERE product_id = $1 AND remaining > 0 ORDER BY id FOR UPDATE`
+	rows, err := tx.QueryContext(ctx, query, productID)
+	if err != nil {
+		return 0, err
+	}
+	var layers []costLayer
+	for rows.Next() {
+		var l costLayer
+		if err := rows.Scan(&l.id, &l.unitCost, &l.remaining, &l.remainingValue); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		layers = append(layers, l)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	layerCost, changed, rest := takeFromLayers(layers, quantity)
+	for _, l := range changed {
+		query := "UPDATE cost_layers SET remaining = $1, remaining_value = $2 WHERE id = $3"
+		if _, err := tx.ExecContext(ctx, query, l.remaining, l.remainingValue, l.id); err != nil {
+			return 0, err
+		}
+	}
+	cost += layerCost
+	if rest > 0 {
+		cost += rest.Amount(costPrice)
+	}
+	return cost, nil
+}
+
+// takeFromLayers mengambil quantity dari layers berurutan dan mengembalikan
+// nilai pokoknya, layer yang berubah dengan sisa barunya, dan quantity yang
+// tidak tertutup layer. Layer yang habis mengeluarkan seluruh sisa nilainya
+// sehingga total HPP sebuah layer sama dengan nilai barang masuk.
+func takeFromLayers(layers []costLayer, quantity models.Quantity) (int, []costLayer, models.Quantity) {
+	cost := 0
+	var changed []costLayer
+	for _, l := range layers {
+		if quantity <= 0 {
+			break
+		}
+		taken := min(l.remaining, quantity)
+		value := l.remainingValue
+		if taken < l.remaining {
+			value = min(taken.Amount(l.unitCost), l.remainingValue)
+		}
+		l.remaining -= taken
+		l.remainingValue -= value
+		changed = append(changed, l)
+		cost += value
+		quantity -= taken
+	}
+	return cost, changed, quantity
+}
@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"kasir-api/internal/domain/models"
+	"reflect"
+	"testing"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestInboundCost(t *testing.T) {
+	tests := []struct {
+		name         string
+		delta        models.Quantity
+		unitCost     *int
+		inboundValue *int
+		wantUnitCost int
+		wantAmount   int
+		wantAverage  int
+	}{
+		{name: "defaults to product cost price", delta: 2000, wantUnitCost: 1000, wantAmount: 2000, wantAverage: 1000},
+		{name: "delta times unit cost", delta: 2000, unitCost: intPtr(1600), wantUnitCost: 1600, wantAmount: 3200, wantAverage: 1200},
+		{name: "exact value beats rounded unit cost", delta: 12000, unitCost: intPtr(833), inboundValue: intPtr(10000),
+			wantUnitCost: 833, wantAmount: 10000, wantAverage: 875},
+		{name: "zero value is a free receipt", delta: 2000, unitCost: intPtr(0), inboundValue: intPtr(0),
+			wantUnitCost: 0, wantAmount: 0, wantAverage: 667},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			movement := &models.StockMovement{Delta: tt.delta, UnitCost: tt.unitCost, InboundValue: tt.inboundValue}
+			average := inboundCost(movement, 4000, 1000)
+			if *movement.UnitCost != tt.wantUnitCost || movement.CostAmount != tt.wantAmount || average != tt.wantAverage {
+				t.Errorf("unit cost, cost amount, average = %d, %d, %d, want %d, %d, %d",
+					*movement.UnitCost, movement.CostAmount, average, tt.wantUnitCost, tt.wantAmount, tt.wantAverage)
+			}
+		})
+	}
+}
+
+func TestMovingAverageCost(t *testing.T) {
+	tests := []struct {
+		name      string
+		stock     models.Quantity
+		costPrice int
+		quantity  models.Quantity
+		unitCost  int
+		want      int
+	}{
+		{name: "empty stock takes the new cost", stock: 0, costPrice: 5000, quantity: 1000, unitCost: 7000, want: 7000},
+		{name: "negative stock takes the new cost", stock: -1000, costPrice: 5000, quantity: 3000, unitCost: 7000, want: 7000},
+		{name: "weighted by quantity", stock: 3000, costPrice: 1000, quantity: 1000, unitCost: 2000, want: 1250},
+		{name: "rounds half up", stock: 1000, costPrice: 100, quantity: 1000, unitCost: 101, want: 101},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := movingAverageCost(tt.stock, tt.costPrice, tt.quantity, tt.unitCost); got != tt.want {
+				t.Errorf("movingAverageCost = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTakeFromLayers(t *testing.T) {
+	// 12 pcs dibeli seharga 10.000 (833,33 per pcs), lalu 5 pcs seharga 1.000
+	layers := []costLayer{
+		{id: 1, unitCost: 833, remaining: 12000, remainingValue: 10000},
+		{id: 2, unitCost: 1000, remaining: 5000, remainingValue: 5000},
+	}
+
+	tests := []struct {
+		name     string
+		quantity models.Quantity
+		cost     int
+		changed  []costLayer
+		rest     models.Quantity
+	}{
+		{
+			name:     "partial layer uses the unit cost",
+			quantity: 5000,
+			cost:     4165,
+			changed:  []costLayer{{id: 1, unitCost: 833, remaining: 7000, remainingValue: 5835}},
+		},
+		{
+			name:     "emptied layer releases its full value",
+			quantity: 12000,
+			cost:     10000,
+			changed:  []costLayer{{id: 1, unitCost: 833, remaining: 0, remainingValue: 0}},
+		},
+		{
+			name:     "spans layers oldest first",
+			quantity: 14000,
+			cost:     12000,
+			changed: []costLayer{
+				{id: 1, unitCost: 833, remaining: 0, remainingValue: 0},
+				{id: 2, unitCost: 1000, remaining: 3000, remainingValue: 3000},
+			},
+		},
+		{
+			name:     "shortfall is returned to the caller",
+			quantity: 20000,
+			cost:     15000,
+			changed: []costLayer{
+				{id: 1, unitCost: 833, remaining: 0, remainingValue: 0},
+				{id: 2, unitCost: 1000, remaining: 0, remainingValue: 0},
+			},
+			rest: 3000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cost, changed, rest := takeFromLayers(layers, tt.quantity)
+			if cost != tt.cost || rest != tt.rest {
+				t.Errorf("cost, rest = %d, %s, want %d, %s", cost, rest, tt.cost, tt.rest)
+			}
+			if !reflect.DeepEqual(changed, tt.changed) {
+				t.Errorf("changed = %+v, want %+v", changed, tt.changed)
+			}
+		})
+	}
+
+	// Layer yang dihabiskan sedikit demi sedikit tetap mengeluarkan tepat
+	// nilai yang dibayar
+	remaining := []costLayer{layers[0]}
+	total := 0
+	for range 12 {
+		cost, changed, _ := takeFromLayers(remaining, 1000)
+		total += cost
+		remaining = changed
+	}
+	if total != 10000 || remaining[0].remaining != 0 || remaining[0].remainingValue != 0 {
+		t.Errorf("consuming one by one released %d, left %+v, want 10000 and an empty layer", total, remaining[0])
+	}
+}
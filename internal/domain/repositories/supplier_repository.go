@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"kasir-api/internal/domain/models"
+)
+
+type SupplierRepository interface {
+	GetAllSuppliers(ctx context.Context) ([]models.Supplier, error)
+	CreateSupplier(ctx context.Context, supplier *models.Supplier) error
+	GetSupplierByID(ctx context.Context, id int) (*models.Supplier, error)
+	UpdateSupplier(ctx context.Context, supplier *models.Supplier) error
+	// DeleteSupplier gagal dengan ConflictError jika supplier sudah punya purchase order
+	DeleteSupplier(ctx context.Context, id int) error
+}
+
+type supplierRepository struct {
+	db *sql.DB
+}
+
+func NewSupplierRepository(db *sql.DB) SupplierRepository {
+	return &supplierRepository{db: db}
+}
+
+const supplierColumns = "id, name, phone, email, address, note, created_at, updated_at"
+
+func scanSupplier(row interface{ Scan(...any) error }, s *models.Supplier) error {
+	return row.Scan(&s.ID, &s.Name, &s.Phone, &s.Email, &s.Address, &s.Note, &s.CreatedAt, &s.UpdatedAt)
+}
+
+func (repo *supplierRepository) GetAllSuppliers(ctx context.Context) ([]models.Supplier, error) {
+	rows, err := repo.db.QueryContext(ctx, "SELECT "+supplierColumns+" FROM suppliers ORDER BY name, id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suppliers := make([]models.Supplier, 0)
+	for rows.Next() {
+		var s models.Supplier
+		if err := scanSupplier(rows, &s); err != nil {
+			return nil, err
+		}
+		suppliers = append(suppliers, s)
+	}
+	return suppliers, rows.Err()
+}
+
+func (repo *supplierRepository) CreateSupplier(ctx context.Context, s *models.Supplier) error {
+	query := `INSERT INTO suppliers (name, phone, email, address, note) VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at`
+	err := repo.db.QueryRowContext(ctx, query, s.Name, s.Phone, s.Email, s.Address, s.Note).
+		Scan(&s.ID, &s.CreatedAt, &s.UpdatedAt)
+	if isUniqueViolation(err) {
+		return models.NewConflictError("supplier name already exists")
+	}
+	return mapDBError(ctx, err)
+}
+
+func (repo *supplierRepository) GetSupplierByID(ctx context.Context, id int) (*models.Supplier, error) {
+	var s models.Supplier
+	err := scanSupplier(repo.db.QueryRowContext(ctx, "SELECT "+supplierColumns+" FROM suppliers WHERE id = $1", id), &s)
+	if err == sql.ErrNoRows {
+		return nil, models.NewNotFoundError("supplier")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (repo *supplierRepository) UpdateSupplier(ctx context.Context, s *models.Supplier) error {
+	query := `UPDATE suppliers SET name = $2, phone = $3, email = $4, address = $5, note = $6, updated_at = NOW()
+		WHERE id = $1 RETURNING created_at, updated_at`
+	err := repo.db.QueryRowContext(ctx, query, s.ID, s.Name, s.Phone, s.Email, s.Address, s.Note).
+		Scan(&s.CreatedAt, &s.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return models.NewNotFoundError("supplier")
+	}
+	if isUniqueViolation(err) {
+		return models.NewConflictError("supplier name already exists")
+	}
+	return mapDBError(ctx, err)
+}
+
+func (repo *supplierRepository) DeleteSupplier(ctx context.Context, id int) error {
+	result, err := repo.db.ExecContext(ctx, "DELETE FROM suppliers WHERE id = $1", id)
+	if isForeignKeyViolation(err) {
+		return models.NewConflictError("supplier has purchase orders and cannot be deleted")
+	}
+	if err != nil {
+		return mapDBError(ctx, err)
+	}
+	return notFoundIfNoRows(result, "supplier")
+}
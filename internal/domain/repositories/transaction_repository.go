@@ -0,0 +1,392 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/pkg"
+
+	"github.com/sirupsen/logrus"
+)
+
+type TransactionRepository interface {
+	// CreateTransaction menyimpan penjualan ke shift yang sedang dibuka oleh userID.
+	// Details harus sudah terurut berdasarkan product ID supaya urutan lock konsisten.
+	CreateTransaction(ctx context.Context, userID int, transaction *models.Transaction) error
+	GetTransactionByID(ctx context.Context, id int) (*models.Transaction, error)
+}
+
+type transactionRepository struct {
+	db *sql.DB
+}
+
+func NewTransactionRepository(db *sql.DB) TransactionRepository {
+	return &transactionRepository{db: db}
+}
+
+// CreateTransaction menyimpan penjualan yang sudah dihitung harganya beserta
+// item, pembayaran dan promo yang terpakai, serta mengurangi stok dalam satu
+// database transaction. Baris produk dikunci dengan FOR UPDATE sehingga dua
+// kasir tidak bisa menjual stok yang sama, dan harga dicek ulang supaya
+// perubahan harga di antara perhitungan dan penyimpanan tidak lolos.
+func (repo *transactionRepository) CreateTransaction(ctx context.Context, userID int, transaction *models.Transaction) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// FOR SHARE menahan penutupan shift sampai checkout ini selesai
+	var shiftID int
+	err = tx.QueryRowContext(ctx, "SELECT id FROM shifts WHERE user_id = $1 AND status = 'open' FOR SHARE", userID).Scan(&shiftID)
+	if err == sql.ErrNoRows {
+		return models.NewConflictError("no open shift, open a shift before checkout")
+	}
+	if err != nil {
+		return err
+	}
+
+	if transaction.OrderID != nil {
+		if err := lockOrderForCheckout(ctx, tx, *transaction.OrderID, transaction.Details); err != nil {
+			return err
+		}
+	}
+
+	for _, item := range transaction.Details {
+		var price int
+		err := tx.QueryRowContext(ctx, "SELECT price FROM products WHERE id = $1 FOR UPDATE", item.ProductID).Scan(&price)
+		if err == sql.ErrNoRows {
+			return models.NewNotFoundError(fmt.Sprintf("product %d", item.ProductID))
+		}
+		if err != nil {
+			return err
+		}
+		if item.VariantID != nil {
+			var active bool
+			query := "SELECT price, active FROM product_variants WHERE id = $1 AND product_id = $2 FOR UPDATE"
+			err := tx.QueryRowContext(ctx, query, *item.VariantID, item.ProductID).Scan(&price, &active)
+			if err == sql.ErrNoRows {
+				return models.NewNotFoundError(fmt.Sprintf("variant %d of product %d", *item.VariantID, item.ProductID))
+			}
+			if err != nil {
+				return err
+			}
+			if !active {
+				return models.NewConflictError(fmt.Sprintf("variant %s of %s is no longer sold", item.VariantName, item.ProductName))
+			}
+		}
+		if price != item.Price {
+			return models.NewConflictError(fmt.Sprintf("price of %s has changed, please retry checkout", item.ProductName))
+		}
+	}
+
+	transaction.UserID = &userID
+	transaction.ShiftID = &shiftID
+	query := `INSERT INTO transactions (user_id, shift_id, subtotal_amount, discount_amount, service_charge_amount,
+		tax_amount, prices_include_tax, total_amount)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at`
+	err = tx.QueryRowContext(ctx, query, userID, shiftID, transaction.SubtotalAmount, transaction.DiscountAmount,
+		transaction.ServiceChargeAmount, transaction.TaxAmount, transaction.PricesIncludeTax, transaction.TotalAmount).
+		Scan(&transaction.ID, &transaction.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if transaction.OrderID != nil {
+		query := "UPDATE orders SET status = 'completed', transaction_id = $2, updated_at = NOW() WHERE id = $1"
+		if _, err := tx.ExecContext(ctx, query, *transaction.OrderID, transaction.ID); err != nil {
+			return mapDBError(ctx, err)
+		}
+	}
+
+	for i := range transaction.Details {
+		item := &transaction.Details[i]
+		item.TransactionID = transaction.ID
+
+		// Kurangi stok lewat ledger, sekaligus validasi stok cukup. HPP baris
+		// diambil dari nilai persediaan yang keluar.
+		movement := &models.StockMovement{
+			ProductID:   item.ProductID,
+			VariantID:   item.VariantID,
+			Delta:       -item.Quantity,
+			Reason:      models.StockReasonSale,
+			ReferenceID: fmt.Sprintf("TRX-%d", transaction.ID),
+		}
+		if err := applyStockMovement(ctx, tx, movement); err != nil {
+			return err
+		}
+
+		query := `INSERT INTO transaction_items (transaction_id, product_id, variant_id, quantity, price, discount, subtotal, tax_rate_bps,
+			service_charge_amount, tax_amount, total_amount, cost_amount)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id`
+		err := tx.QueryRowContext(ctx, query, transaction.ID, item.ProductID, item.VariantID, item.Quantity, item.Price, item.Discount, item.Subtotal,
+			item.TaxRateBps, item.ServiceChargeAmount, item.TaxAmount, item.TotalAmount, -movement.CostAmount).Scan(&item.ID)
+		if err != nil {
+			return mapDBError(ctx, err)
+		}
+	}
+
+	for i := range transaction.Payments {
+		transaction.Payments[i].TransactionID = transaction.ID
+		if err := insertPayment(ctx, tx, &transaction.Payments[i]); err != nil {
+			return err
+		}
+	}
+	transaction.PaidAmount, transaction.ChangeAmount = summarizePayments(transaction.Payments)
+
+	for _, promo := range transaction.Promotions {
+		query := `INSERT INTO transaction_promotions (transaction_id, promotion_id, name, code, discount_amount)
+			VALUES ($1, $2, $3, $4, $5)`
+		_, err := tx.ExecContext(ctx, query, transaction.ID, promo.PromotionID, promo.Name, promo.Code, promo.DiscountAmount)
+		if err != nil {
+			return mapDBError(ctx, err)
+		}
+	}
+
+	for _, tax := range transaction.Taxes {
+		query := `INSERT INTO transaction_taxes (transaction_id, rate_bps, taxable_amount, tax_amount)
+			VALUES ($1, $2, $3, $4)`
+		_, err := tx.ExecContext(ctx, query, transaction.ID, tax.RateBps, tax.TaxableAmount, tax.TaxAmount)
+		if err != nil {
+			return mapDBError(ctx, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"repository":     "transaction",
+		"action":         "create_transaction",
+		"transaction_id": transaction.ID,
+		"shift_id":       shiftID,
+		"items":          len(transaction.Details),
+		"payments":       len(transaction.Payments),
+		"promotions":     len(transaction.Promotions),
+	}).Info("Transaction committed")
+
+	return nil
+}
+
+// lockOrderForCheckout mengunci order yang akan di-checkout dan memastikan
+// order masih draft dengan item yang sama persis seperti yang sudah dihitung
+// harganya, sehingga perubahan order di tengah checkout tidak lolos
+func lockOrderForCheckout(ctx context.Context, tx *sql.Tx, orderID int, details []models.TransactionItem) error {
+	status, err := lockOrder(ctx, tx, orderID)
+	if err != nil {
+		return err
+	}
+	if status != models.OrderStatusDraft {
+		return models.NewConflictError(fmt.Sprintf("order is %s, only draft orders can be checked out", status))
+	}
+
+	// urutan sama dengan mergeCheckoutItems: product ID lalu variant ID
+	query := "SELECT product_id, COALESCE(variant_id, 0), quantity FROM order_items WHERE order_id = $1 ORDER BY product_id, COALESCE(variant_id, 0)"
+	rows, err := tx.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	i := 0
+	for rows.Next() {
+		var productID, variantID int
+		var quantity models.Quantity
+		if err := rows.Scan(&productID, &variantID, &quantity); err != nil {
+			return err
+		}
+		if i >= len(details) || details[i].ProductID != productID || variantIDOrZero(details[i].VariantID) != variantID ||
+			details[i].Quantity != quantity {
+			return models.NewConflictError("order has changed, please retry checkout")
+		}
+		i++
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if i != len(details) {
+		return models.NewConflictError("order has changed, please retry checkout")
+	}
+	return nil
+}
+
+// GetTransactionByID mengembalikan transaksi beserta item, pembayaran, promo dan rincian pajaknya
+func (repo *transactionRepository) GetTransactionByID(ctx context.Context, id int) (*models.Transaction, error) {
+	var transaction models.Transaction
+	var userID, shiftID sql.NullInt64
+	var orderID sql.NullInt64
+	query := `SELECT t.id, t.user_id, t.shift_id, o.id, t.subtotal_amount, t.discount_amount, t.service_charge_amount, t.tax_amount,
+		t.prices_include_tax, t.total_amount, t.created_at
+		FROM transactions t LEFT JOIN orders o ON o.transaction_id = t.id WHERE t.id = $1`
+	err := repo.db.QueryRowContext(ctx, query, id).
+		Scan(&transaction.ID, &userID, &shiftID, &orderID, &transaction.SubtotalAmount, &transaction.DiscountAmount, &transaction.ServiceChargeAmount,
+			&transaction.TaxAmount, &transaction.PricesIncludeTax, &transaction.TotalAmount, &transaction.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, models.NewNotFoundError("transaction")
+	}
+	if err != nil {
+		return nil, err
+	}
+	transaction.UserID = nullIntPtr(userID)
+	transaction.ShiftID = nullIntPtr(shiftID)
+	transaction.OrderID = nullIntPtr(orderID)
+
+	query = `SELECT ti.id, ti.transaction_id, ti.product_id, p.name, ti.variant_id, v.option_values, ti.quantity, ti.price, ti.discount, ti.subtotal, ti.tax_rate_bps,
+		ti.service_charge_amount, ti.tax_amount, ti.total_amount, COALESCE(r.quantity, 0), COALESCE(r.amount, 0)
+		FROM transaction_items ti JOIN products p ON p.id = ti.product_id
+		LEFT JOIN product_variants v ON v.id = ti.variant_id
+		LEFT JOIN (
+			SELECT transaction_item_id, SUM(quantity) AS quantity, SUM(amount) AS amount
+			FROM refund_items GROUP BY transaction_item_id
+		) r ON r.transaction_item_id = ti.id
+		WHERE ti.transaction_id = $1 ORDER BY ti.id`
+	rows, err := repo.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transaction.Details = make([]models.TransactionItem, 0)
+	for rows.Next() {
+		var item models.TransactionItem
+		var refundedAmount int
+		var variantID sql.NullInt64
+		var optionValues []string
+		if err := rows.Scan(&item.ID, &item.TransactionID, &item.ProductID, &item.ProductName, &variantID, textArray(&optionValues), &item.Quantity, &item.Price, &item.Discount,
+			&item.Subtotal, &item.TaxRateBps, &item.ServiceChargeAmount, &item.TaxAmount, &item.TotalAmount, &item.RefundedQuantity, &refundedAmount); err != nil {
+			return nil, err
+		}
+		item.VariantID = nullIntPtr(variantID)
+		if item.VariantID != nil {
+			item.VariantName = models.VariantName(optionValues)
+		}
+		transaction.RefundedAmount += refundedAmount
+		transaction.Details = append(transaction.Details, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	payments, err := repo.getPayments(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	transaction.Payments = payments
+	transaction.PaidAmount, transaction.ChangeAmount = summarizePayments(payments)
+
+	promotions, err := repo.getAppliedPromotions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	transaction.Promotions = promotions
+
+	taxes, err := repo.getTaxes(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	transaction.Taxes = taxes
+
+	return &transaction, nil
+}
+
+func (repo *transactionRepository) getTaxes(ctx context.Context, transactionID int) ([]models.TaxLine, error) {
+	query := `SELECT rate_bps, taxable_amount, tax_amount
+		FROM transaction_taxes WHERE transaction_id = $1 ORDER BY rate_bps`
+	rows, err := repo.db.QueryContext(ctx, query, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	taxes := make([]models.TaxLine, 0)
+	for rows.Next() {
+		var t models.TaxLine
+		if err := rows.Scan(&t.RateBps, &t.TaxableAmount, &t.TaxAmount); err != nil {
+			return nil, err
+		}
+		taxes = append(taxes, t)
+	}
+	return taxes, rows.Err()
+}
+
+func (repo *transactionRepository) getAppliedPromotions(ctx context.Context, transactionID int) ([]models.AppliedPromotion, error) {
+	query := `SELECT COALESCE(promotion_id, 0), name, code, discount_amount
+		FROM transaction_promotions WHERE transaction_id = $1 ORDER BY id`
+	rows, err := repo.db.QueryContext(ctx, query, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	promotions := make([]models.AppliedPromotion, 0)
+	for rows.Next() {
+		var p models.AppliedPromotion
+		if err := rows.Scan(&p.PromotionID, &p.Name, &p.Code, &p.DiscountAmount); err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, p)
+	}
+	return promotions, rows.Err()
+}
+
+func (repo *transactionRepository) getPayments(ctx context.Context, transactionID int) ([]models.Payment, error) {
+	query := `SELECT id, transaction_id, method, amount, tendered_amount, change_amount, COALESCE(reference, ''), created_at
+		FROM payments WHERE transaction_id = $1 ORDER BY id`
+	rows, err := repo.db.QueryContext(ctx, query, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payments := make([]models.Payment, 0)
+	for rows.Next() {
+		var p models.Payment
+		var tendered sql.NullInt64
+		if err := rows.Scan(&p.ID, &p.TransactionID, &p.Method, &p.Amount, &tendered, &p.ChangeAmount, &p.Reference, &p.CreatedAt); err != nil {
+			return nil, err
+		}
+		if tendered.Valid {
+			amount := int(tendered.Int64)
+			p.TenderedAmount = &amount
+		}
+		payments = append(payments, p)
+	}
+	return payments, rows.Err()
+}
+
+func insertPayment(ctx context.Context, tx *sql.Tx, p *models.Payment) error {
+	var reference sql.NullString
+	if p.Reference != "" {
+		reference = sql.NullString{String: p.Reference, Valid: true}
+	}
+
+	query := `INSERT INTO payments (transaction_id, method, amount, tendered_amount, change_amount, reference)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`
+	err := tx.QueryRowContext(ctx, query, p.TransactionID, p.Method, p.Amount, p.TenderedAmount, p.ChangeAmount, reference).
+		Scan(&p.ID, &p.CreatedAt)
+	return mapDBError(ctx, err)
+}
+
+// summarizePayments menghitung uang yang diterima (tunai dihitung dari
+// tendered amount) dan total kembalian
+func summarizePayments(payments []models.Payment) (paid, change int) {
+	for _, p := range payments {
+		if p.TenderedAmount != nil {
+			paid += *p.TenderedAmount
+		} else {
+			paid += p.Amount
+		}
+		change += p.ChangeAmount
+	}
+	return paid, change
+}
+
+// variantIDOrZero memetakan variant ID opsional ke int, 0 untuk baris tanpa varian
+func variantIDOrZero(variantID *int) int {
+	if variantID == nil {
+		return 0
+	}
+	return *variantID
+}
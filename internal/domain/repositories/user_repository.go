@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"kasir-api/internal/domain/models"
+)
+
+type UserRepository interface {
+	GetUserByUsername(ctx context.Context, username string) (*models.User, error)
+	GetUserByID(ctx context.Context, id int) (*models.User, error)
+	CreateUser(ctx context.Context, user *models.User) error
+	CountUsers(ctx context.Context) (int, error)
+}
+
+type userRepository struct {
+	db *sql.DB
+}
+
+func NewUserRepository(db *sql.DB) UserRepository {
+	return &userRepository{db: db}
+}
+
+func (repo *userRepository) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	query := "SELECT id, username, password_hash, role, created_at FROM users WHERE username = $1"
+
+	var u models.User
+	err := repo.db.QueryRowContext(ctx, query, username).Scan(&u.ID, &u.Username, &u.PasswordHash, &u.Role, &u.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, models.NewNotFoundError("user")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
+
+func (repo *userRepository) GetUserByID(ctx context.Context, id int) (*models.User, error) {
+	query := "SELECT id, username, password_hash, role, created_at FROM users WHERE id = $1"
+
+	var u models.User
+	err := repo.db.QueryRowContext(ctx, query, id).Scan(&u.ID, &u.Username, &u.PasswordHash, &u.Role, &u.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, models.NewNotFoundError("user")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
+
+func (repo *userRepository) CreateUser(ctx context.Context, user *models.User) error {
+	query := "INSERT INTO users (username, password_hash, role) VALUES ($1, $2, $3) RETURNING id, created_at"
+	err := repo.db.QueryRowContext(ctx, query, user.Username, user.PasswordHash, user.Role).Scan(&user.ID, &user.CreatedAt)
+	return mapDBError(ctx, err)
+}
+
+func (repo *userRepository) CountUsers(ctx context.Context) (int, error) {
+	var count int
+	err := repo.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users").Scan(&count)
+	return count, err
+}
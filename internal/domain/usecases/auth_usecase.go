@@ -0,0 +1,301 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/domain/repositories"
+	"kasir-api/internal/pkg"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrInvalidCredentials = models.NewUnauthorizedError("invalid username or password")
+	ErrInvalidToken       = models.NewUnauthorizedError("invalid or expired token")
+)
+
+const minPasswordLength = 8
+
+// AuthUseCase adalah interface untuk autentikasi dan manajemen user
+type AuthUseCase interface {
+	Login(ctx context.Context, req *models.LoginRequest) (*models.AuthToken, error)
+	Refresh(ctx context.Context, req *models.RefreshRequest) (*models.AuthToken, error)
+	CreateUser(ctx context.Context, req *models.CreateUserRequest) (*models.User, error)
+	EnsureAdmin(ctx context.Context, username, password string) error
+	// VerifySupervisor memeriksa kredensial supervisor (admin) untuk
+	// menyetujui aksi kasir seperti void order, tanpa menerbitkan token
+	VerifySupervisor(ctx context.Context, username, password string) (*models.User, error)
+}
+
+type authUseCase struct {
+	userRepo   repositories.UserRepository
+	secret     string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// NewAuthUseCase membuat instance baru dari AuthUseCase
+func NewAuthUseCase(userRepo repositories.UserRepository, secret string, accessTTL, refreshTTL time.Duration) AuthUseCase {
+	return &authUseCase{
+		userRepo:   userRepo,
+		secret:     secret,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+	}
+}
+
+// Login memverifikasi username & password lalu menerbitkan token
+func (uc *authUseCase) Login(ctx context.Context, req *models.LoginRequest) (*models.AuthToken, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":  "auth",
+		"action":   "login",
+		"username": req.Username,
+	}).Info("Executing login use case")
+
+	if req.Username == "" || req.Password == "" {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "auth",
+			"action":  "login",
+		}).Warn("Username and password are required")
+		return nil, models.NewValidationError("username", "username and password are required")
+	}
+
+	user, err := uc.userRepo.GetUserByUsername(ctx, req.Username)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":  "auth",
+			"action":   "login",
+			"username": req.Username,
+			"error":    err.Error(),
+		}).Warn("Login failed")
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":  "auth",
+			"action":   "login",
+			"username": req.Username,
+		}).Warn("Login failed, wrong password")
+		return nil, ErrInvalidCredentials
+	}
+
+	token, err := uc.issueToken(user)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "auth",
+			"action":  "login",
+			"error":   err.Error(),
+		}).Error("Failed to issue token")
+		return nil, err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "auth",
+		"action":  "login",
+		"user_id": user.ID,
+		"role":    user.Role,
+	}).Info("Successfully logged in")
+
+	return token, nil
+}
+
+func (uc *authUseCase) VerifySupervisor(ctx context.Context, username, password string) (*models.User, error) {
+	if username == "" || password == "" {
+		return nil, models.NewForbiddenError("supervisor approval is required")
+	}
+
+	user, err := uc.userRepo.GetUserByUsername(ctx, username)
+	if errors.Is(err, models.ErrNotFound) {
+		return nil, models.NewForbiddenError("invalid supervisor credentials")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":    "auth",
+			"action":     "verify_supervisor",
+			"supervisor": username,
+		}).Warn("Supervisor approval failed, wrong password")
+		return nil, models.NewForbiddenError("invalid supervisor credentials")
+	}
+	if user.Role != models.RoleAdmin {
+		return nil, models.NewForbiddenError("supervisor must be an admin")
+	}
+
+	return user, nil
+}
+
+// Refresh menukar refresh token yang valid dengan pasangan token baru
+func (uc *authUseCase) Refresh(ctx context.Context, req *models.RefreshRequest) (*models.AuthToken, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "auth",
+		"action":  "refresh",
+	}).Info("Executing refresh token use case")
+
+	claims, err := pkg.ParseToken(uc.secret, req.RefreshToken, pkg.TokenTypeRefresh)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "auth",
+			"action":  "refresh",
+			"error":   err.Error(),
+		}).Warn("Invalid refresh token")
+		return nil, ErrInvalidToken
+	}
+
+	// Ambil ulang user supaya perubahan role atau user yang dihapus langsung berlaku
+	user, err := uc.userRepo.GetUserByID(ctx, claims.UserID)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "auth",
+			"action":  "refresh",
+			"user_id": claims.UserID,
+			"error":   err.Error(),
+		}).Warn("User for refresh token not found")
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	token, err := uc.issueToken(user)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "auth",
+			"action":  "refresh",
+			"error":   err.Error(),
+		}).Error("Failed to issue token")
+		return nil, err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "auth",
+		"action":  "refresh",
+		"user_id": user.ID,
+	}).Info("Successfully refreshed token")
+
+	return token, nil
+}
+
+// CreateUser membuat user baru dengan password yang di-hash bcrypt
+func (uc *authUseCase) CreateUser(ctx context.Context, req *models.CreateUserRequest) (*models.User, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":  "auth",
+		"action":   "create_user",
+		"username": req.Username,
+		"role":     req.Role,
+	}).Info("Executing create user use case")
+
+	if req.Username == "" {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "auth",
+			"action":  "create_user",
+		}).Warn("Username is required")
+		return nil, models.NewValidationError("username", "username is required")
+	}
+
+	if len(req.Password) < minPasswordLength {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":  "auth",
+			"action":   "create_user",
+			"username": req.Username,
+		}).Warn("Password too short")
+		return nil, models.NewValidationError("password", "password must be at least 8 characters")
+	}
+
+	if req.Role != models.RoleAdmin && req.Role != models.RoleCashier {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "auth",
+			"action":  "create_user",
+			"role":    req.Role,
+		}).Warn("Invalid role")
+		return nil, models.NewValidationError("role", "role must be admin or cashier")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Username:     req.Username,
+		PasswordHash: string(hash),
+		Role:         req.Role,
+	}
+	if err := uc.userRepo.CreateUser(ctx, user); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":  "auth",
+			"action":   "create_user",
+			"username": req.Username,
+			"error":    err.Error(),
+		}).Error("Failed to create user")
+		return nil, err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "auth",
+		"action":  "create_user",
+		"user_id": user.ID,
+	}).Info("Successfully created user")
+
+	return user, nil
+}
+
+// EnsureAdmin membuat admin awal jika tabel users masih kosong
+func (uc *authUseCase) EnsureAdmin(ctx context.Context, username, password string) error {
+	count, err := uc.userRepo.CountUsers(ctx)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	if username == "" || password == "" {
+		pkg.LoggerFromContext(ctx).Warn("No users found, set ADMIN_USERNAME and ADMIN_PASSWORD to create the initial admin")
+		return nil
+	}
+
+	_, err = uc.CreateUser(ctx, &models.CreateUserRequest{
+		Username: username,
+		Password: password,
+		Role:     models.RoleAdmin,
+	})
+	return err
+}
+
+func (uc *authUseCase) issueToken(user *models.User) (*models.AuthToken, error) {
+	claims := pkg.TokenClaims{
+		UserID:   user.ID,
+		Username: user.Username,
+		Role:     user.Role,
+	}
+
+	claims.TokenType = pkg.TokenTypeAccess
+	accessToken, err := pkg.GenerateToken(uc.secret, claims, uc.accessTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	claims.TokenType = pkg.TokenTypeRefresh
+	refreshToken, err := pkg.GenerateToken(uc.secret, claims, uc.refreshTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AuthToken{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(uc.accessTTL.Seconds()),
+		User:         user,
+	}, nil
+}
@@ -0,0 +1,147 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/pkg"
+	"testing"
+	"time"
+)
+
+func newAuthFixture(t *testing.T) (AuthUseCase, *fakeUserRepo) {
+	t.Helper()
+	users := newFakeUserRepo(t,
+		models.User{ID: 1, Username: "admin", Role: models.RoleAdmin},
+		models.User{ID: 7, Username: "kasir", Role: models.RoleCashier},
+	)
+	return NewAuthUseCase(users, "secret", 15*time.Minute, time.Hour), users
+}
+
+func TestLogin(t *testing.T) {
+	uc, _ := newAuthFixture(t)
+	ctx := context.Background()
+
+	token, err := uc.Login(ctx, &models.LoginRequest{Username: "kasir", Password: "rahasia123"})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if token.TokenType != "Bearer" || token.ExpiresIn != 900 || token.User.ID != 7 {
+		t.Errorf("token = type %s expires %d user %d, want Bearer, 900, 7", token.TokenType, token.ExpiresIn, token.User.ID)
+	}
+	claims, err := pkg.ParseToken("secret", token.AccessToken, pkg.TokenTypeAccess)
+	if err != nil {
+		t.Fatalf("ParseToken(access) error = %v", err)
+	}
+	if claims.UserID != 7 || claims.Role != models.RoleCashier {
+		t.Errorf("claims = user %d role %s, want 7, cashier", claims.UserID, claims.Role)
+	}
+	if _, err := pkg.ParseToken("secret", token.RefreshToken, pkg.TokenTypeRefresh); err != nil {
+		t.Errorf("ParseToken(refresh) error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		req  models.LoginRequest
+		want error
+	}{
+		{name: "missing password", req: models.LoginRequest{Username: "kasir"}, want: models.ErrValidation},
+		{name: "wrong password", req: models.LoginRequest{Username: "kasir", Password: "salah"}, want: models.ErrUnauthorized},
+		{name: "unknown user", req: models.LoginRequest{Username: "bos", Password: "rahasia123"}, want: models.ErrUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.Login(ctx, &tt.req); !errors.Is(err, tt.want) {
+				t.Errorf("Login() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	uc, users := newAuthFixture(t)
+	ctx := context.Background()
+
+	token, err := uc.Login(ctx, &models.LoginRequest{Username: "kasir", Password: "rahasia123"})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	// role yang berubah setelah login langsung berlaku saat refresh
+	users.users["kasir"].Role = models.RoleAdmin
+	refreshed, err := uc.Refresh(ctx, &models.RefreshRequest{RefreshToken: token.RefreshToken})
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	claims, err := pkg.ParseToken("secret", refreshed.AccessToken, pkg.TokenTypeAccess)
+	if err != nil || claims.Role != models.RoleAdmin {
+		t.Errorf("refreshed claims = %+v, %v, want admin role", claims, err)
+	}
+
+	// access token tidak bisa dipakai sebagai refresh token
+	if _, err := uc.Refresh(ctx, &models.RefreshRequest{RefreshToken: token.AccessToken}); !errors.Is(err, models.ErrUnauthorized) {
+		t.Errorf("Refresh(access token) error = %v, want unauthorized", err)
+	}
+
+	delete(users.users, "kasir")
+	if _, err := uc.Refresh(ctx, &models.RefreshRequest{RefreshToken: token.RefreshToken}); !errors.Is(err, models.ErrUnauthorized) {
+		t.Errorf("Refresh() for deleted user error = %v, want unauthorized", err)
+	}
+}
+
+func TestCreateUser(t *testing.T) {
+	tests := []struct {
+		name string
+		req  models.CreateUserRequest
+		want error
+	}{
+		{name: "missing username", req: models.CreateUserRequest{Password: "rahasia123", Role: models.RoleCashier}, want: models.ErrValidation},
+		{name: "short password", req: models.CreateUserRequest{Username: "budi", Password: "1234567", Role: models.RoleCashier},
+			want: models.ErrValidation},
+		{name: "unknown role", req: models.CreateUserRequest{Username: "budi", Password: "rahasia123", Role: "manager"},
+			want: models.ErrValidation},
+		{name: "duplicate username", req: models.CreateUserRequest{Username: "kasir", Password: "rahasia123", Role: models.RoleCashier},
+			want: models.ErrConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, _ := newAuthFixture(t)
+			if _, err := uc.CreateUser(context.Background(), &tt.req); !errors.Is(err, tt.want) {
+				t.Errorf("CreateUser() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	uc, _ := newAuthFixture(t)
+	user, err := uc.CreateUser(context.Background(), &models.CreateUserRequest{Username: "budi", Password: "rahasia123", Role: models.RoleCashier})
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	if user.PasswordHash == "" || user.PasswordHash == "rahasia123" {
+		t.Errorf("password must be stored hashed")
+	}
+	if _, err := uc.Login(context.Background(), &models.LoginRequest{Username: "budi", Password: "rahasia123"}); err != nil {
+		t.Errorf("Login() as new user error = %v", err)
+	}
+}
+
+func TestEnsureAdmin(t *testing.T) {
+	ctx := context.Background()
+
+	users := newFakeUserRepo(t)
+	uc := NewAuthUseCase(users, "secret", time.Minute, time.Hour)
+	if err := uc.EnsureAdmin(ctx, "", ""); err != nil || len(users.users) != 0 {
+		t.Fatalf("EnsureAdmin() without credentials = %v, %d users, want no user", err, len(users.users))
+	}
+	if err := uc.EnsureAdmin(ctx, "owner", "rahasia123"); err != nil {
+		t.Fatalf("EnsureAdmin() error = %v", err)
+	}
+	if owner, ok := users.users["owner"]; !ok || owner.Role != models.RoleAdmin {
+		t.Fatalf("initial admin = %+v, want owner with admin role", owner)
+	}
+
+	// admin awal hanya dibuat sekali
+	if err := uc.EnsureAdmin(ctx, "other", "rahasia123"); err != nil || len(users.users) != 1 {
+		t.Errorf("EnsureAdmin() with existing users = %v, %d users, want 1", err, len(users.users))
+	}
+}
@@ -1,8 +1,9 @@
 package usecases
 
 import (
-	"errors"
+	"context"
 	"kasir-api/internal/domain/models"
+	"kasir-api/internal/domain/pricing"
 	"kasir-api/internal/domain/repositories"
 	"kasir-api/internal/pkg"
 
@@ -10,11 +11,11 @@ import (
 )
 
 type CategoryUseCase interface {
-	GetAllCategory() ([]models.Category, error)
-	CreateCategory(category *models.Category) error
-	GetCategoryByID(id int) (*models.Category, error)
-	UpdateCategory(*models.Category) error
-	DeleteCategory(id int) error
+	GetAllCategory(ctx context.Context) ([]models.Category, error)
+	CreateCategory(ctx context.Context, category *models.Category) error
+	GetCategoryByID(ctx context.Context, id int) (*models.Category, error)
+	UpdateCategory(ctx context.Context, category *models.Category) error
+	DeleteCategory(ctx context.Context, id int) error
 }
 
 type categoryUseCase struct {
@@ -27,23 +28,23 @@ func NewCategoryUseCase(categoryRepo repositories.CategoryRepository) CategoryUs
 	}
 }
 
-func (uc *categoryUseCase) GetAllCategory() ([]models.Category, error) {
-	pkg.Log.WithFields(logrus.Fields{
+func (uc *categoryUseCase) GetAllCategory(ctx context.Context) ([]models.Category, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 		"usecase": "category",
 		"action":  "get_all_category",
 	}).Info("Executing get all category use case")
 
-	categories, err := uc.categoryRepo.GetAllCategory()
+	categories, err := uc.categoryRepo.GetAllCategory(ctx)
 
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 			"usecase": "category",
 			"action":  "get_all_category",
 		}).Error("Failed to get all category use case")
 		return nil, err
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 		"usecase": "category",
 		"action":  "get_all_category",
 		"count":   len(categories),
@@ -52,41 +53,50 @@ func (uc *categoryUseCase) GetAllCategory() ([]models.Category, error) {
 	return categories, nil
 }
 
-func (uc *categoryUseCase) CreateCategory(category *models.Category) error {
-	pkg.Log.WithFields(logrus.Fields{
+func (uc *categoryUseCase) CreateCategory(ctx context.Context, category *models.Category) error {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 		"usecase": "category",
 		"action":  "create_category",
 	}).Info("Executing create category use case")
 
 	if category.Name == "" {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 			"usecase": "category",
 			"action":  "create_category",
 		}).Warn("Category name is required")
-		return errors.New("category name is required")
+		return models.NewValidationError("name", "category name is required")
 	}
 
 	if category.Description == "" {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 			"usecase": "category",
 			"action":  "create_category",
 		}).Warn("Category description is required")
-		return errors.New("category description is required")
+		return models.NewValidationError("description", "category description is required")
 	}
 
-	return uc.categoryRepo.CreateCategory(category)
+	if category.TaxRateBps != nil && (*category.TaxRateBps < 0 || *category.TaxRateBps > pricing.BasisPoints) {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":      "category",
+			"action":       "create_category",
+			"tax_rate_bps": *category.TaxRateBps,
+		}).Warn("Invalid category tax rate")
+		return models.NewValidationError("tax_rate_bps", "tax rate must be between 0 and 10000 basis points")
+	}
+
+	return uc.categoryRepo.CreateCategory(ctx, category)
 }
 
-func (uc *categoryUseCase) GetCategoryByID(id int) (*models.Category, error) {
-	pkg.Log.WithFields(logrus.Fields{
+func (uc *categoryUseCase) GetCategoryByID(ctx context.Context, id int) (*models.Category, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 		"usecase": "category",
 		"action":  "get_category_by_id",
 		"id":      id,
 	}).Info("Executing get category by ID use case")
 
-	category, err := uc.categoryRepo.GetCategoryByID(id)
+	category, err := uc.categoryRepo.GetCategoryByID(ctx, id)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 			"usecase": "category",
 			"action":  "get_category_by_id",
 			"id":      id,
@@ -94,7 +104,7 @@ func (uc *categoryUseCase) GetCategoryByID(id int) (*models.Category, error) {
 		return nil, err
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 		"usecase": "category",
 		"action":  "get_category_by_id",
 		"id":      id,
@@ -103,45 +113,55 @@ func (uc *categoryUseCase) GetCategoryByID(id int) (*models.Category, error) {
 	return category, nil
 }
 
-func (uc *categoryUseCase) UpdateCategory(category *models.Category) error {
+func (uc *categoryUseCase) UpdateCategory(ctx context.Context, category *models.Category) error {
 	if category.ID <= 0 {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 			"usecase": "category",
 			"action":  "update_category",
 			"id":      category.ID,
 		}).Warn("Invalid category ID")
-		return errors.New("invalid category ID")
+		return models.NewValidationError("id", "invalid category ID")
 	}
 
 	if category.Name == "" {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 			"usecase": "category",
 			"action":  "update_category",
 			"id":      category.ID,
 		}).Warn("Category name is required")
-		return errors.New("category name is required")
+		return models.NewValidationError("name", "category name is required")
 	}
 
 	if category.Description == "" {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 			"usecase": "category",
 			"action":  "update_category",
 			"id":      category.ID,
 		}).Warn("Category description is required")
-		return errors.New("category description is required")
+		return models.NewValidationError("description", "category description is required")
+	}
+
+	if category.TaxRateBps != nil && (*category.TaxRateBps < 0 || *category.TaxRateBps > pricing.BasisPoints) {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":      "category",
+			"action":       "update_category",
+			"id":           category.ID,
+			"tax_rate_bps": *category.TaxRateBps,
+		}).Warn("Invalid category tax rate")
+		return models.NewValidationError("tax_rate_bps", "tax rate must be between 0 and 10000 basis points")
 	}
 
-	existingCategory, err := uc.categoryRepo.GetCategoryByID(category.ID)
+	existingCategory, err := uc.categoryRepo.GetCategoryByID(ctx, category.ID)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 			"usecase": "category",
 			"action":  "update_category",
 			"id":      category.ID,
 			"error":   err.Error(),
 		}).Error("Category not found")
-		return errors.New("category not found")
+		return err
 	}
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 		"usecase":     "category",
 		"action":      "update_category",
 		"category_id": category.ID,
@@ -149,9 +169,9 @@ func (uc *categoryUseCase) UpdateCategory(category *models.Category) error {
 		"new_name":    category.Name,
 	}).Info("Updating category")
 
-	err = uc.categoryRepo.UpdateCategory(category)
+	err = uc.categoryRepo.UpdateCategory(ctx, category)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 			"usecase": "category",
 			"action":  "update_category",
 			"id":      category.ID,
@@ -160,7 +180,7 @@ func (uc *categoryUseCase) UpdateCategory(category *models.Category) error {
 		return err
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 		"usecase": "category",
 		"action":  "update_category",
 		"id":      category.ID,
@@ -169,43 +189,43 @@ func (uc *categoryUseCase) UpdateCategory(category *models.Category) error {
 	return nil
 }
 
-func (uc *categoryUseCase) DeleteCategory(id int) error {
-	pkg.Log.WithFields(logrus.Fields{
+func (uc *categoryUseCase) DeleteCategory(ctx context.Context, id int) error {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 		"usecase": "category",
 		"action":  "delete_category",
 		"id":      id,
 	}).Info("Executing delete category use case")
 
 	if id <= 0 {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 			"usecase": "category",
 			"action":  "delete_category",
 			"id":      id,
 		}).Warn("Invalid category ID")
-		return errors.New("invalid category ID")
+		return models.NewValidationError("id", "invalid category ID")
 	}
 
-	existingCategory, err := uc.categoryRepo.GetCategoryByID(id)
+	existingCategory, err := uc.categoryRepo.GetCategoryByID(ctx, id)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 			"usecase": "category",
 			"action":  "delete_category",
 			"id":      id,
 			"error":   err.Error(),
 		}).Error("Category not found")
-		return errors.New("category not found")
+		return err
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 		"usecase":       "category",
 		"action":        "delete_category",
 		"category_id":   id,
 		"category_name": existingCategory.Name,
 	}).Info("Deleting category")
 
-	err = uc.categoryRepo.DeleteCategory(id)
+	err = uc.categoryRepo.DeleteCategory(ctx, id)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 			"usecase": "category",
 			"action":  "delete_category",
 			"id":      id,
@@ -214,7 +234,7 @@ func (uc *categoryUseCase) DeleteCategory(id int) error {
 		return err
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 		"usecase": "category",
 		"action":  "delete_category",
 		"id":      id,
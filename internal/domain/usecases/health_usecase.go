@@ -1,8 +1,12 @@
 package usecases
 
 import (
+	"context"
+	"fmt"
 	"kasir-api/internal/domain/models"
+	"kasir-api/internal/domain/repositories"
 	"kasir-api/internal/pkg"
+	"runtime"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -10,25 +14,28 @@ import (
 
 // HealthUseCase adalah interface untuk health check use case
 type HealthUseCase interface {
-	CheckHealth() (*models.HealthResponse, error)
+	CheckHealth(ctx context.Context) (*models.HealthResponse, error)
+	CheckReadiness(ctx context.Context) *models.ReadinessResponse
 }
 
 type healthUseCase struct {
-	serviceName string
-	version     string
+	serviceName  string
+	healthRepo   repositories.HealthRepository
+	checkTimeout time.Duration
 }
 
 // NewHealthUseCase membuat instance baru dari HealthUseCase
-func NewHealthUseCase(serviceName, version string) HealthUseCase {
+func NewHealthUseCase(serviceName string, healthRepo repositories.HealthRepository, checkTimeout time.Duration) HealthUseCase {
 	return &healthUseCase{
-		serviceName: serviceName,
-		version:     version,
+		serviceName:  serviceName,
+		healthRepo:   healthRepo,
+		checkTimeout: checkTimeout,
 	}
 }
 
-// CheckHealth melakukan pengecekan kesehatan aplikasi
-func (h *healthUseCase) CheckHealth() (*models.HealthResponse, error) {
-	pkg.Log.WithFields(logrus.Fields{
+// CheckHealth adalah liveness check, hanya memastikan proses masih berjalan
+func (h *healthUseCase) CheckHealth(ctx context.Context) (*models.HealthResponse, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 		"usecase": "health_check",
 		"action":  "check_health",
 	}).Info("Executing health check use case")
@@ -37,10 +44,10 @@ func (h *healthUseCase) CheckHealth() (*models.HealthResponse, error) {
 		Status:    "healthy",
 		Timestamp: time.Now(),
 		Service:   h.serviceName,
-		Version:   h.version,
+		Version:   pkg.Version,
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 		"usecase": "health_check",
 		"action":  "check_health",
 		"status":  response.Status,
@@ -48,3 +55,90 @@ func (h *healthUseCase) CheckHealth() (*models.HealthResponse, error) {
 
 	return response, nil
 }
+
+// CheckReadiness mengecek semua dependency (database & schema migration).
+// Status "down" jika salah satu check gagal.
+func (h *healthUseCase) CheckReadiness(ctx context.Context) *models.ReadinessResponse {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "health_check",
+		"action":  "check_readiness",
+	}).Info("Executing readiness check use case")
+
+	ctx, cancel := context.WithTimeout(ctx, h.checkTimeout)
+	defer cancel()
+
+	response := &models.ReadinessResponse{
+		Status:    models.HealthStatusUp,
+		Timestamp: time.Now(),
+		Service:   h.serviceName,
+		Build: models.BuildInfo{
+			Version:   pkg.Version,
+			Commit:    pkg.Commit,
+			BuildTime: pkg.BuildTime,
+			GoVersion: runtime.Version(),
+		},
+	}
+
+	response.Checks = append(response.Checks, runCheck("database", func() error {
+		return h.healthRepo.Ping(ctx)
+	}))
+
+	response.Checks = append(response.Checks, runCheck("migrations", func() error {
+		current, latest, err := h.healthRepo.MigrationVersion(ctx)
+		response.Migrations = models.MigrationInfo{CurrentVersion: current, LatestVersion: latest}
+		if err != nil {
+			return err
+		}
+		if current < latest {
+			return fmt.Errorf("schema version %d is behind %d", current, latest)
+		}
+		return nil
+	}))
+
+	stats := h.healthRepo.Stats()
+	response.Database = models.DatabasePoolStats{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDurationMs:     stats.WaitDuration.Milliseconds(),
+		MaxIdleClosed:      stats.MaxIdleClosed,
+		MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+	}
+
+	for _, check := range response.Checks {
+		if check.Status != models.HealthStatusUp {
+			response.Status = models.HealthStatusDown
+		}
+	}
+
+	entry := pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "health_check",
+		"action":  "check_readiness",
+		"status":  response.Status,
+	})
+	if response.Status == models.HealthStatusUp {
+		entry.Info("Readiness check completed")
+	} else {
+		entry.Warn("Readiness check failed")
+	}
+
+	return response
+}
+
+func runCheck(name string, check func() error) models.DependencyCheck {
+	start := time.Now()
+	err := check()
+
+	result := models.DependencyCheck{
+		Name:      name,
+		Status:    models.HealthStatusUp,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = models.HealthStatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,356 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/domain/repositories"
+	"kasir-api/internal/pkg"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultOrdersPerPage = 20
+	maxOrdersPerPage     = 100
+)
+
+type OrderUseCase interface {
+	CreateOrder(ctx context.Context, req *models.CreateOrderRequest) (*models.Order, error)
+	GetOrderByID(ctx context.Context, id int) (*models.Order, error)
+	GetOrders(ctx context.Context, status string, page, perPage int) ([]models.Order, error)
+	UpdateOrder(ctx context.Context, id int, req *models.UpdateOrderRequest) (*models.Order, error)
+	HoldOrder(ctx context.Context, id int) (*models.Order, error)
+	ResumeOrder(ctx context.Context, id int) (*models.Order, error)
+	VoidOrder(ctx context.Context, id int, req *models.VoidOrderRequest) (*models.Order, error)
+}
+
+type orderUseCase struct {
+	orderRepo   repositories.OrderRepository
+	productRepo repositories.ProductRepository
+	authUseCase AuthUseCase
+}
+
+// NewOrderUseCase membuat instance baru dari OrderUseCase. authUseCase dipakai
+// untuk memverifikasi persetujuan supervisor saat kasir melakukan void.
+func NewOrderUseCase(orderRepo repositories.OrderRepository, productRepo repositories.ProductRepository, authUseCase AuthUseCase) OrderUseCase {
+	return &orderUseCase{
+		orderRepo:   orderRepo,
+		productRepo: productRepo,
+		authUseCase: authUseCase,
+	}
+}
+
+func (uc *orderUseCase) CreateOrder(ctx context.Context, req *models.CreateOrderRequest) (*models.Order, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "order",
+		"action":  "create_order",
+		"items":   len(req.Items),
+	}).Info("Executing create order use case")
+
+	user, ok := pkg.AuthUserFromContext(ctx)
+	if !ok {
+		return nil, models.NewUnauthorizedError("authenticated user is required")
+	}
+
+	// draft boleh kosong, item bisa ditambahkan belakangan lewat PATCH
+	items := make([]models.CheckoutItem, 0)
+	if len(req.Items) > 0 {
+		converted, err := toBaseUnits(ctx, uc.productRepo, req.Items)
+		if err != nil {
+			return nil, err
+		}
+		merged, err := mergeCheckoutItems(converted)
+		if err != nil {
+			return nil, err
+		}
+		items = merged
+	}
+	if err := uc.ensureProductsExist(ctx, items); err != nil {
+		return nil, err
+	}
+	if err := validateOrderText(req.Note, req.PromoCode); err != nil {
+		return nil, err
+	}
+
+	order := &models.Order{
+		UserID:    &user.ID,
+		Status:    models.OrderStatusDraft,
+		Note:      strings.TrimSpace(req.Note),
+		PromoCode: strings.ToUpper(strings.TrimSpace(req.PromoCode)),
+	}
+	for _, item := range items {
+		order.Items = append(order.Items, models.OrderItem{ProductID: item.ProductID, VariantID: item.VariantID, Quantity: item.Quantity})
+	}
+
+	if err := uc.orderRepo.CreateOrder(ctx, order); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "order",
+			"action":  "create_order",
+			"error":   err.Error(),
+		}).Error("Failed to create order")
+		return nil, err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":  "order",
+		"action":   "create_order",
+		"order_id": order.ID,
+	}).Info("Successfully created order")
+
+	return uc.orderRepo.GetOrderByID(ctx, order.ID)
+}
+
+func (uc *orderUseCase) GetOrderByID(ctx context.Context, id int) (*models.Order, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "order",
+		"action":  "get_order_by_id",
+		"id":      id,
+	}).Info("Executing get order by ID use case")
+
+	return uc.orderRepo.GetOrderByID(ctx, id)
+}
+
+func (uc *orderUseCase) GetOrders(ctx context.Context, status string, page, perPage int) ([]models.Order, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "order",
+		"action":  "get_orders",
+		"status":  status,
+	}).Info("Executing get orders use case")
+
+	switch status {
+	case "", models.OrderStatusDraft, models.OrderStatusHeld, models.OrderStatusVoided, models.OrderStatusCompleted:
+	default:
+		return nil, models.NewValidationError("status", "status must be one of draft, held, voided, completed")
+	}
+	if page <= 0 {
+		page = 1
+	}
+	if perPage <= 0 {
+		perPage = defaultOrdersPerPage
+	}
+	if perPage > maxOrdersPerPage {
+		perPage = maxOrdersPerPage
+	}
+
+	return uc.orderRepo.GetOrders(ctx, status, page, perPage)
+}
+
+func (uc *orderUseCase) UpdateOrder(ctx context.Context, id int, req *models.UpdateOrderRequest) (*models.Order, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "order",
+		"action":  "update_order",
+		"id":      id,
+		"items":   len(req.Items),
+	}).Info("Executing update order use case")
+
+	// item terakhir untuk produk dan varian yang sama yang berlaku, diurutkan
+	// supaya urutan lock konsisten
+	reqItems, err := toBaseUnits(ctx, uc.productRepo, req.Items)
+	if err != nil {
+		return nil, err
+	}
+	type lineKey struct{ productID, variantID int }
+	quantities := make(map[lineKey]models.Quantity)
+	for _, item := range reqItems {
+		if item.ProductID <= 0 {
+			return nil, models.NewValidationError("product_id", "invalid product ID")
+		}
+		if item.VariantID != nil && *item.VariantID <= 0 {
+			return nil, models.NewValidationError("variant_id", "invalid variant ID")
+		}
+		if item.Quantity < 0 {
+			return nil, models.NewValidationError("quantity", "quantity cannot be negative")
+		}
+		quantities[lineKey{item.ProductID, variantKey(item.VariantID)}] = item.Quantity
+	}
+	items := make([]models.CheckoutItem, 0, len(quantities))
+	added := make([]models.CheckoutItem, 0, len(quantities))
+	for key, quantity := range quantities {
+		item := models.CheckoutItem{ProductID: key.productID, Quantity: quantity}
+		if key.variantID != 0 {
+			variantID := key.variantID
+			item.VariantID = &variantID
+		}
+		items = append(items, item)
+		if quantity > 0 {
+			added = append(added, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].ProductID != items[j].ProductID {
+			return items[i].ProductID < items[j].ProductID
+		}
+		return variantKey(items[i].VariantID) < variantKey(items[j].VariantID)
+	})
+	if err := uc.ensureProductsExist(ctx, added); err != nil {
+		return nil, err
+	}
+
+	var note, promoCode *string
+	if req.Note != nil {
+		trimmed := strings.TrimSpace(*req.Note)
+		note = &trimmed
+	}
+	if req.PromoCode != nil {
+		code := strings.ToUpper(strings.TrimSpace(*req.PromoCode))
+		promoCode = &code
+	}
+	if err := validateOrderText(deref(note), deref(promoCode)); err != nil {
+		return nil, err
+	}
+
+	if err := uc.orderRepo.UpdateOrder(ctx, id, note, promoCode, items); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "order",
+			"action":  "update_order",
+			"id":      id,
+			"error":   err.Error(),
+		}).Error("Failed to update order")
+		return nil, err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "order",
+		"action":  "update_order",
+		"id":      id,
+	}).Info("Successfully updated order")
+
+	return uc.orderRepo.GetOrderByID(ctx, id)
+}
+
+// HoldOrder memarkir order draft supaya kasir bisa melayani pelanggan lain
+func (uc *orderUseCase) HoldOrder(ctx context.Context, id int) (*models.Order, error) {
+	return uc.setStatus(ctx, "hold_order", id, models.OrderStatusHeld, models.OrderStatusDraft)
+}
+
+// ResumeOrder mengembalikan order yang diparkir menjadi draft
+func (uc *orderUseCase) ResumeOrder(ctx context.Context, id int) (*models.Order, error) {
+	return uc.setStatus(ctx, "resume_order", id, models.OrderStatusDraft, models.OrderStatusHeld)
+}
+
+func (uc *orderUseCase) setStatus(ctx context.Context, action string, id int, to string, from ...string) (*models.Order, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "order",
+		"action":  action,
+		"id":      id,
+	}).Info("Executing order status use case")
+
+	if err := uc.orderRepo.SetOrderStatus(ctx, id, to, from...); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "order",
+			"action":  action,
+			"id":      id,
+			"error":   err.Error(),
+		}).Warn("Failed to change order status")
+		return nil, err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "order",
+		"action":  action,
+		"id":      id,
+		"status":  to,
+	}).Info("Successfully changed order status")
+
+	return uc.orderRepo.GetOrderByID(ctx, id)
+}
+
+// VoidOrder membatalkan order draft/held. Admin menyetujui sendiri, kasir
+// harus menyertakan kredensial supervisor.
+func (uc *orderUseCase) VoidOrder(ctx context.Context, id int, req *models.VoidOrderRequest) (*models.Order, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "order",
+		"action":  "void_order",
+		"id":      id,
+	}).Info("Executing void order use case")
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, models.NewValidationError("reason", "void reason is required")
+	}
+	if len(reason) > 500 {
+		return nil, models.NewValidationError("reason", "reason must be at most 500 characters")
+	}
+
+	user, ok := pkg.AuthUserFromContext(ctx)
+	if !ok {
+		return nil, models.NewUnauthorizedError("authenticated user is required")
+	}
+
+	approverID := user.ID
+	if user.Role != models.RoleAdmin {
+		supervisor, err := uc.authUseCase.VerifySupervisor(ctx, req.SupervisorUsername, req.SupervisorPassword)
+		if err != nil {
+			pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+				"usecase": "order",
+				"action":  "void_order",
+				"id":      id,
+				"error":   err.Error(),
+			}).Warn("Void order not approved")
+			return nil, err
+		}
+		approverID = supervisor.ID
+	}
+
+	if err := uc.orderRepo.VoidOrder(ctx, id, reason, approverID); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "order",
+			"action":  "void_order",
+			"id":      id,
+			"error":   err.Error(),
+		}).Error("Failed to void order")
+		return nil, err
+	}
+
+	pkg.OrdersVoidedTotal.Inc()
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":     "order",
+		"action":      "void_order",
+		"id":          id,
+		"approved_by": approverID,
+	}).Info("Successfully voided order")
+
+	return uc.orderRepo.GetOrderByID(ctx, id)
+}
+
+// ensureProductsExist memastikan produk ada dan varian yang disebut cocok
+// dengan produknya, dengan aturan yang sama seperti saat checkout
+func (uc *orderUseCase) ensureProductsExist(ctx context.Context, items []models.CheckoutItem) error {
+	for _, item := range items {
+		product, err := uc.productRepo.GetProductByID(ctx, item.ProductID)
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				return models.NewNotFoundError(fmt.Sprintf("product %d", item.ProductID))
+			}
+			return err
+		}
+		if _, err := resolveVariant(product, item.VariantID); err != nil {
+			return err
+		}
+		if err := validateQuantity(product, item.Quantity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateOrderText(note, promoCode string) error {
+	if len(note) > 500 {
+		return models.NewValidationError("note", "note must be at most 500 characters")
+	}
+	if len(promoCode) > 50 {
+		return models.NewValidationError("promo_code", "promo code must be at most 50 characters")
+	}
+	return nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
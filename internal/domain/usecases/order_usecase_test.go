@@ -0,0 +1,309 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/domain/pricing"
+	"kasir-api/internal/domain/repositories"
+	"kasir-api/internal/pkg"
+	"slices"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fakeOrderRepo menyimpan order di memori dengan aturan status yang sama
+// seperti repository asli
+type fakeOrderRepo struct {
+	repositories.OrderRepository
+	orders map[int]*models.Order
+	// updated adalah item terakhir yang dikirim ke UpdateOrder
+	updated []models.CheckoutItem
+}
+
+func (f *fakeOrderRepo) CreateOrder(ctx context.Context, order *models.Order) error {
+	order.ID = len(f.orders) + 1
+	copied := *order
+	f.orders[order.ID] = &copied
+	return nil
+}
+
+func (f *fakeOrderRepo) GetOrderByID(ctx context.Context, id int) (*models.Order, error) {
+	order, ok := f.orders[id]
+	if !ok {
+		return nil, models.NewNotFoundError("order")
+	}
+	copied := *order
+	return &copied, nil
+}
+
+func (f *fakeOrderRepo) UpdateOrder(ctx context.Context, id int, note, promoCode *string, items []models.CheckoutItem) error {
+	if _, ok := f.orders[id]; !ok {
+		return models.NewNotFoundError("order")
+	}
+	f.updated = items
+	return nil
+}
+
+func (f *fakeOrderRepo) SetOrderStatus(ctx context.Context, id int, to string, from ...string) error {
+	order, ok := f.orders[id]
+	if !ok {
+		return models.NewNotFoundError("order")
+	}
+	if !slices.Contains(from, order.Status) {
+		return models.NewConflictError(fmt.Sprintf("order is %s", order.Status))
+	}
+	order.Status = to
+	return nil
+}
+
+func (f *fakeOrderRepo) VoidOrder(ctx context.Context, id int, reason string, voidedBy int) error {
+	order, ok := f.orders[id]
+	if !ok {
+		return models.NewNotFoundError("order")
+	}
+	if order.Status != models.OrderStatusDraft && order.Status != models.OrderStatusHeld {
+		return models.NewConflictError(fmt.Sprintf("order is %s, only draft or held orders can be voided", order.Status))
+	}
+	now := time.Now()
+	order.Status, order.VoidReason, order.VoidedBy, order.VoidedAt = models.OrderStatusVoided, reason, &voidedBy, &now
+	return nil
+}
+
+// fakeUserRepo menyimpan user di memori berdasarkan username
+type fakeUserRepo struct {
+	users map[string]*models.User
+}
+
+func (f *fakeUserRepo) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	user, ok := f.users[username]
+	if !ok {
+		return nil, models.NewNotFoundError("user")
+	}
+	return user, nil
+}
+
+func (f *fakeUserRepo) GetUserByID(ctx context.Context, id int) (*models.User, error) {
+	for _, user := range f.users {
+		if user.ID == id {
+			return user, nil
+		}
+	}
+	return nil, models.NewNotFoundError("user")
+}
+
+func (f *fakeUserRepo) CreateUser(ctx context.Context, user *models.User) error {
+	if _, ok := f.users[user.Username]; ok {
+		return models.NewConflictError("username already exists")
+	}
+	user.ID = len(f.users) + 1
+	f.users[user.Username] = user
+	return nil
+}
+
+func (f *fakeUserRepo) CountUsers(ctx context.Context) (int, error) {
+	return len(f.users), nil
+}
+
+// newFakeUserRepo membuat user dengan password "rahasia123"
+func newFakeUserRepo(t *testing.T, users ...models.User) *fakeUserRepo {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte("rahasia123"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+	repo := &fakeUserRepo{users: make(map[string]*models.User)}
+	for _, user := range users {
+		user.PasswordHash = string(hash)
+		repo.users[user.Username] = &user
+	}
+	return repo
+}
+
+func newOrderFixture(t *testing.T) (OrderUseCase, *fakeOrderRepo) {
+	t.Helper()
+	orders := &fakeOrderRepo{orders: map[int]*models.Order{
+		1: {ID: 1, Status: models.OrderStatusDraft},
+		2: {ID: 2, Status: models.OrderStatusHeld},
+		3: {ID: 3, Status: models.OrderStatusCompleted},
+	}}
+	users := newFakeUserRepo(t,
+		models.User{ID: 1, Username: "admin", Role: models.RoleAdmin},
+		models.User{ID: 7, Username: "kasir", Role: models.RoleCashier},
+	)
+	auth := NewAuthUseCase(users, "secret", time.Minute, time.Hour)
+	return NewOrderUseCase(orders, newFakeProducts(), auth), orders
+}
+
+func adminContext() context.Context {
+	return pkg.WithAuthUser(context.Background(), &models.AuthUser{ID: 1, Username: "admin", Role: models.RoleAdmin})
+}
+
+func TestVoidOrderApproval(t *testing.T) {
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		orderID    int
+		req        models.VoidOrderRequest
+		want       error
+		approvedBy int
+	}{
+		{name: "admin approves own void", ctx: adminContext(), orderID: 1, req: models.VoidOrderRequest{Reason: "salah input"},
+			approvedBy: 1},
+		{name: "cashier with supervisor", ctx: cashierContext(), orderID: 2, approvedBy: 1,
+			req: models.VoidOrderRequest{Reason: "pelanggan batal", SupervisorUsername: "admin", SupervisorPassword: "rahasia123"}},
+		{name: "cashier without supervisor", ctx: cashierContext(), orderID: 1, want: models.ErrForbidden,
+			req: models.VoidOrderRequest{Reason: "pelanggan batal"}},
+		{name: "wrong supervisor password", ctx: cashierContext(), orderID: 1, want: models.ErrForbidden,
+			req: models.VoidOrderRequest{Reason: "pelanggan batal", SupervisorUsername: "admin", SupervisorPassword: "salah"}},
+		{name: "unknown supervisor", ctx: cashierContext(), orderID: 1, want: models.ErrForbidden,
+			req: models.VoidOrderRequest{Reason: "pelanggan batal", SupervisorUsername: "bos", SupervisorPassword: "rahasia123"}},
+		{name: "cashier cannot approve", ctx: cashierContext(), orderID: 1, want: models.ErrForbidden,
+			req: models.VoidOrderRequest{Reason: "pelanggan batal", SupervisorUsername: "kasir", SupervisorPassword: "rahasia123"}},
+		{name: "reason required", ctx: adminContext(), orderID: 1, want: models.ErrValidation,
+			req: models.VoidOrderRequest{Reason: "  "}},
+		{name: "completed order", ctx: adminContext(), orderID: 3, want: models.ErrConflict,
+			req: models.VoidOrderRequest{Reason: "salah input"}},
+		{name: "no authenticated user", ctx: context.Background(), orderID: 1, want: models.ErrUnauthorized,
+			req: models.VoidOrderRequest{Reason: "salah input"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, orders := newOrderFixture(t)
+			order, err := uc.VoidOrder(tt.ctx, tt.orderID, &tt.req)
+			if tt.want != nil {
+				if !errors.Is(err, tt.want) {
+					t.Fatalf("VoidOrder() error = %v, want %v", err, tt.want)
+				}
+				if orders.orders[tt.orderID].Status == models.OrderStatusVoided {
+					t.Errorf("rejected void must not change the order")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VoidOrder() error = %v", err)
+			}
+			if order.Status != models.OrderStatusVoided || order.VoidedBy == nil || *order.VoidedBy != tt.approvedBy {
+				t.Errorf("order = status %s voided by %v, want voided by %d", order.Status, order.VoidedBy, tt.approvedBy)
+			}
+		})
+	}
+}
+
+func TestHoldAndResumeOrder(t *testing.T) {
+	uc, _ := newOrderFixture(t)
+	ctx := cashierContext()
+
+	order, err := uc.HoldOrder(ctx, 1)
+	if err != nil || order.Status != models.OrderStatusHeld {
+		t.Fatalf("HoldOrder() = %v, %v, want held", order, err)
+	}
+	if _, err := uc.HoldOrder(ctx, 1); !errors.Is(err, models.ErrConflict) {
+		t.Errorf("HoldOrder() on held order error = %v, want conflict", err)
+	}
+	order, err = uc.ResumeOrder(ctx, 1)
+	if err != nil || order.Status != models.OrderStatusDraft {
+		t.Fatalf("ResumeOrder() = %v, %v, want draft", order, err)
+	}
+	if _, err := uc.ResumeOrder(ctx, 3); !errors.Is(err, models.ErrConflict) {
+		t.Errorf("ResumeOrder() on completed order error = %v, want conflict", err)
+	}
+}
+
+func TestCreateOrderMergesItems(t *testing.T) {
+	uc, orders := newOrderFixture(t)
+
+	order, err := uc.CreateOrder(cashierContext(), &models.CreateOrderRequest{
+		PromoCode: " hemat10 ",
+		Items: []models.CheckoutItem{
+			{ProductID: 2, Quantity: models.WholeQuantity(1)},
+			{ProductID: 1, Quantity: models.WholeQuantity(1), Unit: "dus"},
+			{ProductID: 2, Quantity: models.WholeQuantity(2)},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+	saved := orders.orders[order.ID]
+	if saved.Status != models.OrderStatusDraft || saved.PromoCode != "HEMAT10" || saved.UserID == nil || *saved.UserID != 7 {
+		t.Errorf("order = status %s promo %q user %v, want draft, HEMAT10, 7", saved.Status, saved.PromoCode, saved.UserID)
+	}
+	if len(saved.Items) != 2 || saved.Items[0].ProductID != 1 || saved.Items[0].Quantity != models.WholeQuantity(40) ||
+		saved.Items[1].Quantity != models.WholeQuantity(3) {
+		t.Errorf("items = %+v, want 40 x product 1 and 3 x product 2", saved.Items)
+	}
+
+	if _, err := uc.CreateOrder(cashierContext(), &models.CreateOrderRequest{
+		Items: []models.CheckoutItem{{ProductID: 3, Quantity: models.WholeQuantity(1)}},
+	}); !errors.Is(err, models.ErrValidation) {
+		t.Errorf("CreateOrder() without variant error = %v, want validation", err)
+	}
+}
+
+func TestUpdateOrderKeepsLastQuantity(t *testing.T) {
+	uc, orders := newOrderFixture(t)
+
+	// baris terakhir untuk produk yang sama yang berlaku, quantity 0
+	// menghapus baris dan tidak perlu produk yang masih ada
+	_, err := uc.UpdateOrder(cashierContext(), 1, &models.UpdateOrderRequest{Items: []models.CheckoutItem{
+		{ProductID: 2, Quantity: models.WholeQuantity(5)},
+		{ProductID: 99, Quantity: 0},
+		{ProductID: 2, Quantity: models.WholeQuantity(1)},
+	}})
+	if err != nil {
+		t.Fatalf("UpdateOrder() error = %v", err)
+	}
+	if len(orders.updated) != 2 || orders.updated[0].ProductID != 2 || orders.updated[0].Quantity != models.WholeQuantity(1) ||
+		orders.updated[1].ProductID != 99 || orders.updated[1].Quantity != 0 {
+		t.Errorf("updated items = %+v, want product 2 x 1 then product 99 x 0", orders.updated)
+	}
+
+	if _, err := uc.UpdateOrder(cashierContext(), 1, &models.UpdateOrderRequest{Items: []models.CheckoutItem{
+		{ProductID: 2, Quantity: -models.WholeQuantity(1)},
+	}}); !errors.Is(err, models.ErrValidation) {
+		t.Errorf("UpdateOrder() with negative quantity error = %v, want validation", err)
+	}
+}
+
+func TestCheckoutFromOrder(t *testing.T) {
+	products := newFakeProducts()
+	orders := &fakeOrderRepo{orders: map[int]*models.Order{
+		1: {ID: 1, Status: models.OrderStatusDraft, Items: []models.OrderItem{
+			{ProductID: 2, Quantity: models.WholeQuantity(2)},
+			{ProductID: 1, Quantity: models.WholeQuantity(1)},
+		}},
+		2: {ID: 2, Status: models.OrderStatusHeld, Items: []models.OrderItem{{ProductID: 2, Quantity: models.WholeQuantity(1)}}},
+	}}
+	transactions := &fakeTransactionRepo{products: products}
+	uc := NewTransactionUseCase(transactions, products, &fakePromotionRepo{}, orders, time.UTC, pricing.TaxSettings{})
+
+	transaction, err := uc.Checkout(cashierContext(), &models.CheckoutRequest{OrderID: intPtr(1), Payments: cash(13500, nil)})
+	if err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+	if transaction.OrderID == nil || *transaction.OrderID != 1 || len(transaction.Details) != 2 || transaction.Details[0].ProductID != 1 {
+		t.Errorf("transaction = order %v details %+v, want order 1 sorted by product", transaction.OrderID, transaction.Details)
+	}
+
+	tests := []struct {
+		name string
+		req  models.CheckoutRequest
+		want error
+	}{
+		{name: "held order", req: models.CheckoutRequest{OrderID: intPtr(2), Payments: cash(5000, nil)}, want: models.ErrConflict},
+		{name: "unknown order", req: models.CheckoutRequest{OrderID: intPtr(9), Payments: cash(5000, nil)}, want: models.ErrNotFound},
+		{name: "items with order", want: models.ErrValidation, req: models.CheckoutRequest{OrderID: intPtr(1), Payments: cash(5000, nil),
+			Items: []models.CheckoutItem{{ProductID: 2, Quantity: models.WholeQuantity(1)}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.Checkout(cashierContext(), &tt.req); !errors.Is(err, tt.want) {
+				t.Errorf("Checkout() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
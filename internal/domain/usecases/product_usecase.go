@@ -1,21 +1,35 @@
 package usecases
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"kasir-api/internal/domain/barcode"
 	"kasir-api/internal/domain/models"
+	"kasir-api/internal/domain/pricing"
 	"kasir-api/internal/domain/repositories"
 	"kasir-api/internal/pkg"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 )
 
 // ProductUseCase adalah interface untuk product use cases
 type ProductUseCase interface {
-	GetAllProducts(name string) ([]models.Product, error)
-	CreateProduct(product *models.Product) error
-	GetProductByID(id int) (*models.Product, error)
-	UpdateProduct(product *models.Product) error
-	DeleteProduct(id int) error
+	GetAllProducts(ctx context.Context, filter models.ProductFilter) (*models.ProductList, error)
+	CreateProduct(ctx context.Context, product *models.Product) error
+	GetProductByID(ctx context.Context, id int) (*models.Product, error)
+	UpdateProduct(ctx context.Context, product *models.Product) error
+	DeleteProduct(ctx context.Context, id int) error
+	// GetProductByBarcode dipakai scanner, code boleh EAN-8, UPC-A atau EAN-13
+	GetProductByBarcode(ctx context.Context, code string) (*models.Product, error)
+	// GenerateBarcode menambahkan barcode internal untuk produk tanpa kode pabrik
+	GenerateBarcode(ctx context.Context, id int) (*models.Product, error)
+	// SetProductOptions mengganti sumbu opsi produk dan membentuk ulang matriks varian
+	SetProductOptions(ctx context.Context, id int, req models.SetProductOptionsRequest) (*models.Product, error)
+	UpdateVariant(ctx context.Context, productID, variantID int, req models.UpdateVariantRequest) (*models.Product, error)
 }
 
 type productUseCase struct {
@@ -29,16 +43,77 @@ func NewProductUseCase(productRepo repositories.ProductRepository) ProductUseCas
 	}
 }
 
-// GetAllProducts mengambil semua produk dari database
-func (uc *productUseCase) GetAllProducts(name string) ([]models.Product, error) {
-	pkg.Log.WithFields(logrus.Fields{
+const (
+	defaultProductPerPage = 20
+	maxProductPerPage     = 100
+
+	// batas matriks varian supaya satu produk tidak meledak menjadi ribuan SKU
+	maxProductOptions    = 3
+	maxProductVariants   = 100
+	maxOptionValueLength = 50
+
+	maxUnitNameLength = 20
+	maxUnitFactor     = 100000
+)
+
+// GetAllProducts mengambil produk dengan filter, sorting dan pagination
+func (uc *productUseCase) GetAllProducts(ctx context.Context, filter models.ProductFilter) (*models.ProductList, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 		"usecase": "product",
 		"action":  "get_all_products",
 	}).Info("Executing get all products use case")
 
-	products, err := uc.productRepo.GetAllProduct(name)
+	if filter.PerPage <= 0 {
+		filter.PerPage = defaultProductPerPage
+	}
+	if filter.PerPage > maxProductPerPage {
+		filter.PerPage = maxProductPerPage
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+
+	switch strings.TrimPrefix(filter.Sort, "-") {
+	case "", "name", "price", "stock":
+	default:
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "product",
+			"action":  "get_all_products",
+			"sort":    filter.Sort,
+		}).Warn("Invalid sort field")
+		return nil, models.NewValidationError("sort", "sort must be one of name, price, stock")
+	}
+
+	if (filter.MinPrice != nil && *filter.MinPrice < 0) || (filter.MaxPrice != nil && *filter.MaxPrice < 0) {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "product",
+			"action":  "get_all_products",
+		}).Warn("Price filter cannot be negative")
+		return nil, models.NewValidationError("min_price", "price filter cannot be negative")
+	}
+
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":   "product",
+			"action":    "get_all_products",
+			"min_price": *filter.MinPrice,
+			"max_price": *filter.MaxPrice,
+		}).Warn("Invalid price range")
+		return nil, models.NewValidationError("min_price", "min_price cannot be greater than max_price")
+	}
+
+	if filter.Cursor != nil && filter.Cursor.Sort != filter.Sort {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "product",
+			"action":  "get_all_products",
+			"sort":    filter.Sort,
+		}).Warn("Cursor does not match sort")
+		return nil, models.NewValidationError("cursor", "cursor does not match sort parameter")
+	}
+
+	products, total, err := uc.productRepo.GetAllProduct(ctx, filter)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 			"usecase": "product",
 			"action":  "get_all_products",
 			"error":   err.Error(),
@@ -46,35 +121,65 @@ func (uc *productUseCase) GetAllProducts(name string) ([]models.Product, error)
 		return nil, err
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	meta := models.PaginationMeta{
+		PerPage:    filter.PerPage,
+		Total:      total,
+		TotalPages: (total + filter.PerPage - 1) / filter.PerPage,
+	}
+	if filter.Cursor == nil {
+		meta.Page = filter.Page
+	}
+
+	// Repository mengambil satu baris ekstra untuk mengetahui apakah masih ada data
+	if len(products) > filter.PerPage {
+		products = products[:filter.PerPage]
+		meta.NextCursor = productCursor(filter.Sort, products[len(products)-1]).Encode()
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 		"usecase": "product",
 		"action":  "get_all_products",
 		"count":   len(products),
+		"total":   total,
 	}).Info("Successfully retrieved all products")
 
-	return products, nil
+	return &models.ProductList{Products: products, Meta: meta}, nil
+}
+
+// productCursor membuat cursor dari produk terakhir sesuai kolom sort
+func productCursor(sort string, last models.Product) models.ProductCursor {
+	cursor := models.ProductCursor{Sort: sort, ID: last.ID}
+	switch strings.TrimPrefix(sort, "-") {
+	case "name":
+		cursor.Value = last.Name
+	case "price":
+		cursor.Value = strconv.Itoa(last.Price)
+	case "stock":
+		cursor.Value = last.Stock.String()
+	}
+	return cursor
 }
 
 // GetProductByID mengambil produk berdasarkan ID
-func (uc *productUseCase) GetProductByID(id int) (*models.Product, error) {
-	pkg.Log.WithFields(logrus.Fields{
+func (uc *productUseCase) GetProductByID(ctx context.Context, id int) (*models.Product, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 		"usecase":    "product",
 		"action":     "get_product_by_id",
 		"product_id": id,
 	}).Info("Executing get product by ID use case")
 
 	if id <= 0 {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 			"usecase":    "product",
 			"action":     "get_product_by_id",
 			"product_id": id,
 		}).Warn("Invalid product ID")
-		return nil, errors.New("invalid product ID")
+		return nil, models.NewValidationError("id", "invalid product ID")
 	}
 
-	product, err := uc.productRepo.GetProductByID(id)
+	product, err := uc.productRepo.GetProductByID(ctx, id)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 			"usecase":    "product",
 			"action":     "get_product_by_id",
 			"product_id": id,
@@ -83,7 +188,7 @@ func (uc *productUseCase) GetProductByID(id int) (*models.Product, error) {
 		return nil, err
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 		"usecase":      "product",
 		"action":       "get_product_by_id",
 		"product_id":   id,
@@ -94,8 +199,8 @@ func (uc *productUseCase) GetProductByID(id int) (*models.Product, error) {
 }
 
 // CreateProduct membuat produk baru dengan validasi
-func (uc *productUseCase) CreateProduct(product *models.Product) error {
-	pkg.Log.WithFields(logrus.Fields{
+func (uc *productUseCase) CreateProduct(ctx context.Context, product *models.Product) error {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 		"usecase":      "product",
 		"action":       "create_product",
 		"product_name": product.Name,
@@ -103,43 +208,88 @@ func (uc *productUseCase) CreateProduct(product *models.Product) error {
 
 	// Validasi business rules
 	if product.Name == "" {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 			"usecase": "product",
 			"action":  "create_product",
 		}).Warn("Product name is required")
-		return errors.New("product name is required")
+		return models.NewValidationError("name", "product name is required")
 	}
 
 	if product.Price < 0 {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 			"usecase": "product",
 			"action":  "create_product",
 			"price":   product.Price,
 		}).Warn("Product price cannot be negative")
-		return errors.New("product price cannot be negative")
+		return models.NewValidationError("price", "product price cannot be negative")
 	}
 
 	if product.Stock < 0 {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 			"usecase": "product",
 			"action":  "create_product",
 			"stock":   product.Stock,
 		}).Warn("Product stock cannot be negative")
-		return errors.New("product stock cannot be negative")
+		return models.NewValidationError("stock", "product stock cannot be negative")
+	}
+
+	if !product.Weighable && !product.Stock.IsWhole() {
+		return models.NewValidationError("stock", "stock of a product that is not weighable must be a whole number")
 	}
 
 	if product.CategoryID <= 0 {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 			"usecase":     "product",
 			"action":      "create_product",
 			"category_id": product.CategoryID,
 		}).Warn("Product category ID is required")
-		return errors.New("product category ID is required")
+		return models.NewValidationError("category_id", "product category ID is required")
+	}
+
+	if product.TaxRateBps != nil && (*product.TaxRateBps < 0 || *product.TaxRateBps > pricing.BasisPoints) {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":      "product",
+			"action":       "create_product",
+			"tax_rate_bps": *product.TaxRateBps,
+		}).Warn("Invalid product tax rate")
+		return models.NewValidationError("tax_rate_bps", "tax rate must be between 0 and 10000 basis points")
+	}
+
+	if err := normalizeProductCodes(product); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "product",
+			"action":  "create_product",
+			"error":   err.Error(),
+		}).Warn("Invalid product SKU or barcode")
+		return err
+	}
+
+	units, err := normalizeProductUnits(product, product.Units)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "product",
+			"action":  "create_product",
+			"error":   err.Error(),
+		}).Warn("Invalid product units")
+		return err
 	}
+	product.Units = units
 
-	err := uc.productRepo.CreateProduct(product)
+	if product.CostingMethod == "" {
+		product.CostingMethod = models.CostingAverage
+	}
+	if err := validateProductCosting(product); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "product",
+			"action":  "create_product",
+			"error":   err.Error(),
+		}).Warn("Invalid product costing")
+		return err
+	}
+
+	err = uc.productRepo.CreateProduct(ctx, product)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 			"usecase":      "product",
 			"action":       "create_product",
 			"product_name": product.Name,
@@ -148,19 +298,22 @@ func (uc *productUseCase) CreateProduct(product *models.Product) error {
 		return err
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 		"usecase":      "product",
 		"action":       "create_product",
 		"product_id":   product.ID,
 		"product_name": product.Name,
 	}).Info("Successfully created product")
 
+	pkg.ProductsCreatedTotal.Inc()
+
 	return nil
 }
 
-// UpdateProduct mengupdate produk dengan validasi
-func (uc *productUseCase) UpdateProduct(product *models.Product) error {
-	pkg.Log.WithFields(logrus.Fields{
+// UpdateProduct mengupdate produk dengan validasi. Field stock diabaikan,
+// perubahan stok dicatat lewat StockUseCase.AdjustStock.
+func (uc *productUseCase) UpdateProduct(ctx context.Context, product *models.Product) error {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 		"usecase":      "product",
 		"action":       "update_product",
 		"product_id":   product.ID,
@@ -169,66 +322,116 @@ func (uc *productUseCase) UpdateProduct(product *models.Product) error {
 
 	// Validasi business rules
 	if product.ID <= 0 {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 			"usecase":    "product",
 			"action":     "update_product",
 			"product_id": product.ID,
 		}).Warn("Invalid product ID")
-		return errors.New("invalid product ID")
+		return models.NewValidationError("id", "invalid product ID")
 	}
 
 	if product.Name == "" {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 			"usecase":    "product",
 			"action":     "update_product",
 			"product_id": product.ID,
 		}).Warn("Product name is required")
-		return errors.New("product name is required")
+		return models.NewValidationError("name", "product name is required")
 	}
 
 	if product.Price < 0 {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 			"usecase":    "product",
 			"action":     "update_product",
 			"product_id": product.ID,
 			"price":      product.Price,
 		}).Warn("Product price cannot be negative")
-		return errors.New("product price cannot be negative")
-	}
-
-	if product.Stock < 0 {
-		pkg.Log.WithFields(logrus.Fields{
-			"usecase":    "product",
-			"action":     "update_product",
-			"product_id": product.ID,
-			"stock":      product.Stock,
-		}).Warn("Product stock cannot be negative")
-		return errors.New("product stock cannot be negative")
+		return models.NewValidationError("price", "product price cannot be negative")
 	}
 
 	if product.CategoryID <= 0 {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 			"usecase":     "product",
 			"action":      "update_product",
 			"product_id":  product.ID,
 			"category_id": product.CategoryID,
 		}).Warn("Product category ID is required")
-		return errors.New("product category ID is required")
+		return models.NewValidationError("category_id", "product category ID is required")
+	}
+
+	if product.TaxRateBps != nil && (*product.TaxRateBps < 0 || *product.TaxRateBps > pricing.BasisPoints) {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":      "product",
+			"action":       "update_product",
+			"tax_rate_bps": *product.TaxRateBps,
+		}).Warn("Invalid product tax rate")
+		return models.NewValidationError("tax_rate_bps", "tax rate must be between 0 and 10000 basis points")
+	}
+
+	if err := normalizeProductCodes(product); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "product",
+			"action":  "update_product",
+			"error":   err.Error(),
+		}).Warn("Invalid product SKU or barcode")
+		return err
 	}
 
 	// Cek apakah produk ada
-	existingProduct, err := uc.productRepo.GetProductByID(product.ID)
+	existingProduct, err := uc.productRepo.GetProductByID(ctx, product.ID)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 			"usecase":    "product",
 			"action":     "update_product",
 			"product_id": product.ID,
 			"error":      err.Error(),
 		}).Error("Product not found")
-		return errors.New("product not found")
+		return err
+	}
+
+	// Stok yang masih pecahan tidak bisa diubah menjadi barang per unit
+	if !product.Weighable && existingProduct.Weighable && !existingProduct.Stock.IsWhole() {
+		return models.NewConflictError(fmt.Sprintf("stock %s is fractional, adjust it to a whole number before turning off weighable", existingProduct.Stock))
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	// base_unit kosong berarti tidak diubah; satuan lama tetap divalidasi
+	// terhadap base_unit baru jika units tidak dikirim
+	if strings.TrimSpace(product.BaseUnit) == "" {
+		product.BaseUnit = existingProduct.BaseUnit
+	}
+	units := product.Units
+	if units == nil {
+		units = existingProduct.Units
+	}
+	units, err = normalizeProductUnits(product, units)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":    "product",
+			"action":     "update_product",
+			"product_id": product.ID,
+			"error":      err.Error(),
+		}).Warn("Invalid product units")
+		return err
+	}
+	if product.Units != nil {
+		product.Units = units
+	}
+
+	// costing_method kosong berarti tidak diubah
+	if product.CostingMethod == "" {
+		product.CostingMethod = existingProduct.CostingMethod
+	}
+	if err := validateProductCosting(product); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":    "product",
+			"action":     "update_product",
+			"product_id": product.ID,
+			"error":      err.Error(),
+		}).Warn("Invalid product costing")
+		return err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 		"usecase":    "product",
 		"action":     "update_product",
 		"product_id": product.ID,
@@ -236,9 +439,9 @@ func (uc *productUseCase) UpdateProduct(product *models.Product) error {
 		"new_name":   product.Name,
 	}).Info("Updating product")
 
-	err = uc.productRepo.UpdateProduct(product)
+	err = uc.productRepo.UpdateProduct(ctx, product)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 			"usecase":      "product",
 			"action":       "update_product",
 			"product_id":   product.ID,
@@ -248,7 +451,7 @@ func (uc *productUseCase) UpdateProduct(product *models.Product) error {
 		return err
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 		"usecase":      "product",
 		"action":       "update_product",
 		"product_id":   product.ID,
@@ -259,44 +462,44 @@ func (uc *productUseCase) UpdateProduct(product *models.Product) error {
 }
 
 // DeleteProduct menghapus produk berdasarkan ID
-func (uc *productUseCase) DeleteProduct(id int) error {
-	pkg.Log.WithFields(logrus.Fields{
+func (uc *productUseCase) DeleteProduct(ctx context.Context, id int) error {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 		"usecase":    "product",
 		"action":     "delete_product",
 		"product_id": id,
 	}).Info("Executing delete product use case")
 
 	if id <= 0 {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 			"usecase":    "product",
 			"action":     "delete_product",
 			"product_id": id,
 		}).Warn("Invalid product ID")
-		return errors.New("invalid product ID")
+		return models.NewValidationError("id", "invalid product ID")
 	}
 
 	// Cek apakah produk ada sebelum dihapus
-	product, err := uc.productRepo.GetProductByID(id)
+	product, err := uc.productRepo.GetProductByID(ctx, id)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 			"usecase":    "product",
 			"action":     "delete_product",
 			"product_id": id,
 			"error":      err.Error(),
 		}).Error("Product not found")
-		return errors.New("product not found")
+		return err
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 		"usecase":      "product",
 		"action":       "delete_product",
 		"product_id":   id,
 		"product_name": product.Name,
 	}).Info("Deleting product")
 
-	err = uc.productRepo.DeleteProduct(id)
+	err = uc.productRepo.DeleteProduct(ctx, id)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 			"usecase":    "product",
 			"action":     "delete_product",
 			"product_id": id,
@@ -305,7 +508,7 @@ func (uc *productUseCase) DeleteProduct(id int) error {
 		return err
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
 		"usecase":      "product",
 		"action":       "delete_product",
 		"product_id":   id,
@@ -314,3 +517,397 @@ func (uc *productUseCase) DeleteProduct(id int) error {
 
 	return nil
 }
+
+// validateProductCosting memastikan harga pokok tidak negatif dan costing
+// method dikenal
+func validateProductCosting(product *models.Product) error {
+	if product.CostPrice != nil && *product.CostPrice < 0 {
+		return models.NewValidationError("cost_price", "cost price cannot be negative")
+	}
+	switch product.CostingMethod {
+	case models.CostingAverage, models.CostingFIFO:
+	default:
+		return models.NewValidationError("costing_method", "costing_method must be one of average, fifo")
+	}
+	return nil
+}
+
+// normalizeProductCodes merapikan SKU dan PLU timbangan lalu memvalidasi
+// serta menormalisasi barcode produk. Barcode ganda di request yang sama
+// digabung.
+func normalizeProductCodes(product *models.Product) error {
+	product.SKU = strings.TrimSpace(product.SKU)
+	if len(product.SKU) > 64 {
+		return models.NewValidationError("sku", "sku must be at most 64 characters")
+	}
+
+	product.PLU = strings.TrimSpace(product.PLU)
+	if product.PLU != "" && !barcode.ValidPLU(product.PLU) {
+		return models.NewValidationError("plu", "plu must be exactly 5 digits")
+	}
+
+	if product.Barcodes == nil {
+		return nil
+	}
+	seen := make(map[string]bool, len(product.Barcodes))
+	codes := make([]string, 0, len(product.Barcodes))
+	for _, raw := range product.Barcodes {
+		code, err := barcode.Normalize(strings.TrimSpace(raw))
+		if err != nil {
+			return models.NewValidationError("barcodes", fmt.Sprintf("%s: %s", raw, err.Error()))
+		}
+		if !seen[code] {
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+	product.Barcodes = codes
+	return nil
+}
+
+func (uc *productUseCase) GetProductByBarcode(ctx context.Context, code string) (*models.Product, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "product",
+		"action":  "get_product_by_barcode",
+		"code":    code,
+	}).Info("Executing get product by barcode use case")
+
+	normalized, err := barcode.Normalize(strings.TrimSpace(code))
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "product",
+			"action":  "get_product_by_barcode",
+			"code":    code,
+			"error":   err.Error(),
+		}).Warn("Invalid barcode")
+		return nil, models.NewValidationError("code", err.Error())
+	}
+
+	product, err := uc.productRepo.GetProductByBarcode(ctx, normalized)
+	// barcode yang tidak terdaftar tetapi berprefix 20-29 dibaca sebagai
+	// label timbangan
+	if label, ok := barcode.ParseScale(normalized); ok && errors.Is(err, models.ErrNotFound) {
+		product, err = uc.getProductByScaleLabel(ctx, label)
+	}
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "product",
+			"action":  "get_product_by_barcode",
+			"code":    normalized,
+			"error":   err.Error(),
+		}).Warn("Failed to get product by barcode")
+		return nil, err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":    "product",
+		"action":     "get_product_by_barcode",
+		"code":       normalized,
+		"product_id": product.ID,
+	}).Info("Successfully retrieved product by barcode")
+
+	return product, nil
+}
+
+func (uc *productUseCase) GenerateBarcode(ctx context.Context, id int) (*models.Product, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":    "product",
+		"action":     "generate_barcode",
+		"product_id": id,
+	}).Info("Executing generate barcode use case")
+
+	if id <= 0 {
+		return nil, models.NewValidationError("id", "invalid product ID")
+	}
+
+	code, err := uc.productRepo.AddInternalBarcode(ctx, id)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":    "product",
+			"action":     "generate_barcode",
+			"product_id": id,
+			"error":      err.Error(),
+		}).Error("Failed to generate barcode")
+		return nil, err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":    "product",
+		"action":     "generate_barcode",
+		"product_id": id,
+		"code":       code,
+	}).Info("Successfully generated barcode")
+
+	return uc.productRepo.GetProductByID(ctx, id)
+}
+
+func (uc *productUseCase) SetProductOptions(ctx context.Context, id int, req models.SetProductOptionsRequest) (*models.Product, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":    "product",
+		"action":     "set_product_options",
+		"product_id": id,
+		"options":    len(req.Options),
+	}).Info("Executing set product options use case")
+
+	if id <= 0 {
+		return nil, models.NewValidationError("id", "invalid product ID")
+	}
+
+	options, err := normalizeProductOptions(req.Options)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":    "product",
+			"action":     "set_product_options",
+			"product_id": id,
+			"error":      err.Error(),
+		}).Warn("Invalid product options")
+		return nil, err
+	}
+
+	count := 1
+	for _, option := range options {
+		count *= len(option.Values)
+	}
+	if count > maxProductVariants {
+		return nil, models.NewValidationError("options", fmt.Sprintf("options produce %d variants, maximum is %d", count, maxProductVariants))
+	}
+	combinations := variantCombinations(options)
+
+	if err := uc.productRepo.SetProductOptions(ctx, id, options, combinations); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":    "product",
+			"action":     "set_product_options",
+			"product_id": id,
+			"error":      err.Error(),
+		}).Error("Failed to set product options")
+		return nil, err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":    "product",
+		"action":     "set_product_options",
+		"product_id": id,
+		"variants":   len(combinations),
+	}).Info("Successfully set product options")
+
+	return uc.productRepo.GetProductByID(ctx, id)
+}
+
+func (uc *productUseCase) UpdateVariant(ctx context.Context, productID, variantID int, req models.UpdateVariantRequest) (*models.Product, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":    "product",
+		"action":     "update_variant",
+		"product_id": productID,
+		"variant_id": variantID,
+	}).Info("Executing update variant use case")
+
+	if productID <= 0 {
+		return nil, models.NewValidationError("id", "invalid product ID")
+	}
+	if variantID <= 0 {
+		return nil, models.NewValidationError("variant_id", "invalid variant ID")
+	}
+	if req.Price != nil && *req.Price < 0 {
+		return nil, models.NewValidationError("price", "variant price cannot be negative")
+	}
+
+	product, err := uc.productRepo.GetProductByID(ctx, productID)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":    "product",
+			"action":     "update_variant",
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error("Product not found")
+		return nil, err
+	}
+
+	var variant *models.ProductVariant
+	for i := range product.Variants {
+		if product.Variants[i].ID == variantID {
+			variant = &product.Variants[i]
+			break
+		}
+	}
+	if variant == nil {
+		return nil, models.NewNotFoundError("variant")
+	}
+
+	if req.SKU != nil {
+		variant.SKU = *req.SKU
+	}
+	if req.Price != nil {
+		variant.Price = *req.Price
+	}
+	if req.Active != nil {
+		variant.Active = *req.Active
+	}
+	// Barcodes nil berarti barcode varian tidak diubah
+	variant.Barcodes = req.Barcodes
+
+	// validasi SKU dan barcode sama dengan milik produk
+	codes := models.Product{SKU: variant.SKU, Barcodes: variant.Barcodes}
+	if err := normalizeProductCodes(&codes); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":    "product",
+			"action":     "update_variant",
+			"variant_id": variantID,
+			"error":      err.Error(),
+		}).Warn("Invalid variant SKU or barcode")
+		return nil, err
+	}
+	variant.SKU, variant.Barcodes = codes.SKU, codes.Barcodes
+
+	if err := uc.productRepo.UpdateVariant(ctx, variant); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":    "product",
+			"action":     "update_variant",
+			"product_id": productID,
+			"variant_id": variantID,
+			"error":      err.Error(),
+		}).Error("Failed to update variant")
+		return nil, err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":    "product",
+		"action":     "update_variant",
+		"product_id": productID,
+		"variant_id": variantID,
+	}).Info("Successfully updated variant")
+
+	return uc.productRepo.GetProductByID(ctx, productID)
+}
+
+// normalizeProductOptions merapikan nama dan nilai opsi lalu memvalidasi
+// bahwa tidak ada nama atau nilai yang ganda
+func normalizeProductOptions(raw []models.ProductOption) ([]models.ProductOption, error) {
+	if len(raw) == 0 {
+		return nil, models.NewValidationError("options", "at least one option is required")
+	}
+	if len(raw) > maxProductOptions {
+		return nil, models.NewValidationError("options", fmt.Sprintf("at most %d options are allowed", maxProductOptions))
+	}
+
+	options := make([]models.ProductOption, 0, len(raw))
+	names := make(map[string]bool, len(raw))
+	for _, option := range raw {
+		name := strings.TrimSpace(option.Name)
+		if name == "" || len(name) > maxOptionValueLength {
+			return nil, models.NewValidationError("options", fmt.Sprintf("option name must be 1-%d characters", maxOptionValueLength))
+		}
+		if names[strings.ToLower(name)] {
+			return nil, models.NewValidationError("options", fmt.Sprintf("duplicate option %q", name))
+		}
+		names[strings.ToLower(name)] = true
+
+		if len(option.Values) == 0 {
+			return nil, models.NewValidationError("options", fmt.Sprintf("option %q needs at least one value", name))
+		}
+		values := make([]string, 0, len(option.Values))
+		seen := make(map[string]bool, len(option.Values))
+		for _, v := range option.Values {
+			value := strings.TrimSpace(v)
+			if value == "" || len(value) > maxOptionValueLength {
+				return nil, models.NewValidationError("options", fmt.Sprintf("values of option %q must be 1-%d characters", name, maxOptionValueLength))
+			}
+			if seen[strings.ToLower(value)] {
+				return nil, models.NewValidationError("options", fmt.Sprintf("duplicate value %q in option %q", value, name))
+			}
+			seen[strings.ToLower(value)] = true
+			values = append(values, value)
+		}
+		options = append(options, models.ProductOption{Name: name, Values: values})
+	}
+	return options, nil
+}
+
+// variantCombinations membentuk hasil kali kartesius nilai opsi, mis.
+// [S, M] x [Merah, Biru] -> [S Merah] [S Biru] [M Merah] [M Biru]
+func variantCombinations(options []models.ProductOption) [][]string {
+	combinations := [][]string{{}}
+	for _, option := range options {
+		next := make([][]string, 0, len(combinations)*len(option.Values))
+		for _, prefix := range combinations {
+			for _, value := range option.Values {
+				combination := make([]string, len(prefix), len(prefix)+1)
+				copy(combination, prefix)
+				next = append(next, append(combination, value))
+			}
+		}
+		combinations = next
+	}
+	return combinations
+}
+
+// normalizeProductUnits merapikan base unit produk (default pcs) dan
+// memvalidasi satuan tambahan: nama unik dan berbeda dari base unit, factor
+// lebih dari 1. Hasilnya diurutkan dari factor terkecil.
+func normalizeProductUnits(product *models.Product, raw []models.ProductUnit) ([]models.ProductUnit, error) {
+	product.BaseUnit = strings.TrimSpace(product.BaseUnit)
+	if product.BaseUnit == "" {
+		product.BaseUnit = models.DefaultBaseUnit
+	}
+	if len(product.BaseUnit) > maxUnitNameLength {
+		return nil, models.NewValidationError("base_unit", fmt.Sprintf("base_unit must be at most %d characters", maxUnitNameLength))
+	}
+
+	units := make([]models.ProductUnit, 0, len(raw))
+	seen := map[string]bool{strings.ToLower(product.BaseUnit): true}
+	for _, unit := range raw {
+		name := strings.TrimSpace(unit.Name)
+		if name == "" || len(name) > maxUnitNameLength {
+			return nil, models.NewValidationError("units", fmt.Sprintf("unit name must be 1-%d characters", maxUnitNameLength))
+		}
+		if seen[strings.ToLower(name)] {
+			return nil, models.NewValidationError("units", fmt.Sprintf("duplicate unit %q", name))
+		}
+		seen[strings.ToLower(name)] = true
+		if unit.Factor <= 1 || unit.Factor > maxUnitFactor {
+			return nil, models.NewValidationError("units", fmt.Sprintf("factor of %s must be between 2 and %d %s", name, maxUnitFactor, product.BaseUnit))
+		}
+		units = append(units, models.ProductUnit{Name: name, Factor: unit.Factor})
+	}
+	sort.Slice(units, func(i, j int) bool {
+		return units[i].Factor < units[j].Factor
+	})
+	return units, nil
+}
+
+// getProductByScaleLabel mencari produk dari PLU label timbangan dan mengisi
+// ScannedQuantity. Label harga dikonversi menjadi quantity dengan membagi
+// harga label dengan harga per unit, sehingga total baris bisa berbeda
+// beberapa rupiah dari label karena pembulatan ke 3 desimal.
+func (uc *productUseCase) getProductByScaleLabel(ctx context.Context, label barcode.ScaleLabel) (*models.Product, error) {
+	product, err := uc.productRepo.GetProductByPLU(ctx, label.PLU)
+	if err != nil {
+		return nil, err
+	}
+	if !product.Weighable {
+		return nil, models.NewValidationError("code", fmt.Sprintf("%s is not weighable, scale labels are not accepted", product.Name))
+	}
+
+	quantity := models.Quantity(label.Value)
+	if label.IsPrice {
+		if product.Price <= 0 {
+			return nil, models.NewValidationError("code", fmt.Sprintf("%s has no unit price to derive quantity from", product.Name))
+		}
+		quantity = models.Quantity((int64(label.Value)*models.QuantityScale + int64(product.Price)/2) / int64(product.Price))
+	}
+	if quantity <= 0 {
+		return nil, models.NewValidationError("code", "scale label has zero quantity")
+	}
+	product.ScannedQuantity = &quantity
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":    "product",
+		"action":     "get_product_by_scale_label",
+		"plu":        label.PLU,
+		"is_price":   label.IsPrice,
+		"value":      label.Value,
+		"product_id": product.ID,
+		"quantity":   quantity,
+	}).Info("Resolved scale label")
+
+	return product, nil
+}
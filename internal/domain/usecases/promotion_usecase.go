@@ -0,0 +1,257 @@
+package usecases
+
+import (
+	"context"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/domain/pricing"
+	"kasir-api/internal/domain/repositories"
+	"kasir-api/internal/pkg"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+type PromotionUseCase interface {
+	GetAllPromotions(ctx context.Context) ([]models.Promotion, error)
+	GetPromotionByID(ctx context.Context, id int) (*models.Promotion, error)
+	CreatePromotion(ctx context.Context, promotion *models.Promotion) error
+	UpdatePromotion(ctx context.Context, promotion *models.Promotion) error
+	DeletePromotion(ctx context.Context, id int) error
+}
+
+type promotionUseCase struct {
+	promotionRepo repositories.PromotionRepository
+}
+
+func NewPromotionUseCase(promotionRepo repositories.PromotionRepository) PromotionUseCase {
+	return &promotionUseCase{
+		promotionRepo: promotionRepo,
+	}
+}
+
+func (uc *promotionUseCase) GetAllPromotions(ctx context.Context) ([]models.Promotion, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "promotion",
+		"action":  "get_all_promotions",
+	}).Info("Executing get all promotions use case")
+
+	promotions, err := uc.promotionRepo.GetAllPromotions(ctx)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "promotion",
+			"action":  "get_all_promotions",
+			"error":   err.Error(),
+		}).Error("Failed to get all promotions")
+		return nil, err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "promotion",
+		"action":  "get_all_promotions",
+		"count":   len(promotions),
+	}).Info("Successfully retrieved all promotions")
+
+	return promotions, nil
+}
+
+func (uc *promotionUseCase) GetPromotionByID(ctx context.Context, id int) (*models.Promotion, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "promotion",
+		"action":  "get_promotion_by_id",
+		"id":      id,
+	}).Info("Executing get promotion by ID use case")
+
+	promotion, err := uc.promotionRepo.GetPromotionByID(ctx, id)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "promotion",
+			"action":  "get_promotion_by_id",
+			"id":      id,
+			"error":   err.Error(),
+		}).Error("Failed to get promotion by ID")
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+func (uc *promotionUseCase) CreatePromotion(ctx context.Context, promotion *models.Promotion) error {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "promotion",
+		"action":  "create_promotion",
+	}).Info("Executing create promotion use case")
+
+	if err := normalizePromotion(promotion); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "promotion",
+			"action":  "create_promotion",
+			"error":   err.Error(),
+		}).Warn("Invalid promotion")
+		return err
+	}
+
+	if err := uc.promotionRepo.CreatePromotion(ctx, promotion); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "promotion",
+			"action":  "create_promotion",
+			"error":   err.Error(),
+		}).Error("Failed to create promotion")
+		return err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":      "promotion",
+		"action":       "create_promotion",
+		"promotion_id": promotion.ID,
+		"type":         promotion.Type,
+		"scope":        promotion.Scope,
+	}).Info("Successfully created promotion")
+
+	return nil
+}
+
+func (uc *promotionUseCase) UpdatePromotion(ctx context.Context, promotion *models.Promotion) error {
+	if promotion.ID <= 0 {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "promotion",
+			"action":  "update_promotion",
+			"id":      promotion.ID,
+		}).Warn("Invalid promotion ID")
+		return models.NewValidationError("id", "invalid promotion ID")
+	}
+
+	if err := normalizePromotion(promotion); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "promotion",
+			"action":  "update_promotion",
+			"id":      promotion.ID,
+			"error":   err.Error(),
+		}).Warn("Invalid promotion")
+		return err
+	}
+
+	if err := uc.promotionRepo.UpdatePromotion(ctx, promotion); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "promotion",
+			"action":  "update_promotion",
+			"id":      promotion.ID,
+			"error":   err.Error(),
+		}).Error("Failed to update promotion")
+		return err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "promotion",
+		"action":  "update_promotion",
+		"id":      promotion.ID,
+		"active":  promotion.Active,
+	}).Info("Successfully updated promotion")
+
+	return nil
+}
+
+func (uc *promotionUseCase) DeletePromotion(ctx context.Context, id int) error {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "promotion",
+		"action":  "delete_promotion",
+		"id":      id,
+	}).Info("Executing delete promotion use case")
+
+	if id <= 0 {
+		return models.NewValidationError("id", "invalid promotion ID")
+	}
+
+	if err := uc.promotionRepo.DeletePromotion(ctx, id); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "promotion",
+			"action":  "delete_promotion",
+			"id":      id,
+			"error":   err.Error(),
+		}).Error("Failed to delete promotion")
+		return err
+	}
+
+	return nil
+}
+
+// normalizePromotion merapikan input (trim nama, kode huruf besar) lalu
+// memvalidasi kombinasi tipe dan scope promo
+func normalizePromotion(p *models.Promotion) error {
+	p.Name = strings.TrimSpace(p.Name)
+	p.Code = strings.ToUpper(strings.TrimSpace(p.Code))
+	p.DailyStartTime = strings.TrimSpace(p.DailyStartTime)
+	p.DailyEndTime = strings.TrimSpace(p.DailyEndTime)
+
+	if p.Name == "" {
+		return models.NewValidationError("name", "promotion name is required")
+	}
+
+	switch p.Type {
+	case models.PromotionTypePercentage:
+		if p.Value < 1 || p.Value > 100 {
+			return models.NewValidationError("value", "percentage must be between 1 and 100")
+		}
+	case models.PromotionTypeFixed:
+		if p.Value <= 0 {
+			return models.NewValidationError("value", "fixed discount must be greater than zero")
+		}
+	case models.PromotionTypeBuyXGetY:
+		if p.BuyQuantity < 1 || p.GetQuantity < 1 {
+			return models.NewValidationError("buy_quantity", "buy_quantity and get_quantity must be at least 1")
+		}
+	default:
+		return models.NewValidationError("type", "type must be percentage, fixed or buy_x_get_y")
+	}
+	if p.Type != models.PromotionTypeBuyXGetY {
+		p.BuyQuantity, p.GetQuantity = 0, 0
+	}
+
+	switch p.Scope {
+	case models.PromotionScopeItem:
+		if p.ProductID == nil || *p.ProductID <= 0 {
+			return models.NewValidationError("product_id", "product_id is required for item promotions")
+		}
+		p.CategoryID = nil
+	case models.PromotionScopeCategory:
+		if p.CategoryID == nil || *p.CategoryID <= 0 {
+			return models.NewValidationError("category_id", "category_id is required for category promotions")
+		}
+		p.ProductID = nil
+	case models.PromotionScopeCart:
+		if p.ProductID != nil || p.CategoryID != nil {
+			return models.NewValidationError("scope", "cart promotions cannot target a product or category")
+		}
+		if p.Type == models.PromotionTypeBuyXGetY {
+			return models.NewValidationError("type", "buy_x_get_y is only available for item or category promotions")
+		}
+	default:
+		return models.NewValidationError("scope", "scope must be item, category or cart")
+	}
+
+	if p.MinSpend < 0 {
+		return models.NewValidationError("min_spend", "min_spend cannot be negative")
+	}
+
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return models.NewValidationError("ends_at", "ends_at must be after starts_at")
+	}
+
+	if (p.DailyStartTime == "") != (p.DailyEndTime == "") {
+		return models.NewValidationError("daily_start_time", "daily_start_time and daily_end_time must be set together")
+	}
+	if p.DailyStartTime != "" {
+		start, ok := pricing.ParseClock(p.DailyStartTime)
+		if !ok {
+			return models.NewValidationError("daily_start_time", "daily_start_time must use HH:MM format")
+		}
+		end, ok := pricing.ParseClock(p.DailyEndTime)
+		if !ok {
+			return models.NewValidationError("daily_end_time", "daily_end_time must use HH:MM format")
+		}
+		if start == end {
+			return models.NewValidationError("daily_end_time", "daily_end_time must differ from daily_start_time")
+		}
+	}
+
+	return nil
+}
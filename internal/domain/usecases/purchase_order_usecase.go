@@ -0,0 +1,422 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/domain/repositories"
+	"kasir-api/internal/pkg"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultPurchaseOrdersPerPage = 20
+	maxPurchaseOrdersPerPage     = 100
+)
+
+type PurchaseOrderUseCase interface {
+	CreatePurchaseOrder(ctx context.Context, req *models.PurchaseOrderRequest) (*models.PurchaseOrder, error)
+	GetPurchaseOrderByID(ctx context.Context, id int) (*models.PurchaseOrder, error)
+	// GetPurchaseOrders mengembalikan daftar PO, SupplierID di filter dipakai
+	// untuk riwayat PO per supplier
+	GetPurchaseOrders(ctx context.Context, filter models.PurchaseOrderFilter) (*models.PurchaseOrderList, error)
+	UpdatePurchaseOrder(ctx context.Context, id int, req *models.PurchaseOrderRequest) (*models.PurchaseOrder, error)
+	SendPurchaseOrder(ctx context.Context, id int) (*models.PurchaseOrder, error)
+	CancelPurchaseOrder(ctx context.Context, id int) (*models.PurchaseOrder, error)
+	ReceiveGoods(ctx context.Context, id int, req *models.ReceiveGoodsRequest) (*models.PurchaseOrder, error)
+}
+
+type purchaseOrderUseCase struct {
+	purchaseOrderRepo repositories.PurchaseOrderRepository
+	supplierRepo      repositories.SupplierRepository
+	productRepo       repositories.ProductRepository
+}
+
+// NewPurchaseOrderUseCase membuat instance baru dari PurchaseOrderUseCase
+func NewPurchaseOrderUseCase(purchaseOrderRepo repositories.PurchaseOrderRepository, supplierRepo repositories.SupplierRepository,
+	productRepo repositories.ProductRepository) PurchaseOrderUseCase {
+	return &purchaseOrderUseCase{
+		purchaseOrderRepo: purchaseOrderRepo,
+		supplierRepo:      supplierRepo,
+		productRepo:       productRepo,
+	}
+}
+
+func (uc *purchaseOrderUseCase) CreatePurchaseOrder(ctx context.Context, req *models.PurchaseOrderRequest) (*models.PurchaseOrder, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":     "purchase_order",
+		"action":      "create_purchase_order",
+		"supplier_id": req.SupplierID,
+		"lines":       len(req.Lines),
+	}).Info("Executing create purchase order use case")
+
+	user, ok := pkg.AuthUserFromContext(ctx)
+	if !ok {
+		return nil, models.NewUnauthorizedError("authenticated user is required")
+	}
+
+	po, err := uc.buildPurchaseOrder(ctx, req)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "purchase_order",
+			"action":  "create_purchase_order",
+			"error":   err.Error(),
+		}).Warn("Invalid purchase order")
+		return nil, err
+	}
+	po.UserID = &user.ID
+	po.Status = models.PurchaseOrderStatusDraft
+
+	if err := uc.purchaseOrderRepo.CreatePurchaseOrder(ctx, po); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "purchase_order",
+			"action":  "create_purchase_order",
+			"error":   err.Error(),
+		}).Error("Failed to create purchase order")
+		return nil, err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":           "purchase_order",
+		"action":            "create_purchase_order",
+		"purchase_order_id": po.ID,
+		"total_amount":      po.TotalAmount,
+	}).Info("Successfully created purchase order")
+
+	return uc.purchaseOrderRepo.GetPurchaseOrderByID(ctx, po.ID)
+}
+
+func (uc *purchaseOrderUseCase) GetPurchaseOrderByID(ctx context.Context, id int) (*models.PurchaseOrder, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "purchase_order",
+		"action":  "get_purchase_order_by_id",
+		"id":      id,
+	}).Info("Executing get purchase order by ID use case")
+
+	return uc.purchaseOrderRepo.GetPurchaseOrderByID(ctx, id)
+}
+
+func (uc *purchaseOrderUseCase) GetPurchaseOrders(ctx context.Context, filter models.PurchaseOrderFilter) (*models.PurchaseOrderList, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":     "purchase_order",
+		"action":      "get_purchase_orders",
+		"supplier_id": filter.SupplierID,
+		"status":      filter.Status,
+	}).Info("Executing get purchase orders use case")
+
+	switch filter.Status {
+	case "", models.PurchaseOrderStatusDraft, models.PurchaseOrderStatusSent, models.PurchaseOrderStatusPartiallyReceived,
+		models.PurchaseOrderStatusReceived, models.PurchaseOrderStatusCancelled:
+	default:
+		return nil, models.NewValidationError("status", "status must be one of draft, sent, partially_received, received, cancelled")
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PerPage <= 0 {
+		filter.PerPage = defaultPurchaseOrdersPerPage
+	}
+	if filter.PerPage > maxPurchaseOrdersPerPage {
+		filter.PerPage = maxPurchaseOrdersPerPage
+	}
+
+	// Supplier yang tidak dikenal mendapat 404, bukan riwayat kosong
+	if filter.SupplierID != 0 {
+		if _, err := uc.supplierRepo.GetSupplierByID(ctx, filter.SupplierID); err != nil {
+			return nil, err
+		}
+	}
+
+	orders, total, err := uc.purchaseOrderRepo.GetPurchaseOrders(ctx, filter)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "purchase_order",
+			"action":  "get_purchase_orders",
+			"error":   err.Error(),
+		}).Error("Failed to get purchase orders")
+		return nil, err
+	}
+
+	return &models.PurchaseOrderList{
+		PurchaseOrders: orders,
+		Meta: models.PaginationMeta{
+			Page:       filter.Page,
+			PerPage:    filter.PerPage,
+			Total:      total,
+			TotalPages: (total + filter.PerPage - 1) / filter.PerPage,
+		},
+	}, nil
+}
+
+func (uc *purchaseOrderUseCase) UpdatePurchaseOrder(ctx context.Context, id int, req *models.PurchaseOrderRequest) (*models.PurchaseOrder, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "purchase_order",
+		"action":  "update_purchase_order",
+		"id":      id,
+		"lines":   len(req.Lines),
+	}).Info("Executing update purchase order use case")
+
+	po, err := uc.buildPurchaseOrder(ctx, req)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "purchase_order",
+			"action":  "update_purchase_order",
+			"id":      id,
+			"error":   err.Error(),
+		}).Warn("Invalid purchase order")
+		return nil, err
+	}
+	po.ID = id
+
+	if err := uc.purchaseOrderRepo.UpdatePurchaseOrder(ctx, po); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "purchase_order",
+			"action":  "update_purchase_order",
+			"id":      id,
+			"error":   err.Error(),
+		}).Error("Failed to update purchase order")
+		return nil, err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":      "purchase_order",
+		"action":       "update_purchase_order",
+		"id":           id,
+		"total_amount": po.TotalAmount,
+	}).Info("Successfully updated purchase order")
+
+	return uc.purchaseOrderRepo.GetPurchaseOrderByID(ctx, id)
+}
+
+// SendPurchaseOrder menandai PO draft sudah dikirim ke supplier. Setelah
+// dikirim PO tidak bisa diubah lagi dan barang boleh mulai diterima.
+func (uc *purchaseOrderUseCase) SendPurchaseOrder(ctx context.Context, id int) (*models.PurchaseOrder, error) {
+	return uc.setStatus(ctx, "send_purchase_order", id, models.PurchaseOrderStatusSent, models.PurchaseOrderStatusDraft)
+}
+
+// CancelPurchaseOrder membatalkan PO yang belum selesai diterima. Barang yang
+// sudah diterima tetap masuk stok.
+func (uc *purchaseOrderUseCase) CancelPurchaseOrder(ctx context.Context, id int) (*models.PurchaseOrder, error) {
+	return uc.setStatus(ctx, "cancel_purchase_order", id, models.PurchaseOrderStatusCancelled,
+		models.PurchaseOrderStatusDraft, models.PurchaseOrderStatusSent, models.PurchaseOrderStatusPartiallyReceived)
+}
+
+func (uc *purchaseOrderUseCase) setStatus(ctx context.Context, action string, id int, to string, from ...string) (*models.PurchaseOrder, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "purchase_order",
+		"action":  action,
+		"id":      id,
+	}).Info("Executing purchase order status use case")
+
+	if err := uc.purchaseOrderRepo.SetPurchaseOrderStatus(ctx, id, to, from...); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "purchase_order",
+			"action":  action,
+			"id":      id,
+			"error":   err.Error(),
+		}).Warn("Failed to change purchase order status")
+		return nil, err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "purchase_order",
+		"action":  action,
+		"id":      id,
+		"status":  to,
+	}).Info("Successfully changed purchase order status")
+
+	return uc.purchaseOrderRepo.GetPurchaseOrderByID(ctx, id)
+}
+
+// ReceiveGoods mencatat barang yang datang untuk PO yang sudah dikirim. Tiap
+// baris menambah stok lewat ledger dengan reason restock dan harga beli
+// baris sebagai harga pokok barang masuk.
+func (uc *purchaseOrderUseCase) ReceiveGoods(ctx context.Context, id int, req *models.ReceiveGoodsRequest) (*models.PurchaseOrder, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "purchase_order",
+		"action":  "receive_goods",
+		"id":      id,
+		"lines":   len(req.Lines),
+	}).Info("Executing receive goods use case")
+
+	user, ok := pkg.AuthUserFromContext(ctx)
+	if !ok {
+		return nil, models.NewUnauthorizedError("authenticated user is required")
+	}
+
+	note := strings.TrimSpace(req.Note)
+	if len(note) > 500 {
+		return nil, models.NewValidationError("note", "note must be at most 500 characters")
+	}
+
+	po, err := uc.purchaseOrderRepo.GetPurchaseOrderByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if po.Status != models.PurchaseOrderStatusSent && po.Status != models.PurchaseOrderStatusPartiallyReceived {
+		return nil, models.NewConflictError(fmt.Sprintf("purchase order is %s, only sent or partially received purchase orders can be received", po.Status))
+	}
+
+	lines, err := buildReceiptLines(po, req.Lines)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "purchase_order",
+			"action":  "receive_goods",
+			"id":      id,
+			"error":   err.Error(),
+		}).Warn("Invalid goods receipt")
+		return nil, err
+	}
+
+	receipt := &models.GoodsReceipt{
+		PurchaseOrderID: id,
+		UserID:          &user.ID,
+		Note:            note,
+		Lines:           lines,
+	}
+	if err := uc.purchaseOrderRepo.ReceiveGoods(ctx, receipt); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "purchase_order",
+			"action":  "receive_goods",
+			"id":      id,
+			"error":   err.Error(),
+		}).Error("Failed to receive goods")
+		return nil, err
+	}
+
+	pkg.StockMovementsTotal.WithLabelValues(models.StockReasonRestock).Add(float64(len(receipt.Lines)))
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":    "purchase_order",
+		"action":     "receive_goods",
+		"id":         id,
+		"receipt_id": receipt.ID,
+		"lines":      len(receipt.Lines),
+	}).Info("Successfully received goods")
+
+	return uc.purchaseOrderRepo.GetPurchaseOrderByID(ctx, id)
+}
+
+// buildReceiptLines mengubah request penerimaan menjadi baris goods receipt.
+// Tanpa baris berarti semua sisa yang belum diterima. Sisa tiap baris dicek
+// ulang oleh repository saat baris PO dikunci.
+func buildReceiptLines(po *models.PurchaseOrder, reqLines []models.ReceiveLineRequest) ([]models.GoodsReceiptLine, error) {
+	lines := make([]models.GoodsReceiptLine, 0, len(po.Lines))
+	if len(reqLines) == 0 {
+		for _, line := range po.Lines {
+			if remaining := line.Quantity - line.ReceivedQuantity; remaining > 0 {
+				lines = append(lines, models.GoodsReceiptLine{LineID: line.ID, Quantity: remaining, UnitCost: line.UnitCost})
+			}
+		}
+		if len(lines) == 0 {
+			return nil, models.NewConflictError("all lines of the purchase order have been received")
+		}
+		return lines, nil
+	}
+
+	poLines := make(map[int]models.PurchaseOrderLine, len(po.Lines))
+	for _, line := range po.Lines {
+		poLines[line.ID] = line
+	}
+	seen := make(map[int]bool, len(reqLines))
+	for _, req := range reqLines {
+		line, ok := poLines[req.LineID]
+		if !ok {
+			return nil, models.NewNotFoundError(fmt.Sprintf("line %d of purchase order %d", req.LineID, po.ID))
+		}
+		if seen[req.LineID] {
+			return nil, models.NewValidationError("lines", fmt.Sprintf("line %d is listed more than once", req.LineID))
+		}
+		seen[req.LineID] = true
+		if req.Quantity <= 0 {
+			return nil, models.NewValidationError("quantity", "quantity must be greater than zero")
+		}
+		if remaining := line.Quantity - line.ReceivedQuantity; req.Quantity > remaining {
+			return nil, models.NewConflictError(fmt.Sprintf("line %d only has %s left to receive, got %s", line.ID, remaining, req.Quantity))
+		}
+		unitCost := line.UnitCost
+		if req.UnitCost != nil {
+			if *req.UnitCost < 0 {
+				return nil, models.NewValidationError("unit_cost", "unit_cost cannot be negative")
+			}
+			unitCost = *req.UnitCost
+		}
+		lines = append(lines, models.GoodsReceiptLine{LineID: line.ID, Quantity: req.Quantity, UnitCost: unitCost})
+	}
+	return lines, nil
+}
+
+// buildPurchaseOrder memvalidasi supplier dan baris PO lalu menghitung total.
+// Satuan baris dicocokkan dengan satuan produk dan factor-nya disimpan di
+// baris supaya penerimaan barang tidak terpengaruh perubahan satuan produk.
+func (uc *purchaseOrderUseCase) buildPurchaseOrder(ctx context.Context, req *models.PurchaseOrderRequest) (*models.PurchaseOrder, error) {
+	if req.SupplierID <= 0 {
+		return nil, models.NewValidationError("supplier_id", "supplier_id is required")
+	}
+	supplier, err := uc.supplierRepo.GetSupplierByID(ctx, req.SupplierID)
+	if err != nil {
+		return nil, err
+	}
+
+	note := strings.TrimSpace(req.Note)
+	if len(note) > 500 {
+		return nil, models.NewValidationError("note", "note must be at most 500 characters")
+	}
+	if len(req.Lines) == 0 {
+		return nil, models.NewValidationError("lines", "purchase order must have at least one line")
+	}
+
+	po := &models.PurchaseOrder{SupplierID: supplier.ID, SupplierName: supplier.Name, Note: note}
+	seen := make(map[string]bool, len(req.Lines))
+	for _, reqLine := range req.Lines {
+		product, err := uc.productRepo.GetProductByID(ctx, reqLine.ProductID)
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				return nil, models.NewNotFoundError(fmt.Sprintf("product %d", reqLine.ProductID))
+			}
+			return nil, err
+		}
+		if _, err := resolveVariant(product, reqLine.VariantID); err != nil {
+			return nil, err
+		}
+		factor, err := product.UnitFactor(reqLine.Unit)
+		if err != nil {
+			return nil, err
+		}
+		unit := strings.TrimSpace(reqLine.Unit)
+		if unit == "" {
+			unit = product.BaseUnit
+		}
+
+		key := fmt.Sprintf("%d/%d/%s", product.ID, variantKey(reqLine.VariantID), strings.ToLower(unit))
+		if seen[key] {
+			return nil, models.NewValidationError("lines", fmt.Sprintf("%s (%s) is listed more than once", product.Name, unit))
+		}
+		seen[key] = true
+
+		if reqLine.Quantity <= 0 {
+			return nil, models.NewValidationError("quantity", "quantity must be greater than zero")
+		}
+		if err := validateQuantity(product, reqLine.Quantity*models.Quantity(factor)); err != nil {
+			return nil, err
+		}
+		if reqLine.UnitCost < 0 {
+			return nil, models.NewValidationError("unit_cost", "unit_cost cannot be negative")
+		}
+
+		line := models.PurchaseOrderLine{
+			ProductID: product.ID,
+			VariantID: reqLine.VariantID,
+			Unit:      unit,
+			Factor:    factor,
+			Quantity:  reqLine.Quantity,
+			UnitCost:  reqLine.UnitCost,
+			Subtotal:  reqLine.Quantity.Amount(reqLine.UnitCost),
+		}
+		po.TotalAmount += line.Subtotal
+		po.Lines = append(po.Lines, line)
+	}
+	return po, nil
+}
@@ -0,0 +1,112 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/domain/receipt"
+	"kasir-api/internal/domain/repositories"
+	"kasir-api/internal/pkg"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type ReceiptUseCase interface {
+	// RenderReceipt merender struk transaksi dalam format escpos, text atau
+	// pdf untuk kertas selebar paperMM (58 atau 80)
+	RenderReceipt(ctx context.Context, transactionID int, format string, paperMM int) ([]byte, error)
+}
+
+type receiptUseCase struct {
+	transactionRepo repositories.TransactionRepository
+	userRepo        repositories.UserRepository
+	store           receipt.Store
+	location        *time.Location
+}
+
+// NewReceiptUseCase membuat instance baru dari ReceiptUseCase. Waktu
+// transaksi dicetak dalam zona waktu location.
+func NewReceiptUseCase(transactionRepo repositories.TransactionRepository, userRepo repositories.UserRepository,
+	store receipt.Store, location *time.Location) ReceiptUseCase {
+	return &receiptUseCase{
+		transactionRepo: transactionRepo,
+		userRepo:        userRepo,
+		store:           store,
+		location:        location,
+	}
+}
+
+func (uc *receiptUseCase) RenderReceipt(ctx context.Context, transactionID int, format string, paperMM int) ([]byte, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":        "receipt",
+		"action":         "render_receipt",
+		"transaction_id": transactionID,
+		"format":         format,
+		"paper_mm":       paperMM,
+	}).Info("Executing render receipt use case")
+
+	switch format {
+	case receipt.FormatESCPOS, receipt.FormatText, receipt.FormatPDF:
+	default:
+		return nil, models.NewValidationError("format", "format must be one of escpos, text, pdf")
+	}
+	columns, ok := receipt.Columns(paperMM)
+	if !ok {
+		return nil, models.NewValidationError("paper", "paper must be 58 or 80")
+	}
+	if transactionID <= 0 {
+		return nil, models.NewValidationError("id", "invalid transaction ID")
+	}
+
+	transaction, err := uc.transactionRepo.GetTransactionByID(ctx, transactionID)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":        "receipt",
+			"action":         "render_receipt",
+			"transaction_id": transactionID,
+			"error":          err.Error(),
+		}).Error("Failed to get transaction")
+		return nil, err
+	}
+
+	// transaksi lama atau yang user-nya sudah dihapus tetap bisa dicetak
+	cashier := "-"
+	if transaction.UserID != nil {
+		user, err := uc.userRepo.GetUserByID(ctx, *transaction.UserID)
+		switch {
+		case err == nil:
+			cashier = user.Username
+		case !errors.Is(err, models.ErrNotFound):
+			return nil, err
+		}
+	}
+
+	r := receipt.Receipt{
+		Store:       uc.store,
+		Cashier:     cashier,
+		IssuedAt:    transaction.CreatedAt.In(uc.location),
+		Transaction: *transaction,
+	}
+
+	var body []byte
+	switch format {
+	case receipt.FormatESCPOS:
+		body = receipt.ESCPOS(r, columns)
+	case receipt.FormatText:
+		body = receipt.Text(r, columns)
+	case receipt.FormatPDF:
+		body = receipt.PDF(r, paperMM, columns)
+	}
+
+	pkg.ReceiptsRenderedTotal.WithLabelValues(format).Inc()
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":        "receipt",
+		"action":         "render_receipt",
+		"transaction_id": transactionID,
+		"format":         format,
+		"bytes":          len(body),
+	}).Info("Successfully rendered receipt")
+
+	return body, nil
+}
@@ -0,0 +1,145 @@
+package usecases
+
+import (
+	"context"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/domain/repositories"
+	"kasir-api/internal/pkg"
+
+	"github.com/sirupsen/logrus"
+)
+
+type RefundUseCase interface {
+	CreateRefund(ctx context.Context, transactionID int, req *models.RefundRequest) (*models.Refund, error)
+	GetRefundByID(ctx context.Context, id int) (*models.Refund, error)
+}
+
+type refundUseCase struct {
+	refundRepo repositories.RefundRepository
+}
+
+func NewRefundUseCase(refundRepo repositories.RefundRepository) RefundUseCase {
+	return &refundUseCase{refundRepo: refundRepo}
+}
+
+// CreateRefund memvalidasi permintaan refund lalu menyimpannya ke shift kasir
+// yang sedang dibuka. Batas jumlah unit per baris dicek di repository karena
+// bergantung pada refund sebelumnya.
+func (uc *refundUseCase) CreateRefund(ctx context.Context, transactionID int, req *models.RefundRequest) (*models.Refund, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":        "refund",
+		"action":         "create_refund",
+		"transaction_id": transactionID,
+		"items":          len(req.Items),
+	}).Info("Executing create refund use case")
+
+	if err := validateRefundRequest(transactionID, req); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":        "refund",
+			"action":         "create_refund",
+			"transaction_id": transactionID,
+			"error":          err.Error(),
+		}).Warn("Invalid refund request")
+		return nil, err
+	}
+
+	user, ok := pkg.AuthUserFromContext(ctx)
+	if !ok {
+		return nil, models.NewUnauthorizedError("authenticated user is required for refund")
+	}
+
+	refund := &models.Refund{
+		TransactionID: transactionID,
+		Reason:        req.Reason,
+		Note:          req.Note,
+		Method:        req.Method,
+		Restock:       req.Restock,
+		Items:         make([]models.RefundItem, 0, len(req.Items)),
+	}
+	for _, item := range req.Items {
+		refund.Items = append(refund.Items, models.RefundItem{
+			TransactionItemID: item.TransactionItemID,
+			Quantity:          item.Quantity,
+		})
+	}
+
+	if err := uc.refundRepo.CreateRefund(ctx, user.ID, refund); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":        "refund",
+			"action":         "create_refund",
+			"transaction_id": transactionID,
+			"error":          err.Error(),
+		}).Error("Failed to create refund")
+		return nil, err
+	}
+
+	pkg.RefundsTotal.WithLabelValues(refund.Reason).Inc()
+	pkg.RefundAmountTotal.WithLabelValues(refund.Method).Add(float64(refund.TotalAmount))
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":        "refund",
+		"action":         "create_refund",
+		"transaction_id": transactionID,
+		"refund_id":      refund.ID,
+		"total_amount":   refund.TotalAmount,
+	}).Info("Successfully created refund")
+
+	return refund, nil
+}
+
+func (uc *refundUseCase) GetRefundByID(ctx context.Context, id int) (*models.Refund, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "refund",
+		"action":  "get_refund_by_id",
+		"id":      id,
+	}).Info("Executing get refund by ID use case")
+
+	if id <= 0 {
+		return nil, models.NewValidationError("id", "invalid refund ID")
+	}
+
+	refund, err := uc.refundRepo.GetRefundByID(ctx, id)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "refund",
+			"action":  "get_refund_by_id",
+			"id":      id,
+			"error":   err.Error(),
+		}).Error("Failed to get refund by ID")
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+func validateRefundRequest(transactionID int, req *models.RefundRequest) error {
+	if transactionID <= 0 {
+		return models.NewValidationError("id", "invalid transaction ID")
+	}
+
+	switch req.Reason {
+	case models.RefundReasonCustomerRequest, models.RefundReasonDamaged, models.RefundReasonWrongItem,
+		models.RefundReasonExpired, models.RefundReasonOther:
+	default:
+		return models.NewValidationError("reason", "reason must be one of customer_request, damaged, wrong_item, expired, other")
+	}
+
+	if !models.IsValidPaymentMethod(req.Method) {
+		return models.NewValidationError("method", "method must be one of cash, debit_card, credit_card, ewallet, qris")
+	}
+
+	if len(req.Note) > 500 {
+		return models.NewValidationError("note", "note must be at most 500 characters")
+	}
+
+	for _, item := range req.Items {
+		if item.TransactionItemID <= 0 {
+			return models.NewValidationError("transaction_item_id", "invalid transaction item ID")
+		}
+		if item.Quantity <= 0 {
+			return models.NewValidationError("quantity", "quantity must be greater than zero")
+		}
+	}
+
+	return nil
+}
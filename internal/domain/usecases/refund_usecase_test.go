@@ -0,0 +1,107 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/domain/repositories"
+	"strings"
+	"testing"
+)
+
+// fakeRefundRepo mencatat refund yang disimpan; nilai per baris dihitung
+// repository asli, di sini cukup 1.000 per unit
+type fakeRefundRepo struct {
+	repositories.RefundRepository
+	err    error
+	userID int
+	saved  *models.Refund
+}
+
+func (f *fakeRefundRepo) CreateRefund(ctx context.Context, userID int, refund *models.Refund) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.userID = userID
+	refund.ID = 1
+	for i := range refund.Items {
+		refund.Items[i].Amount = refund.Items[i].Quantity.Amount(1000)
+		refund.TotalAmount += refund.Items[i].Amount
+	}
+	f.saved = refund
+	return nil
+}
+
+func TestCreateRefund(t *testing.T) {
+	repo := &fakeRefundRepo{}
+	uc := NewRefundUseCase(repo)
+
+	req := &models.RefundRequest{
+		Reason:  models.RefundReasonDamaged,
+		Method:  models.PaymentMethodCash,
+		Restock: true,
+		Items:   []models.RefundItemRequest{{TransactionItemID: 10, Quantity: models.WholeQuantity(2)}},
+	}
+	refund, err := uc.CreateRefund(cashierContext(), 42, req)
+	if err != nil {
+		t.Fatalf("CreateRefund() error = %v", err)
+	}
+	if repo.userID != 7 || repo.saved.TransactionID != 42 || !repo.saved.Restock {
+		t.Errorf("saved refund = user %d transaction %d restock %v, want 7, 42, true", repo.userID, repo.saved.TransactionID, repo.saved.Restock)
+	}
+	if len(refund.Items) != 1 || refund.Items[0].TransactionItemID != 10 || refund.TotalAmount != 2000 {
+		t.Errorf("refund = %+v, want line 10 with total 2000", refund)
+	}
+
+	// Items kosong diteruskan apa adanya, repository mengisi semua sisa unit
+	if _, err := uc.CreateRefund(cashierContext(), 42, &models.RefundRequest{Reason: models.RefundReasonOther, Method: models.PaymentMethodQRIS}); err != nil {
+		t.Fatalf("full CreateRefund() error = %v", err)
+	}
+	if len(repo.saved.Items) != 0 {
+		t.Errorf("full refund items = %d, want 0 for the repository to resolve", len(repo.saved.Items))
+	}
+}
+
+func TestCreateRefundRejects(t *testing.T) {
+	valid := models.RefundRequest{Reason: models.RefundReasonDamaged, Method: models.PaymentMethodCash}
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		transactionID int
+		mutate        func(req *models.RefundRequest)
+		repoErr       error
+		want          error
+	}{
+		{name: "invalid transaction id", ctx: cashierContext(), transactionID: 0, want: models.ErrValidation},
+		{name: "unknown reason", ctx: cashierContext(), transactionID: 42, want: models.ErrValidation,
+			mutate: func(req *models.RefundRequest) { req.Reason = "changed_mind" }},
+		{name: "unknown method", ctx: cashierContext(), transactionID: 42, want: models.ErrValidation,
+			mutate: func(req *models.RefundRequest) { req.Method = "voucher" }},
+		{name: "note too long", ctx: cashierContext(), transactionID: 42, want: models.ErrValidation,
+			mutate: func(req *models.RefundRequest) { req.Note = strings.Repeat("x", 501) }},
+		{name: "zero quantity", ctx: cashierContext(), transactionID: 42, want: models.ErrValidation,
+			mutate: func(req *models.RefundRequest) { req.Items = []models.RefundItemRequest{{TransactionItemID: 10}} }},
+		{name: "no authenticated user", ctx: context.Background(), transactionID: 42, want: models.ErrUnauthorized},
+		{name: "more than remaining", ctx: cashierContext(), transactionID: 42, want: models.ErrConflict,
+			repoErr: models.NewConflictError("only 1 of Sabun can still be refunded")},
+		{name: "unknown transaction", ctx: cashierContext(), transactionID: 42, want: models.ErrNotFound,
+			repoErr: models.NewNotFoundError("transaction")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeRefundRepo{err: tt.repoErr}
+			req := valid
+			if tt.mutate != nil {
+				tt.mutate(&req)
+			}
+			_, err := NewRefundUseCase(repo).CreateRefund(tt.ctx, tt.transactionID, &req)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("CreateRefund() error = %v, want %v", err, tt.want)
+			}
+			if repo.saved != nil {
+				t.Errorf("rejected refund must not be saved")
+			}
+		})
+	}
+}
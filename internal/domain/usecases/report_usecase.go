@@ -0,0 +1,106 @@
+package usecases
+
+import (
+	"context"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/domain/repositories"
+	"kasir-api/internal/pkg"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	reportDateLayout = "2006-01-02"
+	// maxReportDays membatasi periode laporan supaya query tetap ringan
+	maxReportDays = 366
+)
+
+type ReportUseCase interface {
+	// GetSalesReport merekap penjualan, HPP dan margin dari tanggal from
+	// sampai to (inklusif, format YYYY-MM-DD, zona waktu toko). Tanggal
+	// kosong berarti awal bulan ini sampai hari ini.
+	GetSalesReport(ctx context.Context, from, to string) (*models.SalesReport, error)
+}
+
+type reportUseCase struct {
+	reportRepo repositories.ReportRepository
+	location   *time.Location
+}
+
+// NewReportUseCase membuat instance baru dari ReportUseCase. Batas tanggal
+// laporan dihitung dalam zona waktu location.
+func NewReportUseCase(reportRepo repositories.ReportRepository, location *time.Location) ReportUseCase {
+	return &reportUseCase{
+		reportRepo: reportRepo,
+		location:   location,
+	}
+}
+
+func (uc *reportUseCase) GetSalesReport(ctx context.Context, from, to string) (*models.SalesReport, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "report",
+		"action":  "get_sales_report",
+		"from":    from,
+		"to":      to,
+	}).Info("Executing get sales report use case")
+
+	start, end, err := uc.reportPeriod(from, to)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "report",
+			"action":  "get_sales_report",
+			"error":   err.Error(),
+		}).Warn("Invalid report period")
+		return nil, err
+	}
+
+	report, err := uc.reportRepo.GetSalesReport(ctx, start, end)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "report",
+			"action":  "get_sales_report",
+			"error":   err.Error(),
+		}).Error("Failed to get sales report")
+		return nil, err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":      "report",
+		"action":       "get_sales_report",
+		"transactions": report.TransactionCount,
+		"net_sales":    report.NetSales,
+		"cogs":         report.COGS,
+	}).Info("Successfully built sales report")
+
+	return report, nil
+}
+
+// reportPeriod mengubah tanggal inklusif from..to menjadi rentang waktu
+// [start, end) di zona waktu toko
+func (uc *reportUseCase) reportPeriod(from, to string) (start, end time.Time, err error) {
+	now := time.Now().In(uc.location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, uc.location)
+
+	start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, uc.location)
+	if from != "" {
+		if start, err = time.ParseInLocation(reportDateLayout, from, uc.location); err != nil {
+			return time.Time{}, time.Time{}, models.NewValidationError("from", "from must be a date in YYYY-MM-DD format")
+		}
+	}
+	last := today
+	if to != "" {
+		if last, err = time.ParseInLocation(reportDateLayout, to, uc.location); err != nil {
+			return time.Time{}, time.Time{}, models.NewValidationError("to", "to must be a date in YYYY-MM-DD format")
+		}
+	}
+
+	if last.Before(start) {
+		return time.Time{}, time.Time{}, models.NewValidationError("to", "to must not be before from")
+	}
+	end = last.AddDate(0, 0, 1)
+	if end.After(start.AddDate(0, 0, maxReportDays)) {
+		return time.Time{}, time.Time{}, models.NewValidationError("to", "report period must be at most 366 days")
+	}
+	return start, end, nil
+}
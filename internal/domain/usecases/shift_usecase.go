@@ -0,0 +1,270 @@
+package usecases
+
+import (
+	"context"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/domain/repositories"
+	"kasir-api/internal/pkg"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ShiftUseCase adalah interface untuk shift kasir dan rekonsiliasi laci kas
+type ShiftUseCase interface {
+	OpenShift(ctx context.Context, req *models.OpenShiftRequest) (*models.Shift, error)
+	GetCurrentShift(ctx context.Context) (*models.ShiftReport, error)
+	GetShiftReport(ctx context.Context, id int) (*models.ShiftReport, error)
+	AddCashEvent(ctx context.Context, shiftID int, req *models.CashEventRequest) (*models.ShiftCashEvent, error)
+	CloseShift(ctx context.Context, id int, req *models.CloseShiftRequest) (*models.ShiftReport, error)
+}
+
+type shiftUseCase struct {
+	shiftRepo repositories.ShiftRepository
+}
+
+// NewShiftUseCase membuat instance baru dari ShiftUseCase
+func NewShiftUseCase(shiftRepo repositories.ShiftRepository) ShiftUseCase {
+	return &shiftUseCase{
+		shiftRepo: shiftRepo,
+	}
+}
+
+// OpenShift membuka shift baru untuk user yang login dengan modal awal laci
+func (uc *shiftUseCase) OpenShift(ctx context.Context, req *models.OpenShiftRequest) (*models.Shift, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":       "shift",
+		"action":        "open_shift",
+		"opening_float": req.OpeningFloat,
+	}).Info("Executing open shift use case")
+
+	user, ok := pkg.AuthUserFromContext(ctx)
+	if !ok {
+		return nil, models.NewUnauthorizedError("authenticated user is required")
+	}
+
+	if req.OpeningFloat < 0 {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":       "shift",
+			"action":        "open_shift",
+			"opening_float": req.OpeningFloat,
+		}).Warn("Opening float cannot be negative")
+		return nil, models.NewValidationError("opening_float", "opening float cannot be negative")
+	}
+
+	shift := &models.Shift{
+		UserID:       user.ID,
+		OpeningFloat: req.OpeningFloat,
+		OpeningNote:  req.Note,
+	}
+	if err := uc.shiftRepo.CreateShift(ctx, shift); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "shift",
+			"action":  "open_shift",
+			"error":   err.Error(),
+		}).Error("Failed to open shift")
+		return nil, err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":  "shift",
+		"action":   "open_shift",
+		"shift_id": shift.ID,
+	}).Info("Successfully opened shift")
+
+	return shift, nil
+}
+
+// GetCurrentShift mengembalikan rekap berjalan dari shift user yang login
+func (uc *shiftUseCase) GetCurrentShift(ctx context.Context) (*models.ShiftReport, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "shift",
+		"action":  "get_current_shift",
+	}).Info("Executing get current shift use case")
+
+	user, ok := pkg.AuthUserFromContext(ctx)
+	if !ok {
+		return nil, models.NewUnauthorizedError("authenticated user is required")
+	}
+
+	shift, err := uc.shiftRepo.GetOpenShiftByUserID(ctx, user.ID)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "shift",
+			"action":  "get_current_shift",
+			"error":   err.Error(),
+		}).Warn("Failed to get open shift")
+		return nil, err
+	}
+
+	return uc.GetShiftReport(ctx, shift.ID)
+}
+
+// GetShiftReport mengembalikan rekap penjualan dan kas sebuah shift
+func (uc *shiftUseCase) GetShiftReport(ctx context.Context, id int) (*models.ShiftReport, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":  "shift",
+		"action":   "get_shift_report",
+		"shift_id": id,
+	}).Info("Executing get shift report use case")
+
+	if _, err := uc.getAuthorizedShift(ctx, id); err != nil {
+		return nil, err
+	}
+
+	report, err := uc.shiftRepo.GetShiftReport(ctx, id)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":  "shift",
+			"action":   "get_shift_report",
+			"shift_id": id,
+			"error":    err.Error(),
+		}).Error("Failed to get shift report")
+		return nil, err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":       "shift",
+		"action":        "get_shift_report",
+		"shift_id":      id,
+		"expected_cash": report.ExpectedCash,
+	}).Info("Successfully retrieved shift report")
+
+	return report, nil
+}
+
+// AddCashEvent mencatat kas masuk/keluar laci di luar penjualan
+func (uc *shiftUseCase) AddCashEvent(ctx context.Context, shiftID int, req *models.CashEventRequest) (*models.ShiftCashEvent, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":  "shift",
+		"action":   "add_cash_event",
+		"shift_id": shiftID,
+		"type":     req.Type,
+		"amount":   req.Amount,
+	}).Info("Executing add cash event use case")
+
+	if req.Type != models.CashEventIn && req.Type != models.CashEventOut {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "shift",
+			"action":  "add_cash_event",
+			"type":    req.Type,
+		}).Warn("Invalid cash event type")
+		return nil, models.NewValidationError("type", "type must be cash_in or cash_out")
+	}
+
+	if req.Amount <= 0 {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "shift",
+			"action":  "add_cash_event",
+			"amount":  req.Amount,
+		}).Warn("Amount must be greater than zero")
+		return nil, models.NewValidationError("amount", "amount must be greater than zero")
+	}
+
+	if _, err := uc.getAuthorizedShift(ctx, shiftID); err != nil {
+		return nil, err
+	}
+
+	user, _ := pkg.AuthUserFromContext(ctx)
+	event := &models.ShiftCashEvent{
+		ShiftID: shiftID,
+		Type:    req.Type,
+		Amount:  req.Amount,
+		Note:    req.Note,
+		UserID:  &user.ID,
+	}
+	if err := uc.shiftRepo.AddCashEvent(ctx, event); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":  "shift",
+			"action":   "add_cash_event",
+			"shift_id": shiftID,
+			"error":    err.Error(),
+		}).Error("Failed to add cash event")
+		return nil, err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":  "shift",
+		"action":   "add_cash_event",
+		"shift_id": shiftID,
+		"event_id": event.ID,
+	}).Info("Successfully added cash event")
+
+	return event, nil
+}
+
+// CloseShift menutup shift dengan hasil hitung uang di laci dan
+// mengembalikan laporan selisih expected vs actual
+func (uc *shiftUseCase) CloseShift(ctx context.Context, id int, req *models.CloseShiftRequest) (*models.ShiftReport, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":  "shift",
+		"action":   "close_shift",
+		"shift_id": id,
+	}).Info("Executing close shift use case")
+
+	if req.CountedCash == nil || *req.CountedCash < 0 {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":  "shift",
+			"action":   "close_shift",
+			"shift_id": id,
+		}).Warn("Counted cash is required")
+		return nil, models.NewValidationError("counted_cash", "counted cash is required and cannot be negative")
+	}
+
+	if _, err := uc.getAuthorizedShift(ctx, id); err != nil {
+		return nil, err
+	}
+
+	report, err := uc.shiftRepo.CloseShift(ctx, id, *req.CountedCash, req.Note)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":  "shift",
+			"action":   "close_shift",
+			"shift_id": id,
+			"error":    err.Error(),
+		}).Error("Failed to close shift")
+		return nil, err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":       "shift",
+		"action":        "close_shift",
+		"shift_id":      id,
+		"expected_cash": report.ExpectedCash,
+		"variance":      *report.Variance,
+	}).Info("Successfully closed shift")
+
+	pkg.ShiftsClosedTotal.Inc()
+	pkg.ShiftCashVariance.Observe(float64(*report.Variance))
+
+	return report, nil
+}
+
+// getAuthorizedShift memastikan shift ada dan milik user yang login.
+// Admin boleh mengakses shift siapa saja.
+func (uc *shiftUseCase) getAuthorizedShift(ctx context.Context, id int) (*models.Shift, error) {
+	user, ok := pkg.AuthUserFromContext(ctx)
+	if !ok {
+		return nil, models.NewUnauthorizedError("authenticated user is required")
+	}
+
+	shift, err := uc.shiftRepo.GetShiftByID(ctx, id)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":  "shift",
+			"shift_id": id,
+			"error":    err.Error(),
+		}).Warn("Failed to get shift")
+		return nil, err
+	}
+
+	if user.Role != models.RoleAdmin && shift.UserID != user.ID {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":  "shift",
+			"shift_id": id,
+			"owner_id": shift.UserID,
+		}).Warn("Access to shift denied")
+		return nil, models.NewForbiddenError("shift belongs to another user")
+	}
+
+	return shift, nil
+}
@@ -0,0 +1,418 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/domain/repositories"
+	"kasir-api/internal/pkg"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultStockOpnamesPerPage = 20
+	maxStockOpnamesPerPage     = 100
+)
+
+// StockOpnameUseCase adalah interface untuk sesi hitung fisik stok
+type StockOpnameUseCase interface {
+	CreateStockOpname(ctx context.Context, req *models.CreateStockOpnameRequest) (*models.StockOpname, error)
+	GetStockOpnames(ctx context.Context, status string, page, perPage int) (*models.StockOpnameList, error)
+	GetStockOpnameByID(ctx context.Context, id int) (*models.StockOpname, error)
+	GetVariance(ctx context.Context, id int) (*models.StockOpnameVariance, error)
+	SubmitCounts(ctx context.Context, id int, req *models.StockOpnameCountRequest) ([]models.StockOpnameCount, error)
+	ScanCount(ctx context.Context, id int, req *models.StockOpnameScanRequest) (*models.StockOpnameCount, error)
+	ApproveStockOpname(ctx context.Context, id int) (*models.StockOpnameVariance, error)
+	CancelStockOpname(ctx context.Context, id int) (*models.StockOpname, error)
+}
+
+type stockOpnameUseCase struct {
+	opnameRepo     repositories.StockOpnameRepository
+	productRepo    repositories.ProductRepository
+	categoryRepo   repositories.CategoryRepository
+	productUseCase ProductUseCase
+}
+
+// NewStockOpnameUseCase membuat instance baru dari StockOpnameUseCase.
+// productUseCase dipakai untuk lookup barcode saat scan, termasuk label
+// timbangan.
+func NewStockOpnameUseCase(opnameRepo repositories.StockOpnameRepository, productRepo repositories.ProductRepository,
+	categoryRepo repositories.CategoryRepository, productUseCase ProductUseCase) StockOpnameUseCase {
+	return &stockOpnameUseCase{
+		opnameRepo:     opnameRepo,
+		productRepo:    productRepo,
+		categoryRepo:   categoryRepo,
+		productUseCase: productUseCase,
+	}
+}
+
+func (uc *stockOpnameUseCase) CreateStockOpname(ctx context.Context, req *models.CreateStockOpnameRequest) (*models.StockOpname, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":     "stock_opname",
+		"action":      "create_stock_opname",
+		"category_id": req.CategoryID,
+	}).Info("Executing create stock opname use case")
+
+	user, ok := pkg.AuthUserFromContext(ctx)
+	if !ok {
+		return nil, models.NewUnauthorizedError("authenticated user is required")
+	}
+
+	note := strings.TrimSpace(req.Note)
+	if len(note) > 500 {
+		return nil, models.NewValidationError("note", "note must be at most 500 characters")
+	}
+	if req.CategoryID != nil {
+		if _, err := uc.categoryRepo.GetCategoryByID(ctx, *req.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	opname := &models.StockOpname{
+		Status:     models.StockOpnameStatusOpen,
+		Note:       note,
+		CategoryID: req.CategoryID,
+		CreatedBy:  &user.ID,
+	}
+	if err := uc.opnameRepo.CreateStockOpname(ctx, opname); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "stock_opname",
+			"action":  "create_stock_opname",
+			"error":   err.Error(),
+		}).Error("Failed to create stock opname")
+		return nil, err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":    "stock_opname",
+		"action":     "create_stock_opname",
+		"opname_id":  opname.ID,
+		"item_count": opname.ItemCount,
+	}).Info("Successfully opened stock opname")
+
+	return uc.opnameRepo.GetStockOpnameByID(ctx, opname.ID)
+}
+
+func (uc *stockOpnameUseCase) GetStockOpnames(ctx context.Context, status string, page, perPage int) (*models.StockOpnameList, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "stock_opname",
+		"action":  "get_stock_opnames",
+		"status":  status,
+	}).Info("Executing get stock opnames use case")
+
+	switch status {
+	case "", models.StockOpnameStatusOpen, models.StockOpnameStatusApproved, models.StockOpnameStatusCancelled:
+	default:
+		return nil, models.NewValidationError("status", "status must be one of open, approved, cancelled")
+	}
+	if page <= 0 {
+		page = 1
+	}
+	if perPage <= 0 {
+		perPage = defaultStockOpnamesPerPage
+	}
+	if perPage > maxStockOpnamesPerPage {
+		perPage = maxStockOpnamesPerPage
+	}
+
+	opnames, total, err := uc.opnameRepo.GetStockOpnames(ctx, status, page, perPage)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "stock_opname",
+			"action":  "get_stock_opnames",
+			"error":   err.Error(),
+		}).Error("Failed to get stock opnames")
+		return nil, err
+	}
+
+	return &models.StockOpnameList{
+		Opnames: opnames,
+		Meta: models.PaginationMeta{
+			Page:       page,
+			PerPage:    perPage,
+			Total:      total,
+			TotalPages: (total + perPage - 1) / perPage,
+		},
+	}, nil
+}
+
+func (uc *stockOpnameUseCase) GetStockOpnameByID(ctx context.Context, id int) (*models.StockOpname, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "stock_opname",
+		"action":  "get_stock_opname_by_id",
+		"id":      id,
+	}).Info("Executing get stock opname by ID use case")
+
+	return uc.opnameRepo.GetStockOpnameByID(ctx, id)
+}
+
+// GetVariance membandingkan hasil hitung dengan snapshot. Nilai selisih
+// dihitung dengan harga pokok saat sesi dibuka.
+func (uc *stockOpnameUseCase) GetVariance(ctx context.Context, id int) (*models.StockOpnameVariance, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "stock_opname",
+		"action":  "get_variance",
+		"id":      id,
+	}).Info("Executing get stock opname variance use case")
+
+	opname, err := uc.opnameRepo.GetStockOpnameByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	items, err := uc.opnameRepo.GetStockOpnameItems(ctx, id)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "stock_opname",
+			"action":  "get_variance",
+			"id":      id,
+			"error":   err.Error(),
+		}).Error("Failed to get stock opname items")
+		return nil, err
+	}
+
+	report := &models.StockOpnameVariance{
+		Opname:    *opname,
+		Items:     make([]models.StockOpnameItem, 0),
+		Uncounted: make([]models.StockOpnameItem, 0),
+	}
+	for _, item := range items {
+		switch {
+		case item.Counted == nil:
+			report.Uncounted = append(report.Uncounted, item)
+		case item.Variance != 0:
+			report.Items = append(report.Items, item)
+			if item.VarianceValue > 0 {
+				report.SurplusValue += item.VarianceValue
+			} else {
+				report.ShortageValue -= item.VarianceValue
+			}
+		}
+	}
+	report.DiscrepancyItems = len(report.Items)
+	report.NetValue = report.SurplusValue - report.ShortageValue
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":           "stock_opname",
+		"action":            "get_variance",
+		"id":                id,
+		"discrepancy_items": report.DiscrepancyItems,
+		"uncounted":         len(report.Uncounted),
+		"net_value":         report.NetValue,
+	}).Info("Successfully computed stock opname variance")
+
+	return report, nil
+}
+
+// SubmitCounts menyimpan hasil hitung penghitung yang sedang login. Baris
+// untuk produk dan varian yang sama dijumlahkan, sehingga hitungan dalam
+// beberapa satuan (mis. 2 box + 5 pcs) bisa dikirim sekaligus.
+func (uc *stockOpnameUseCase) SubmitCounts(ctx context.Context, id int, req *models.StockOpnameCountRequest) ([]models.StockOpnameCount, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "stock_opname",
+		"action":  "submit_counts",
+		"id":      id,
+		"counts":  len(req.Counts),
+	}).Info("Executing submit stock opname counts use case")
+
+	user, ok := pkg.AuthUserFromContext(ctx)
+	if !ok {
+		return nil, models.NewUnauthorizedError("authenticated user is required")
+	}
+	if len(req.Counts) == 0 {
+		return nil, models.NewValidationError("counts", "counts must not be empty")
+	}
+
+	index := make(map[[2]int]int, len(req.Counts))
+	counts := make([]models.StockOpnameCount, 0, len(req.Counts))
+	products := make(map[int]*models.Product, len(req.Counts))
+	for _, line := range req.Counts {
+		if line.Quantity < 0 {
+			return nil, models.NewValidationError("quantity", "quantity cannot be negative")
+		}
+		product, err := uc.countedProduct(ctx, line.ProductID, line.VariantID)
+		if err != nil {
+			return nil, err
+		}
+		factor, err := product.UnitFactor(line.Unit)
+		if err != nil {
+			return nil, err
+		}
+		products[product.ID] = product
+
+		key := [2]int{line.ProductID, variantKey(line.VariantID)}
+		quantity := line.Quantity * models.Quantity(factor)
+		if i, ok := index[key]; ok {
+			counts[i].Quantity += quantity
+			continue
+		}
+		index[key] = len(counts)
+		counts = append(counts, models.StockOpnameCount{ProductID: line.ProductID, VariantID: line.VariantID, Quantity: quantity})
+	}
+	for _, count := range counts {
+		if err := validateQuantity(products[count.ProductID], count.Quantity); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.opnameRepo.SaveCounts(ctx, id, user.ID, counts, false); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "stock_opname",
+			"action":  "submit_counts",
+			"id":      id,
+			"error":   err.Error(),
+		}).Error("Failed to save stock opname counts")
+		return nil, err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "stock_opname",
+		"action":  "submit_counts",
+		"id":      id,
+		"user_id": user.ID,
+		"items":   len(counts),
+	}).Info("Successfully saved stock opname counts")
+
+	return counts, nil
+}
+
+// ScanCount menambah hasil hitung penghitung untuk barang yang di-scan.
+// Label timbangan menambah berat yang tercetak di label.
+func (uc *stockOpnameUseCase) ScanCount(ctx context.Context, id int, req *models.StockOpnameScanRequest) (*models.StockOpnameCount, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "stock_opname",
+		"action":  "scan_count",
+		"id":      id,
+		"barcode": req.Barcode,
+	}).Info("Executing scan stock opname count use case")
+
+	user, ok := pkg.AuthUserFromContext(ctx)
+	if !ok {
+		return nil, models.NewUnauthorizedError("authenticated user is required")
+	}
+
+	product, err := uc.productUseCase.GetProductByBarcode(ctx, req.Barcode)
+	if err != nil {
+		return nil, err
+	}
+
+	quantity := models.WholeQuantity(1)
+	switch {
+	case req.Quantity != nil:
+		quantity = *req.Quantity
+	case product.ScannedQuantity != nil:
+		quantity = *product.ScannedQuantity
+	}
+	if quantity <= 0 {
+		return nil, models.NewValidationError("quantity", "quantity must be greater than zero")
+	}
+	if err := validateQuantity(product, quantity); err != nil {
+		return nil, err
+	}
+	if len(product.Variants) > 0 && product.VariantID == nil {
+		return nil, models.NewValidationError("barcode", fmt.Sprintf("%s has variants, scan the variant barcode", product.Name))
+	}
+
+	counts := []models.StockOpnameCount{{ProductID: product.ID, VariantID: product.VariantID, Quantity: quantity}}
+	if err := uc.opnameRepo.SaveCounts(ctx, id, user.ID, counts, true); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "stock_opname",
+			"action":  "scan_count",
+			"id":      id,
+			"error":   err.Error(),
+		}).Error("Failed to save scanned count")
+		return nil, err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":    "stock_opname",
+		"action":     "scan_count",
+		"id":         id,
+		"product_id": product.ID,
+		"variant_id": product.VariantID,
+		"quantity":   counts[0].Quantity,
+	}).Info("Successfully saved scanned count")
+
+	return &counts[0], nil
+}
+
+// countedProduct memastikan produk ada dan variant_id sesuai: produk
+// bervarian dihitung per varian, produk tanpa varian tidak boleh menyebut
+// variant_id. Varian nonaktif tetap boleh dihitung selama masih masuk snapshot.
+func (uc *stockOpnameUseCase) countedProduct(ctx context.Context, productID int, variantID *int) (*models.Product, error) {
+	product, err := uc.productRepo.GetProductByID(ctx, productID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, models.NewNotFoundError(fmt.Sprintf("product %d", productID))
+		}
+		return nil, err
+	}
+	if len(product.Variants) == 0 && variantID != nil {
+		return nil, models.NewValidationError("variant_id", fmt.Sprintf("%s has no variants", product.Name))
+	}
+	if len(product.Variants) > 0 && variantID == nil {
+		return nil, models.NewValidationError("variant_id", fmt.Sprintf("%s has variants, variant_id is required", product.Name))
+	}
+	return product, nil
+}
+
+// ApproveStockOpname memposting adjustment untuk setiap item yang sudah
+// dihitung dan berbeda dari snapshot, lalu mengembalikan laporan selisih
+// yang sudah dibekukan
+func (uc *stockOpnameUseCase) ApproveStockOpname(ctx context.Context, id int) (*models.StockOpnameVariance, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "stock_opname",
+		"action":  "approve_stock_opname",
+		"id":      id,
+	}).Info("Executing approve stock opname use case")
+
+	user, ok := pkg.AuthUserFromContext(ctx)
+	if !ok {
+		return nil, models.NewUnauthorizedError("authenticated user is required")
+	}
+
+	adjustments, err := uc.opnameRepo.ApproveStockOpname(ctx, id, user.ID)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "stock_opname",
+			"action":  "approve_stock_opname",
+			"id":      id,
+			"error":   err.Error(),
+		}).Error("Failed to approve stock opname")
+		return nil, err
+	}
+
+	pkg.StockMovementsTotal.WithLabelValues(models.StockReasonAdjustment).Add(float64(adjustments))
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":     "stock_opname",
+		"action":      "approve_stock_opname",
+		"id":          id,
+		"approved_by": user.ID,
+		"adjustments": adjustments,
+	}).Info("Successfully approved stock opname")
+
+	return uc.GetVariance(ctx, id)
+}
+
+// CancelStockOpname menutup sesi tanpa mengubah stok
+func (uc *stockOpnameUseCase) CancelStockOpname(ctx context.Context, id int) (*models.StockOpname, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "stock_opname",
+		"action":  "cancel_stock_opname",
+		"id":      id,
+	}).Info("Executing cancel stock opname use case")
+
+	if err := uc.opnameRepo.CancelStockOpname(ctx, id); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "stock_opname",
+			"action":  "cancel_stock_opname",
+			"id":      id,
+			"error":   err.Error(),
+		}).Warn("Failed to cancel stock opname")
+		return nil, err
+	}
+
+	return uc.opnameRepo.GetStockOpnameByID(ctx, id)
+}
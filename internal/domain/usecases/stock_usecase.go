@@ -0,0 +1,216 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/domain/repositories"
+	"kasir-api/internal/pkg"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultStockHistoryPerPage = 20
+	maxStockHistoryPerPage     = 100
+)
+
+// StockUseCase adalah interface untuk pergerakan stok produk
+type StockUseCase interface {
+	AdjustStock(ctx context.Context, productID int, req *models.StockAdjustmentRequest) (*models.StockMovement, error)
+	GetStockHistory(ctx context.Context, productID, page, perPage int) (*models.StockMovementList, error)
+}
+
+type stockUseCase struct {
+	stockRepo   repositories.StockRepository
+	productRepo repositories.ProductRepository
+}
+
+// NewStockUseCase membuat instance baru dari StockUseCase
+func NewStockUseCase(stockRepo repositories.StockRepository, productRepo repositories.ProductRepository) StockUseCase {
+	return &stockUseCase{
+		stockRepo:   stockRepo,
+		productRepo: productRepo,
+	}
+}
+
+// AdjustStock mencatat pergerakan stok manual dan memperbarui stok produk
+func (uc *stockUseCase) AdjustStock(ctx context.Context, productID int, req *models.StockAdjustmentRequest) (*models.StockMovement, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":    "stock",
+		"action":     "adjust_stock",
+		"product_id": productID,
+		"variant_id": req.VariantID,
+		"delta":      req.Delta,
+		"reason":     req.Reason,
+	}).Info("Executing adjust stock use case")
+
+	if err := validateStockAdjustment(req); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":    "stock",
+			"action":     "adjust_stock",
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Warn("Invalid stock adjustment")
+		return nil, err
+	}
+
+	// Delta dan harga pokok dalam satuan selain satuan dasar dikonversi dulu,
+	// ledger selalu mencatat satuan dasar
+	delta, unitCost, note := req.Delta, req.UnitCost, req.Note
+	if strings.TrimSpace(req.Unit) != "" {
+		product, err := uc.productRepo.GetProductByID(ctx, productID)
+		if err != nil {
+			return nil, err
+		}
+		factor, err := product.UnitFactor(req.Unit)
+		if err != nil {
+			pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+				"usecase":    "stock",
+				"action":     "adjust_stock",
+				"product_id": productID,
+				"unit":       req.Unit,
+			}).Warn("Unknown stock unit")
+			return nil, err
+		}
+		if factor != 1 {
+			delta *= models.Quantity(factor)
+			note = strings.TrimSpace(fmt.Sprintf("%s (%s %s)", note, req.Delta, strings.TrimSpace(req.Unit)))
+			if unitCost != nil {
+				baseCost := models.BaseUnitCost(*unitCost, factor)
+				unitCost = &baseCost
+			}
+		}
+	}
+
+	movement := &models.StockMovement{
+		ProductID:   productID,
+		VariantID:   req.VariantID,
+		Delta:       delta,
+		Reason:      req.Reason,
+		ReferenceID: req.ReferenceID,
+		Note:        note,
+		UnitCost:    unitCost,
+	}
+	if err := uc.stockRepo.ApplyMovement(ctx, movement); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":    "stock",
+			"action":     "adjust_stock",
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error("Failed to apply stock movement")
+		return nil, err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":     "stock",
+		"action":      "adjust_stock",
+		"product_id":  productID,
+		"movement_id": movement.ID,
+		"stock_after": movement.StockAfter,
+	}).Info("Successfully adjusted stock")
+
+	pkg.StockMovementsTotal.WithLabelValues(movement.Reason).Inc()
+
+	return movement, nil
+}
+
+// validateStockAdjustment memastikan arah delta sesuai dengan reason.
+// Penjualan dan barang rusak selalu mengurangi stok, restock dan retur
+// selalu menambah, sedangkan adjustment boleh ke dua arah.
+func validateStockAdjustment(req *models.StockAdjustmentRequest) error {
+	if req.Delta == 0 {
+		return models.NewValidationError("delta", "delta must not be zero")
+	}
+	if req.VariantID != nil && *req.VariantID <= 0 {
+		return models.NewValidationError("variant_id", "invalid variant ID")
+	}
+
+	switch req.Reason {
+	case models.StockReasonSale, models.StockReasonDamage:
+		if req.Delta > 0 {
+			return models.NewValidationError("delta", "delta must be negative for reason "+req.Reason)
+		}
+	case models.StockReasonRestock, models.StockReasonReturn:
+		if req.Delta < 0 {
+			return models.NewValidationError("delta", "delta must be positive for reason "+req.Reason)
+		}
+	case models.StockReasonAdjustment:
+	default:
+		return models.NewValidationError("reason", "reason must be one of sale, restock, adjustment, return, damage")
+	}
+
+	if req.UnitCost != nil {
+		if req.Delta < 0 {
+			return models.NewValidationError("unit_cost", "unit_cost is only allowed when adding stock")
+		}
+		if *req.UnitCost < 0 {
+			return models.NewValidationError("unit_cost", "unit_cost cannot be negative")
+		}
+	}
+
+	if len(req.ReferenceID) > 100 {
+		return models.NewValidationError("reference_id", "reference_id must be at most 100 characters")
+	}
+
+	return nil
+}
+
+// GetStockHistory mengembalikan riwayat pergerakan stok produk, terbaru dulu
+func (uc *stockUseCase) GetStockHistory(ctx context.Context, productID, page, perPage int) (*models.StockMovementList, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":    "stock",
+		"action":     "get_stock_history",
+		"product_id": productID,
+	}).Info("Executing get stock history use case")
+
+	if page <= 0 {
+		page = 1
+	}
+	if perPage <= 0 {
+		perPage = defaultStockHistoryPerPage
+	}
+	if perPage > maxStockHistoryPerPage {
+		perPage = maxStockHistoryPerPage
+	}
+
+	// Pastikan produk ada supaya produk yang tidak dikenal mendapat 404, bukan list kosong
+	if _, err := uc.productRepo.GetProductByID(ctx, productID); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":    "stock",
+			"action":     "get_stock_history",
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Warn("Failed to get product")
+		return nil, err
+	}
+
+	movements, total, err := uc.stockRepo.GetMovementsByProductID(ctx, productID, perPage, (page-1)*perPage)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":    "stock",
+			"action":     "get_stock_history",
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Error("Failed to get stock movements")
+		return nil, err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":    "stock",
+		"action":     "get_stock_history",
+		"product_id": productID,
+		"count":      len(movements),
+	}).Info("Successfully retrieved stock history")
+
+	return &models.StockMovementList{
+		Movements: movements,
+		Meta: models.PaginationMeta{
+			Page:       page,
+			PerPage:    perPage,
+			Total:      total,
+			TotalPages: (total + perPage - 1) / perPage,
+		},
+	}, nil
+}
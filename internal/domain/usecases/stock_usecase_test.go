@@ -0,0 +1,169 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/domain/repositories"
+	"testing"
+)
+
+// fakeStockRepo meniru ledger stok: setiap movement mengubah stok produk,
+// stok tidak boleh negatif dan riwayat dikembalikan terbaru dulu
+type fakeStockRepo struct {
+	repositories.StockRepository
+	products  *fakeProductRepo
+	movements []models.StockMovement
+}
+
+func (f *fakeStockRepo) ApplyMovement(ctx context.Context, movement *models.StockMovement) error {
+	product, ok := f.products.products[movement.ProductID]
+	if !ok {
+		return models.NewNotFoundError("product")
+	}
+	after := product.Stock + movement.Delta
+	if after < 0 {
+		return models.NewConflictError(fmt.Sprintf("insufficient stock for %s", product.Name))
+	}
+	product.Stock = after
+	movement.ID = len(f.movements) + 1
+	movement.ProductName = product.Name
+	movement.StockAfter = after
+	f.movements = append(f.movements, *movement)
+	return nil
+}
+
+func (f *fakeStockRepo) GetMovementsByProductID(ctx context.Context, productID, limit, offset int) ([]models.StockMovement, int, error) {
+	var matched []models.StockMovement
+	for i := len(f.movements) - 1; i >= 0; i-- {
+		if f.movements[i].ProductID == productID {
+			matched = append(matched, f.movements[i])
+		}
+	}
+	total := len(matched)
+	if offset >= total {
+		return []models.StockMovement{}, total, nil
+	}
+	return matched[offset:min(offset+limit, total)], total, nil
+}
+
+func newStockFixture() (StockUseCase, *fakeProductRepo, *fakeStockRepo) {
+	products := &fakeProductRepo{products: map[int]*models.Product{
+		1: {ID: 1, Name: "Indomie Goreng", Price: 3500, Stock: models.WholeQuantity(10), BaseUnit: "pcs",
+			Units: []models.ProductUnit{{Name: "dus", Factor: 40}}},
+	}}
+	stock := &fakeStockRepo{products: products}
+	return NewStockUseCase(stock, products), products, stock
+}
+
+func TestAdjustStockLedger(t *testing.T) {
+	uc, products, stock := newStockFixture()
+	ctx := context.Background()
+
+	// restock 2 dus @ 120.000 dicatat 80 pcs @ 3.000 di ledger
+	movement, err := uc.AdjustStock(ctx, 1, &models.StockAdjustmentRequest{
+		Delta: models.WholeQuantity(2), Unit: "dus", UnitCost: intPtr(120000), Reason: models.StockReasonRestock, Note: "PO supplier"})
+	if err != nil {
+		t.Fatalf("AdjustStock() error = %v", err)
+	}
+	if movement.Delta != models.WholeQuantity(80) || movement.StockAfter != models.WholeQuantity(90) {
+		t.Errorf("movement delta, stock after = %s, %s, want 80, 90", movement.Delta, movement.StockAfter)
+	}
+	if movement.UnitCost == nil || *movement.UnitCost != 3000 {
+		t.Errorf("unit cost = %v, want 3000", movement.UnitCost)
+	}
+	if movement.Note != "PO supplier (2 dus)" {
+		t.Errorf("note = %q, want %q", movement.Note, "PO supplier (2 dus)")
+	}
+
+	if _, err := uc.AdjustStock(ctx, 1, &models.StockAdjustmentRequest{
+		Delta: -models.WholeQuantity(5), Reason: models.StockReasonDamage}); err != nil {
+		t.Fatalf("AdjustStock() error = %v", err)
+	}
+	if got := products.products[1].Stock; got != models.WholeQuantity(85) {
+		t.Errorf("stock = %s, want 85", got)
+	}
+
+	// stok tidak cukup ditolak repository dan ledger tidak bertambah
+	_, err = uc.AdjustStock(ctx, 1, &models.StockAdjustmentRequest{Delta: -models.WholeQuantity(100), Reason: models.StockReasonAdjustment})
+	if !errors.Is(err, models.ErrConflict) {
+		t.Fatalf("AdjustStock() error = %v, want conflict", err)
+	}
+	if len(stock.movements) != 2 {
+		t.Errorf("movements = %d, want 2", len(stock.movements))
+	}
+}
+
+func TestAdjustStockValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		productID int
+		req       models.StockAdjustmentRequest
+		want      error
+	}{
+		{name: "zero delta", productID: 1, req: models.StockAdjustmentRequest{Reason: models.StockReasonAdjustment}, want: models.ErrValidation},
+		{name: "positive damage", productID: 1, want: models.ErrValidation,
+			req: models.StockAdjustmentRequest{Delta: models.WholeQuantity(1), Reason: models.StockReasonDamage}},
+		{name: "negative restock", productID: 1, want: models.ErrValidation,
+			req: models.StockAdjustmentRequest{Delta: -models.WholeQuantity(1), Reason: models.StockReasonRestock}},
+		{name: "unknown reason", productID: 1, want: models.ErrValidation,
+			req: models.StockAdjustmentRequest{Delta: models.WholeQuantity(1), Reason: "lost"}},
+		{name: "unit cost on outbound", productID: 1, want: models.ErrValidation,
+			req: models.StockAdjustmentRequest{Delta: -models.WholeQuantity(1), UnitCost: intPtr(3000), Reason: models.StockReasonAdjustment}},
+		{name: "negative unit cost", productID: 1, want: models.ErrValidation,
+			req: models.StockAdjustmentRequest{Delta: models.WholeQuantity(1), UnitCost: intPtr(-1), Reason: models.StockReasonRestock}},
+		{name: "unknown unit", productID: 1, want: models.ErrValidation,
+			req: models.StockAdjustmentRequest{Delta: models.WholeQuantity(1), Unit: "pak", Reason: models.StockReasonRestock}},
+		{name: "unknown product", productID: 99, want: models.ErrNotFound,
+			req: models.StockAdjustmentRequest{Delta: models.WholeQuantity(1), Reason: models.StockReasonRestock}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, products, stock := newStockFixture()
+			_, err := uc.AdjustStock(context.Background(), tt.productID, &tt.req)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("AdjustStock() error = %v, want %v", err, tt.want)
+			}
+			if len(stock.movements) != 0 || products.products[1].Stock != models.WholeQuantity(10) {
+				t.Errorf("rejected adjustment must not touch the ledger")
+			}
+		})
+	}
+}
+
+func TestGetStockHistory(t *testing.T) {
+	uc, _, _ := newStockFixture()
+	ctx := context.Background()
+	for i := 1; i <= 3; i++ {
+		req := &models.StockAdjustmentRequest{Delta: models.WholeQuantity(i), Reason: models.StockReasonRestock}
+		if _, err := uc.AdjustStock(ctx, 1, req); err != nil {
+			t.Fatalf("AdjustStock() error = %v", err)
+		}
+	}
+
+	list, err := uc.GetStockHistory(ctx, 1, 1, 2)
+	if err != nil {
+		t.Fatalf("GetStockHistory() error = %v", err)
+	}
+	if len(list.Movements) != 2 || list.Movements[0].Delta != models.WholeQuantity(3) {
+		t.Errorf("first page = %d movements, want 2 newest first", len(list.Movements))
+	}
+	if list.Meta.Total != 3 || list.Meta.TotalPages != 2 || list.Meta.PerPage != 2 {
+		t.Errorf("meta = %+v, want total 3, 2 pages of 2", list.Meta)
+	}
+
+	list, err = uc.GetStockHistory(ctx, 1, 0, 1000)
+	if err != nil {
+		t.Fatalf("GetStockHistory() error = %v", err)
+	}
+	if list.Meta.Page != 1 || list.Meta.PerPage != maxStockHistoryPerPage {
+		t.Errorf("page, per page = %d, %d, want 1, %d", list.Meta.Page, list.Meta.PerPage, maxStockHistoryPerPage)
+	}
+
+	// produk yang tidak dikenal mendapat 404, bukan riwayat kosong
+	if _, err := uc.GetStockHistory(ctx, 99, 1, 20); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("GetStockHistory() error = %v, want not found", err)
+	}
+}
@@ -0,0 +1,207 @@
+package usecases
+
+import (
+	"context"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/domain/repositories"
+	"kasir-api/internal/pkg"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+type SupplierUseCase interface {
+	GetAllSuppliers(ctx context.Context) ([]models.Supplier, error)
+	GetSupplierByID(ctx context.Context, id int) (*models.Supplier, error)
+	CreateSupplier(ctx context.Context, supplier *models.Supplier) error
+	UpdateSupplier(ctx context.Context, supplier *models.Supplier) error
+	DeleteSupplier(ctx context.Context, id int) error
+}
+
+type supplierUseCase struct {
+	supplierRepo repositories.SupplierRepository
+}
+
+func NewSupplierUseCase(supplierRepo repositories.SupplierRepository) SupplierUseCase {
+	return &supplierUseCase{
+		supplierRepo: supplierRepo,
+	}
+}
+
+func (uc *supplierUseCase) GetAllSuppliers(ctx context.Context) ([]models.Supplier, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "supplier",
+		"action":  "get_all_suppliers",
+	}).Info("Executing get all suppliers use case")
+
+	suppliers, err := uc.supplierRepo.GetAllSuppliers(ctx)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "supplier",
+			"action":  "get_all_suppliers",
+			"error":   err.Error(),
+		}).Error("Failed to get all suppliers")
+		return nil, err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "supplier",
+		"action":  "get_all_suppliers",
+		"count":   len(suppliers),
+	}).Info("Successfully retrieved all suppliers")
+
+	return suppliers, nil
+}
+
+func (uc *supplierUseCase) GetSupplierByID(ctx context.Context, id int) (*models.Supplier, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "supplier",
+		"action":  "get_supplier_by_id",
+		"id":      id,
+	}).Info("Executing get supplier by ID use case")
+
+	supplier, err := uc.supplierRepo.GetSupplierByID(ctx, id)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "supplier",
+			"action":  "get_supplier_by_id",
+			"id":      id,
+			"error":   err.Error(),
+		}).Error("Failed to get supplier by ID")
+		return nil, err
+	}
+
+	return supplier, nil
+}
+
+func (uc *supplierUseCase) CreateSupplier(ctx context.Context, supplier *models.Supplier) error {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "supplier",
+		"action":  "create_supplier",
+	}).Info("Executing create supplier use case")
+
+	if err := normalizeSupplier(supplier); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "supplier",
+			"action":  "create_supplier",
+			"error":   err.Error(),
+		}).Warn("Invalid supplier")
+		return err
+	}
+
+	if err := uc.supplierRepo.CreateSupplier(ctx, supplier); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "supplier",
+			"action":  "create_supplier",
+			"error":   err.Error(),
+		}).Error("Failed to create supplier")
+		return err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":     "supplier",
+		"action":      "create_supplier",
+		"supplier_id": supplier.ID,
+	}).Info("Successfully created supplier")
+
+	return nil
+}
+
+func (uc *supplierUseCase) UpdateSupplier(ctx context.Context, supplier *models.Supplier) error {
+	if supplier.ID <= 0 {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "supplier",
+			"action":  "update_supplier",
+			"id":      supplier.ID,
+		}).Warn("Invalid supplier ID")
+		return models.NewValidationError("id", "invalid supplier ID")
+	}
+
+	if err := normalizeSupplier(supplier); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "supplier",
+			"action":  "update_supplier",
+			"id":      supplier.ID,
+			"error":   err.Error(),
+		}).Warn("Invalid supplier")
+		return err
+	}
+
+	if err := uc.supplierRepo.UpdateSupplier(ctx, supplier); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "supplier",
+			"action":  "update_supplier",
+			"id":      supplier.ID,
+			"error":   err.Error(),
+		}).Error("Failed to update supplier")
+		return err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "supplier",
+		"action":  "update_supplier",
+		"id":      supplier.ID,
+	}).Info("Successfully updated supplier")
+
+	return nil
+}
+
+func (uc *supplierUseCase) DeleteSupplier(ctx context.Context, id int) error {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "supplier",
+		"action":  "delete_supplier",
+		"id":      id,
+	}).Info("Executing delete supplier use case")
+
+	if id <= 0 {
+		return models.NewValidationError("id", "invalid supplier ID")
+	}
+
+	if err := uc.supplierRepo.DeleteSupplier(ctx, id); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "supplier",
+			"action":  "delete_supplier",
+			"id":      id,
+			"error":   err.Error(),
+		}).Error("Failed to delete supplier")
+		return err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "supplier",
+		"action":  "delete_supplier",
+		"id":      id,
+	}).Info("Successfully deleted supplier")
+
+	return nil
+}
+
+// normalizeSupplier merapikan spasi dan memvalidasi panjang field sesuai
+// kolom di database
+func normalizeSupplier(s *models.Supplier) error {
+	s.Name = strings.TrimSpace(s.Name)
+	s.Phone = strings.TrimSpace(s.Phone)
+	s.Email = strings.TrimSpace(s.Email)
+	s.Address = strings.TrimSpace(s.Address)
+	s.Note = strings.TrimSpace(s.Note)
+
+	if s.Name == "" {
+		return models.NewValidationError("name", "supplier name is required")
+	}
+	if len(s.Name) > 100 {
+		return models.NewValidationError("name", "supplier name must be at most 100 characters")
+	}
+	if len(s.Phone) > 30 {
+		return models.NewValidationError("phone", "phone must be at most 30 characters")
+	}
+	if len(s.Email) > 100 {
+		return models.NewValidationError("email", "email must be at most 100 characters")
+	}
+	if s.Email != "" && !strings.Contains(s.Email, "@") {
+		return models.NewValidationError("email", "invalid email address")
+	}
+	if len(s.Note) > 500 {
+		return models.NewValidationError("note", "note must be at most 500 characters")
+	}
+	return nil
+}
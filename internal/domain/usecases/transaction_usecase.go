@@ -0,0 +1,523 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/domain/pricing"
+	"kasir-api/internal/domain/repositories"
+	"kasir-api/internal/pkg"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TransactionUseCase adalah interface untuk transaction use cases
+type TransactionUseCase interface {
+	Checkout(ctx context.Context, req *models.CheckoutRequest) (*models.Transaction, error)
+	// PreviewCheckout menghitung harga dan promo keranjang tanpa menyimpan apapun
+	PreviewCheckout(ctx context.Context, req *models.CheckoutRequest) (*models.Transaction, error)
+	GetTransactionByID(ctx context.Context, id int) (*models.Transaction, error)
+}
+
+type transactionUseCase struct {
+	transactionRepo repositories.TransactionRepository
+	productRepo     repositories.ProductRepository
+	promotionRepo   repositories.PromotionRepository
+	orderRepo       repositories.OrderRepository
+	location        *time.Location
+	tax             pricing.TaxSettings
+}
+
+// NewTransactionUseCase membuat instance baru dari TransactionUseCase.
+// location adalah zona waktu toko untuk mengevaluasi jam berlaku promo,
+// tax adalah pengaturan pajak dan service charge toko.
+func NewTransactionUseCase(transactionRepo repositories.TransactionRepository, productRepo repositories.ProductRepository,
+	promotionRepo repositories.PromotionRepository, orderRepo repositories.OrderRepository, location *time.Location,
+	tax pricing.TaxSettings) TransactionUseCase {
+	return &transactionUseCase{
+		transactionRepo: transactionRepo,
+		productRepo:     productRepo,
+		promotionRepo:   promotionRepo,
+		orderRepo:       orderRepo,
+		location:        location,
+		tax:             tax,
+	}
+}
+
+// Checkout memvalidasi keranjang, menghitung harga beserta promo, lalu menyimpan penjualan
+func (uc *transactionUseCase) Checkout(ctx context.Context, req *models.CheckoutRequest) (*models.Transaction, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "transaction",
+		"action":  "checkout",
+		"items":   len(req.Items),
+	}).Info("Executing checkout use case")
+
+	items, promoCode, err := uc.resolveCart(ctx, req)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "transaction",
+			"action":  "checkout",
+			"error":   err.Error(),
+		}).Warn("Invalid checkout items")
+		return nil, err
+	}
+
+	payments, err := buildPayments(req.Payments)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "transaction",
+			"action":  "checkout",
+			"error":   err.Error(),
+		}).Warn("Invalid payments")
+		return nil, err
+	}
+
+	user, ok := pkg.AuthUserFromContext(ctx)
+	if !ok {
+		return nil, models.NewUnauthorizedError("authenticated user is required for checkout")
+	}
+
+	transaction, err := uc.priceCart(ctx, items, promoCode)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "transaction",
+			"action":  "checkout",
+			"error":   err.Error(),
+		}).Warn("Failed to price cart")
+		pkg.CheckoutsFailedTotal.WithLabelValues(checkoutFailureReason(err)).Inc()
+		return nil, err
+	}
+	transaction.OrderID = req.OrderID
+
+	paymentTotal := 0
+	for _, p := range payments {
+		paymentTotal += p.Amount
+	}
+	if paymentTotal != transaction.TotalAmount {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":       "transaction",
+			"action":        "checkout",
+			"payment_total": paymentTotal,
+			"total_amount":  transaction.TotalAmount,
+		}).Warn("Payments do not match transaction total")
+		pkg.CheckoutsFailedTotal.WithLabelValues("payment_mismatch").Inc()
+		return nil, models.NewValidationError("payments", fmt.Sprintf("payments total %d does not match transaction total %d", paymentTotal, transaction.TotalAmount))
+	}
+	transaction.Payments = payments
+
+	if err := uc.transactionRepo.CreateTransaction(ctx, user.ID, transaction); err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "transaction",
+			"action":  "checkout",
+			"error":   err.Error(),
+		}).Error("Failed to checkout")
+		pkg.CheckoutsFailedTotal.WithLabelValues(checkoutFailureReason(err)).Inc()
+		return nil, err
+	}
+
+	pkg.CheckoutsCompletedTotal.Inc()
+	pkg.CheckoutRevenueTotal.Add(float64(transaction.TotalAmount))
+	pkg.CheckoutTaxTotal.Add(float64(transaction.TaxAmount))
+	for _, item := range transaction.Details {
+		pkg.CheckoutItemsTotal.Add(float64(item.Quantity) / models.QuantityScale)
+	}
+	for _, payment := range transaction.Payments {
+		pkg.PaymentsTotal.WithLabelValues(payment.Method).Inc()
+		pkg.PaymentAmountTotal.WithLabelValues(payment.Method).Add(float64(payment.Amount))
+	}
+	for _, promo := range transaction.Promotions {
+		pkg.PromotionDiscountTotal.WithLabelValues(strconv.Itoa(promo.PromotionID)).Add(float64(promo.DiscountAmount))
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":         "transaction",
+		"action":          "checkout",
+		"transaction_id":  transaction.ID,
+		"total_amount":    transaction.TotalAmount,
+		"discount_amount": transaction.DiscountAmount,
+		"tax_amount":      transaction.TaxAmount,
+	}).Info("Successfully checked out")
+
+	return transaction, nil
+}
+
+// PreviewCheckout menghitung harga keranjang supaya kasir bisa melihat total
+// dan promo yang berlaku sebelum menerima pembayaran
+func (uc *transactionUseCase) PreviewCheckout(ctx context.Context, req *models.CheckoutRequest) (*models.Transaction, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase": "transaction",
+		"action":  "preview_checkout",
+		"items":   len(req.Items),
+	}).Info("Executing preview checkout use case")
+
+	items, promoCode, err := uc.resolveCart(ctx, req)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "transaction",
+			"action":  "preview_checkout",
+			"error":   err.Error(),
+		}).Warn("Invalid checkout items")
+		return nil, err
+	}
+
+	transaction, err := uc.priceCart(ctx, items, promoCode)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase": "transaction",
+			"action":  "preview_checkout",
+			"error":   err.Error(),
+		}).Warn("Failed to price cart")
+		return nil, err
+	}
+	transaction.OrderID = req.OrderID
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":         "transaction",
+		"action":          "preview_checkout",
+		"total_amount":    transaction.TotalAmount,
+		"discount_amount": transaction.DiscountAmount,
+	}).Info("Successfully priced cart")
+
+	return transaction, nil
+}
+
+// resolveCart mengambil item dan kode promo dari request, atau dari order
+// draft jika request menyebut order_id
+func (uc *transactionUseCase) resolveCart(ctx context.Context, req *models.CheckoutRequest) ([]models.CheckoutItem, string, error) {
+	if req.OrderID == nil {
+		converted, err := toBaseUnits(ctx, uc.productRepo, req.Items)
+		if err != nil {
+			return nil, "", err
+		}
+		items, err := mergeCheckoutItems(converted)
+		return items, req.PromoCode, err
+	}
+
+	if len(req.Items) > 0 {
+		return nil, "", models.NewValidationError("items", "items must be empty when order_id is set")
+	}
+	order, err := uc.orderRepo.GetOrderByID(ctx, *req.OrderID)
+	if err != nil {
+		return nil, "", err
+	}
+	if order.Status != models.OrderStatusDraft {
+		return nil, "", models.NewConflictError(fmt.Sprintf("order is %s, only draft orders can be checked out", order.Status))
+	}
+
+	reqItems := make([]models.CheckoutItem, 0, len(order.Items))
+	for _, item := range order.Items {
+		reqItems = append(reqItems, models.CheckoutItem{ProductID: item.ProductID, VariantID: item.VariantID, Quantity: item.Quantity})
+	}
+	items, err := mergeCheckoutItems(reqItems)
+	if err != nil {
+		return nil, "", err
+	}
+
+	promoCode := req.PromoCode
+	if promoCode == "" {
+		promoCode = order.PromoCode
+	}
+	return items, promoCode, nil
+}
+
+// mergeCheckoutItems memvalidasi item, menggabungkan baris dengan produk dan
+// varian yang sama lalu mengurutkannya berdasarkan product ID dan variant ID
+// supaya urutan lock selalu sama dan tidak deadlock
+func mergeCheckoutItems(reqItems []models.CheckoutItem) ([]models.CheckoutItem, error) {
+	if len(reqItems) == 0 {
+		return nil, models.NewValidationError("items", "checkout items are required")
+	}
+
+	type lineKey struct{ productID, variantID int }
+	quantities := make(map[lineKey]models.Quantity)
+	for _, item := range reqItems {
+		if item.ProductID <= 0 {
+			return nil, models.NewValidationError("product_id", "invalid product ID")
+		}
+		if item.VariantID != nil && *item.VariantID <= 0 {
+			return nil, models.NewValidationError("variant_id", "invalid variant ID")
+		}
+		if item.Quantity <= 0 {
+			return nil, models.NewValidationError("quantity", "quantity must be greater than zero")
+		}
+		quantities[lineKey{item.ProductID, variantKey(item.VariantID)}] += item.Quantity
+	}
+
+	items := make([]models.CheckoutItem, 0, len(quantities))
+	for key, quantity := range quantities {
+		item := models.CheckoutItem{ProductID: key.productID, Quantity: quantity}
+		if key.variantID != 0 {
+			variantID := key.variantID
+			item.VariantID = &variantID
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].ProductID != items[j].ProductID {
+			return items[i].ProductID < items[j].ProductID
+		}
+		return variantKey(items[i].VariantID) < variantKey(items[j].VariantID)
+	})
+	return items, nil
+}
+
+// toBaseUnits mengonversi quantity item yang menyebut satuan ke satuan dasar
+// produk, sehingga baris yang sama dalam satuan berbeda bisa digabung dan
+// stok selalu berkurang dalam satuan dasar
+func toBaseUnits(ctx context.Context, productRepo repositories.ProductRepository, items []models.CheckoutItem) ([]models.CheckoutItem, error) {
+	converted := make([]models.CheckoutItem, 0, len(items))
+	for _, item := range items {
+		if strings.TrimSpace(item.Unit) != "" {
+			product, err := productRepo.GetProductByID(ctx, item.ProductID)
+			if errors.Is(err, models.ErrNotFound) {
+				return nil, models.NewNotFoundError(fmt.Sprintf("product %d", item.ProductID))
+			}
+			if err != nil {
+				return nil, err
+			}
+			factor, err := product.UnitFactor(item.Unit)
+			if err != nil {
+				return nil, err
+			}
+			item.Quantity *= models.Quantity(factor)
+			item.Unit = ""
+		}
+		converted = append(converted, item)
+	}
+	return converted, nil
+}
+
+// variantKey memetakan variant ID opsional ke int, 0 untuk baris tanpa varian
+func variantKey(variantID *int) int {
+	if variantID == nil {
+		return 0
+	}
+	return *variantID
+}
+
+// validateQuantity memastikan barang yang bukan barang timbang hanya dijual
+// per unit utuh
+func validateQuantity(product *models.Product, quantity models.Quantity) error {
+	if !product.Weighable && !quantity.IsWhole() {
+		return models.NewValidationError("quantity", fmt.Sprintf("%s is sold per whole unit, quantity %s is not allowed", product.Name, quantity))
+	}
+	return nil
+}
+
+// resolveVariant memilih varian yang dijual untuk baris keranjang. Produk
+// bervarian wajib dijual per varian aktif, produk tanpa varian tidak boleh
+// menyebut variant_id.
+func resolveVariant(product *models.Product, variantID *int) (*models.ProductVariant, error) {
+	if len(product.Variants) == 0 {
+		if variantID != nil {
+			return nil, models.NewValidationError("variant_id", fmt.Sprintf("%s has no variants", product.Name))
+		}
+		return nil, nil
+	}
+	if variantID == nil {
+		return nil, models.NewValidationError("variant_id", fmt.Sprintf("%s has variants, variant_id is required", product.Name))
+	}
+	for i := range product.Variants {
+		variant := &product.Variants[i]
+		if variant.ID != *variantID {
+			continue
+		}
+		if !variant.Active {
+			return nil, models.NewValidationError("variant_id", fmt.Sprintf("variant %s of %s is no longer sold", variant.Name, product.Name))
+		}
+		return variant, nil
+	}
+	return nil, models.NewNotFoundError(fmt.Sprintf("variant %d of product %d", *variantID, product.ID))
+}
+
+// priceCart mengambil harga produk saat ini dan promo aktif, lalu menghitung
+// harga, diskon, service charge dan pajak keranjang lewat package pricing. Repository akan mengecek ulang
+// harga saat baris produk dikunci.
+func (uc *transactionUseCase) priceCart(ctx context.Context, items []models.CheckoutItem, promoCode string) (*models.Transaction, error) {
+	cart := pricing.Cart{
+		Lines:     make([]pricing.Line, 0, len(items)),
+		PromoCode: strings.TrimSpace(promoCode),
+		Now:       time.Now().In(uc.location),
+	}
+
+	for _, item := range items {
+		product, err := uc.productRepo.GetProductByID(ctx, item.ProductID)
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, models.NewNotFoundError(fmt.Sprintf("product %d", item.ProductID))
+		}
+		if err != nil {
+			return nil, err
+		}
+		variant, err := resolveVariant(product, item.VariantID)
+		if err != nil {
+			return nil, err
+		}
+		if err := validateQuantity(product, item.Quantity); err != nil {
+			return nil, err
+		}
+		var categoryTaxRate *int
+		if product.Category != nil {
+			categoryTaxRate = product.Category.TaxRateBps
+		}
+		line := pricing.Line{
+			ProductID:   product.ID,
+			ProductName: product.Name,
+			CategoryID:  product.CategoryID,
+			Quantity:    item.Quantity,
+			UnitPrice:   product.Price,
+			TaxRateBps:  pricing.ResolveTaxRate(uc.tax.DefaultRateBps, product.TaxRateBps, categoryTaxRate, product.TaxExempt),
+		}
+		if variant != nil {
+			line.VariantID = &variant.ID
+			line.VariantName = variant.Name
+			line.UnitPrice = variant.Price
+		}
+		cart.Lines = append(cart.Lines, line)
+	}
+
+	promotions, err := uc.promotionRepo.GetActivePromotions(ctx, cart.Now)
+	if err != nil {
+		return nil, err
+	}
+
+	result := pricing.Calculate(cart, promotions, uc.tax)
+	if cart.PromoCode != "" && !result.CodeApplied {
+		return nil, models.NewValidationError("promo_code", "promo code is invalid or not applicable to this cart")
+	}
+
+	transaction := &models.Transaction{
+		SubtotalAmount:      result.Subtotal,
+		DiscountAmount:      result.DiscountAmount,
+		ServiceChargeAmount: result.ServiceCharge,
+		TaxAmount:           result.TaxAmount,
+		PricesIncludeTax:    result.PricesIncludeTax,
+		TotalAmount:         result.Total,
+		Details:             make([]models.TransactionItem, 0, len(result.Lines)),
+		Payments:            make([]models.Payment, 0),
+		Promotions:          result.Applied,
+		Taxes:               result.Taxes,
+	}
+	for _, line := range result.Lines {
+		transaction.Details = append(transaction.Details, models.TransactionItem{
+			ProductID:           line.ProductID,
+			ProductName:         line.ProductName,
+			VariantID:           line.VariantID,
+			VariantName:         line.VariantName,
+			Quantity:            line.Quantity,
+			Price:               line.UnitPrice,
+			Discount:            line.Discount,
+			Subtotal:            line.Net,
+			TaxRateBps:          line.TaxRateBps,
+			ServiceChargeAmount: line.ServiceCharge,
+			TaxAmount:           line.TaxAmount,
+			TotalAmount:         line.Total,
+		})
+	}
+
+	return transaction, nil
+}
+
+// buildPayments memvalidasi pembayaran dari kasir dan menghitung kembalian
+func buildPayments(reqs []models.PaymentRequest) ([]models.Payment, error) {
+	if len(reqs) == 0 {
+		return nil, models.NewValidationError("payments", "at least one payment is required")
+	}
+
+	payments := make([]models.Payment, 0, len(reqs))
+	for _, req := range reqs {
+		if req.Amount <= 0 {
+			return nil, models.NewValidationError("amount", "payment amount must be greater than zero")
+		}
+		if len(req.Reference) > 100 {
+			return nil, models.NewValidationError("reference", "reference must be at most 100 characters")
+		}
+
+		payment := models.Payment{
+			Method:    req.Method,
+			Amount:    req.Amount,
+			Reference: req.Reference,
+		}
+
+		switch req.Method {
+		case models.PaymentMethodCash:
+			// Tanpa tendered amount berarti uang pas
+			tendered := req.Amount
+			if req.TenderedAmount != nil {
+				tendered = *req.TenderedAmount
+			}
+			if tendered < req.Amount {
+				return nil, models.NewValidationError("tendered_amount", "tendered amount must be at least the cash amount")
+			}
+			payment.TenderedAmount = &tendered
+			payment.ChangeAmount = tendered - req.Amount
+		case models.PaymentMethodDebitCard, models.PaymentMethodCreditCard, models.PaymentMethodEWallet, models.PaymentMethodQRIS:
+			if req.TenderedAmount != nil {
+				return nil, models.NewValidationError("tendered_amount", "tendered amount is only allowed for cash payments")
+			}
+		default:
+			return nil, models.NewValidationError("method", "method must be one of cash, debit_card, credit_card, ewallet, qris")
+		}
+
+		payments = append(payments, payment)
+	}
+
+	return payments, nil
+}
+
+// checkoutFailureReason mengelompokkan error checkout untuk label metrics
+func checkoutFailureReason(err error) string {
+	switch {
+	case errors.Is(err, models.ErrConflict):
+		return "conflict"
+	case errors.Is(err, models.ErrNotFound):
+		return "product_not_found"
+	case errors.Is(err, models.ErrValidation):
+		return "validation"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	default:
+		return "internal"
+	}
+}
+
+// GetTransactionByID mengembalikan transaksi beserta rincian item dan pembayaran
+func (uc *transactionUseCase) GetTransactionByID(ctx context.Context, id int) (*models.Transaction, error) {
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":        "transaction",
+		"action":         "get_transaction_by_id",
+		"transaction_id": id,
+	}).Info("Executing get transaction by ID use case")
+
+	if id <= 0 {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":        "transaction",
+			"action":         "get_transaction_by_id",
+			"transaction_id": id,
+		}).Warn("Invalid transaction ID")
+		return nil, models.NewValidationError("id", "invalid transaction ID")
+	}
+
+	transaction, err := uc.transactionRepo.GetTransactionByID(ctx, id)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+			"usecase":        "transaction",
+			"action":         "get_transaction_by_id",
+			"transaction_id": id,
+			"error":          err.Error(),
+		}).Error("Failed to get transaction")
+		return nil, err
+	}
+
+	pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+		"usecase":        "transaction",
+		"action":         "get_transaction_by_id",
+		"transaction_id": id,
+	}).Info("Successfully retrieved transaction")
+
+	return transaction, nil
+}
@@ -0,0 +1,279 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/domain/pricing"
+	"kasir-api/internal/domain/repositories"
+	"kasir-api/internal/pkg"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	pkg.InitLogger()
+	pkg.Log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// fakeProductRepo menyimpan produk di memori. Method yang tidak dipakai test
+// jatuh ke interface embedded (nil) dan panic jika terpanggil.
+type fakeProductRepo struct {
+	repositories.ProductRepository
+	products map[int]*models.Product
+}
+
+func (f *fakeProductRepo) GetProductByID(ctx context.Context, id int) (*models.Product, error) {
+	product, ok := f.products[id]
+	if !ok {
+		return nil, models.NewNotFoundError("product")
+	}
+	copied := *product
+	return &copied, nil
+}
+
+type fakePromotionRepo struct {
+	repositories.PromotionRepository
+	promotions []models.Promotion
+}
+
+func (f *fakePromotionRepo) GetActivePromotions(ctx context.Context, now time.Time) ([]models.Promotion, error) {
+	return f.promotions, nil
+}
+
+// fakeTransactionRepo meniru CreateTransaction: harga dicek ulang terhadap
+// harga produk saat ini, stok dicek lalu dikurangi, semuanya atau tidak sama
+// sekali
+type fakeTransactionRepo struct {
+	repositories.TransactionRepository
+	products *fakeProductRepo
+	saved    []*models.Transaction
+}
+
+func (f *fakeTransactionRepo) CreateTransaction(ctx context.Context, userID int, transaction *models.Transaction) error {
+	for _, item := range transaction.Details {
+		product := f.products.products[item.ProductID]
+		price := product.Price
+		for _, variant := range product.Variants {
+			if item.VariantID != nil && variant.ID == *item.VariantID {
+				price = variant.Price
+			}
+		}
+		if price != item.Price {
+			return models.NewConflictError(fmt.Sprintf("price of %s has changed, please retry checkout", item.ProductName))
+		}
+		if product.Stock < item.Quantity {
+			return models.NewConflictError(fmt.Sprintf("insufficient stock for %s", item.ProductName))
+		}
+	}
+	for _, item := range transaction.Details {
+		f.products.products[item.ProductID].Stock -= item.Quantity
+	}
+	transaction.ID = len(f.saved) + 1
+	transaction.UserID = &userID
+	f.saved = append(f.saved, transaction)
+	return nil
+}
+
+// newFakeProducts membuat katalog dengan produk bersatuan tambahan (1),
+// produk biasa (2) dan produk bervarian (3)
+func newFakeProducts() *fakeProductRepo {
+	return &fakeProductRepo{products: map[int]*models.Product{
+		1: {ID: 1, Name: "Indomie Goreng", Price: 3500, Stock: models.WholeQuantity(100), BaseUnit: "pcs",
+			Units: []models.ProductUnit{{Name: "dus", Factor: 40}}},
+		2: {ID: 2, Name: "Teh Botol", Price: 5000, Stock: models.WholeQuantity(10), BaseUnit: "pcs"},
+		3: {ID: 3, Name: "Kaos", Price: 50000, Stock: models.WholeQuantity(5), BaseUnit: "pcs",
+			Variants: []models.ProductVariant{{ID: 31, Name: "M", Price: 55000, Active: true}, {ID: 32, Name: "XXL", Price: 60000}}},
+	}}
+}
+
+func newCheckoutFixture(t *testing.T) (TransactionUseCase, *fakeProductRepo, *fakeTransactionRepo) {
+	t.Helper()
+	products := newFakeProducts()
+	transactions := &fakeTransactionRepo{products: products}
+	uc := NewTransactionUseCase(transactions, products, &fakePromotionRepo{}, nil, time.UTC, pricing.TaxSettings{})
+	return uc, products, transactions
+}
+
+func cashierContext() context.Context {
+	return pkg.WithAuthUser(context.Background(), &models.AuthUser{ID: 7, Username: "kasir", Role: models.RoleCashier})
+}
+
+func cash(amount int, tendered *int) []models.PaymentRequest {
+	return []models.PaymentRequest{{Method: models.PaymentMethodCash, Amount: amount, TenderedAmount: tendered}}
+}
+
+func intPtr(v int) *int { return &v }
+
+func TestCheckoutDecrementsStock(t *testing.T) {
+	uc, products, transactions := newCheckoutFixture(t)
+
+	req := &models.CheckoutRequest{
+		Items:    []models.CheckoutItem{{ProductID: 2, Quantity: models.WholeQuantity(3)}},
+		Payments: cash(15000, nil),
+	}
+	transaction, err := uc.Checkout(cashierContext(), req)
+	if err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+	if transaction.TotalAmount != 15000 || len(transactions.saved) != 1 {
+		t.Fatalf("total = %d, saved = %d, want 15000, 1", transaction.TotalAmount, len(transactions.saved))
+	}
+	if got := products.products[2].Stock; got != models.WholeQuantity(7) {
+		t.Errorf("stock = %s, want 7", got)
+	}
+	if transaction.UserID == nil || *transaction.UserID != 7 {
+		t.Errorf("user id = %v, want 7", transaction.UserID)
+	}
+
+	// sisa stok 7, checkout 8 harus ditolak tanpa mengubah stok
+	req.Items[0].Quantity = models.WholeQuantity(8)
+	req.Payments = cash(40000, nil)
+	_, err = uc.Checkout(cashierContext(), req)
+	if !errors.Is(err, models.ErrConflict) {
+		t.Fatalf("Checkout() error = %v, want conflict", err)
+	}
+	if got := products.products[2].Stock; got != models.WholeQuantity(7) {
+		t.Errorf("stock after rejected checkout = %s, want 7", got)
+	}
+}
+
+func TestCheckoutMergesAndSortsLines(t *testing.T) {
+	uc, _, transactions := newCheckoutFixture(t)
+
+	// baris produk yang sama digabung (termasuk satuan dus = 40 pcs) dan
+	// diurutkan berdasarkan product ID lalu variant ID supaya urutan lock
+	// di repository selalu sama
+	req := &models.CheckoutRequest{
+		Items: []models.CheckoutItem{
+			{ProductID: 2, Quantity: models.WholeQuantity(1)},
+			{ProductID: 1, Quantity: models.WholeQuantity(2)},
+			{ProductID: 3, VariantID: intPtr(31), Quantity: models.WholeQuantity(1)},
+			{ProductID: 2, Quantity: models.WholeQuantity(2)},
+			{ProductID: 1, Quantity: models.WholeQuantity(1), Unit: "dus"},
+		},
+		Payments: cash(3500*42+5000*3+55000, nil),
+	}
+	transaction, err := uc.Checkout(cashierContext(), req)
+	if err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+
+	want := []struct {
+		productID int
+		variantID int
+		quantity  models.Quantity
+		price     int
+	}{
+		{1, 0, models.WholeQuantity(42), 3500},
+		{2, 0, models.WholeQuantity(3), 5000},
+		{3, 31, models.WholeQuantity(1), 55000},
+	}
+	details := transactions.saved[0].Details
+	if len(details) != len(want) {
+		t.Fatalf("details = %d lines, want %d", len(details), len(want))
+	}
+	for i, w := range want {
+		d := details[i]
+		if d.ProductID != w.productID || variantKey(d.VariantID) != w.variantID || d.Quantity != w.quantity || d.Price != w.price {
+			t.Errorf("line %d = product %d variant %d qty %s price %d, want product %d variant %d qty %s price %d",
+				i, d.ProductID, variantKey(d.VariantID), d.Quantity, d.Price, w.productID, w.variantID, w.quantity, w.price)
+		}
+	}
+	if transaction.TotalAmount != 3500*42+5000*3+55000 {
+		t.Errorf("total = %d", transaction.TotalAmount)
+	}
+}
+
+func TestCheckoutRejectsChangedPrice(t *testing.T) {
+	uc, products, transactions := newCheckoutFixture(t)
+
+	// harga berubah setelah keranjang dihitung: repository menolak dengan
+	// conflict dan stok tidak berkurang
+	transactions.products = &fakeProductRepo{products: map[int]*models.Product{
+		2: {ID: 2, Name: "Teh Botol", Price: 5500, Stock: models.WholeQuantity(10)},
+	}}
+	req := &models.CheckoutRequest{
+		Items:    []models.CheckoutItem{{ProductID: 2, Quantity: models.WholeQuantity(2)}},
+		Payments: cash(10000, nil),
+	}
+	_, err := uc.Checkout(cashierContext(), req)
+	if !errors.Is(err, models.ErrConflict) {
+		t.Fatalf("Checkout() error = %v, want conflict", err)
+	}
+	if got := products.products[2].Stock; got != models.WholeQuantity(10) {
+		t.Errorf("stock = %s, want 10", got)
+	}
+}
+
+func TestCheckoutCashChange(t *testing.T) {
+	uc, _, _ := newCheckoutFixture(t)
+
+	req := &models.CheckoutRequest{
+		Items: []models.CheckoutItem{{ProductID: 2, Quantity: models.WholeQuantity(3)}},
+		Payments: []models.PaymentRequest{
+			{Method: models.PaymentMethodQRIS, Amount: 5000, Reference: "QR-1"},
+			{Method: models.PaymentMethodCash, Amount: 10000, TenderedAmount: intPtr(20000)},
+		},
+	}
+	transaction, err := uc.Checkout(cashierContext(), req)
+	if err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+	cashPayment := transaction.Payments[1]
+	if cashPayment.TenderedAmount == nil || *cashPayment.TenderedAmount != 20000 || cashPayment.ChangeAmount != 10000 {
+		t.Errorf("cash payment = tendered %v change %d, want 20000, 10000", cashPayment.TenderedAmount, cashPayment.ChangeAmount)
+	}
+	if transaction.Payments[0].ChangeAmount != 0 {
+		t.Errorf("qris change = %d, want 0", transaction.Payments[0].ChangeAmount)
+	}
+}
+
+func TestCheckoutValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		req  models.CheckoutRequest
+		want error
+	}{
+		{name: "empty cart", ctx: cashierContext(), req: models.CheckoutRequest{Payments: cash(1000, nil)}, want: models.ErrValidation},
+		{name: "zero quantity", ctx: cashierContext(), want: models.ErrValidation, req: models.CheckoutRequest{
+			Items: []models.CheckoutItem{{ProductID: 2}}, Payments: cash(1000, nil)}},
+		{name: "fractional quantity of non weighable product", ctx: cashierContext(), want: models.ErrValidation,
+			req: models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: 2, Quantity: 1500}}, Payments: cash(7500, nil)}},
+		{name: "payments do not match total", ctx: cashierContext(), want: models.ErrValidation, req: models.CheckoutRequest{
+			Items: []models.CheckoutItem{{ProductID: 2, Quantity: models.WholeQuantity(1)}}, Payments: cash(4000, nil)}},
+		{name: "tendered less than cash amount", ctx: cashierContext(), want: models.ErrValidation, req: models.CheckoutRequest{
+			Items: []models.CheckoutItem{{ProductID: 2, Quantity: models.WholeQuantity(1)}}, Payments: cash(5000, intPtr(2000))}},
+		{name: "tendered on card payment", ctx: cashierContext(), want: models.ErrValidation, req: models.CheckoutRequest{
+			Items:    []models.CheckoutItem{{ProductID: 2, Quantity: models.WholeQuantity(1)}},
+			Payments: []models.PaymentRequest{{Method: models.PaymentMethodDebitCard, Amount: 5000, TenderedAmount: intPtr(5000)}}}},
+		{name: "variant required", ctx: cashierContext(), want: models.ErrValidation, req: models.CheckoutRequest{
+			Items: []models.CheckoutItem{{ProductID: 3, Quantity: models.WholeQuantity(1)}}, Payments: cash(50000, nil)}},
+		{name: "inactive variant", ctx: cashierContext(), want: models.ErrValidation, req: models.CheckoutRequest{
+			Items: []models.CheckoutItem{{ProductID: 3, VariantID: intPtr(32), Quantity: models.WholeQuantity(1)}}, Payments: cash(60000, nil)}},
+		{name: "unknown product", ctx: cashierContext(), want: models.ErrNotFound, req: models.CheckoutRequest{
+			Items: []models.CheckoutItem{{ProductID: 99, Quantity: models.WholeQuantity(1)}}, Payments: cash(1000, nil)}},
+		{name: "unknown promo code", ctx: cashierContext(), want: models.ErrValidation, req: models.CheckoutRequest{
+			Items: []models.CheckoutItem{{ProductID: 2, Quantity: models.WholeQuantity(1)}}, Payments: cash(5000, nil), PromoCode: "HEMAT"}},
+		{name: "no authenticated user", ctx: context.Background(), want: models.ErrUnauthorized, req: models.CheckoutRequest{
+			Items: []models.CheckoutItem{{ProductID: 2, Quantity: models.WholeQuantity(1)}}, Payments: cash(5000, nil)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, products, transactions := newCheckoutFixture(t)
+			_, err := uc.Checkout(tt.ctx, &tt.req)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Checkout() error = %v, want %v", err, tt.want)
+			}
+			if len(transactions.saved) != 0 || products.products[2].Stock != models.WholeQuantity(10) {
+				t.Errorf("rejected checkout must not save or move stock")
+			}
+		})
+	}
+}
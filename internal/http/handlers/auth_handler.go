@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/domain/usecases"
+	"kasir-api/internal/pkg"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+type AuthHandler struct {
+	authUseCase usecases.AuthUseCase
+}
+
+func NewAuthHandler(authUseCase usecases.AuthUseCase) *AuthHandler {
+	return &AuthHandler{authUseCase: authUseCase}
+}
+
+// @Summary Login
+// @Description Login dengan username & password, mengembalikan access dan refresh token
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body models.LoginRequest true "Login Request"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/auth/login [post]
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler": "auth_handler",
+		"action":  "login",
+		"method":  r.Method,
+	}).Info("Login handler called")
+
+	var req models.LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "auth_handler",
+			"action":  "login",
+			"error":   err.Error(),
+		}).Warn("Invalid request body")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid request", nil)
+		return
+	}
+
+	token, err := h.authUseCase.Login(r.Context(), &req)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "auth_handler",
+			"action":  "login",
+			"error":   err.Error(),
+		}).Warn("Login failed")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler": "auth_handler",
+		"action":  "login",
+		"user_id": token.User.ID,
+	}).Info("Login successful")
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Login successful", token)
+}
+
+// @Summary Refresh Token
+// @Description Tukar refresh token dengan access token baru
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body models.RefreshRequest true "Refresh Request"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/auth/refresh [post]
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler": "auth_handler",
+		"action":  "refresh",
+		"method":  r.Method,
+	}).Info("Refresh token handler called")
+
+	var req models.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "auth_handler",
+			"action":  "refresh",
+			"error":   err.Error(),
+		}).Warn("Invalid request body")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid request", nil)
+		return
+	}
+
+	token, err := h.authUseCase.Refresh(r.Context(), &req)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "auth_handler",
+			"action":  "refresh",
+			"error":   err.Error(),
+		}).Warn("Refresh token failed")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler": "auth_handler",
+		"action":  "refresh",
+		"user_id": token.User.ID,
+	}).Info("Token refreshed")
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Token refreshed", token)
+}
+
+// @Summary Create User
+// @Description Buat user kasir/admin baru (khusus admin)
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body models.CreateUserRequest true "Create User Request"
+// @Success 201 {object} pkg.ResponsePayload
+// @Router /api/users [post]
+func (h *AuthHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler": "auth_handler",
+		"action":  "create_user",
+		"method":  r.Method,
+	}).Info("Create user handler called")
+
+	var req models.CreateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "auth_handler",
+			"action":  "create_user",
+			"error":   err.Error(),
+		}).Warn("Invalid request body")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid request", nil)
+		return
+	}
+
+	user, err := h.authUseCase.CreateUser(r.Context(), &req)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":  "auth_handler",
+			"action":   "create_user",
+			"username": req.Username,
+			"error":    err.Error(),
+		}).Error("Failed to create user")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler": "auth_handler",
+		"action":  "create_user",
+		"user_id": user.ID,
+	}).Info("User created successfully")
+
+	pkg.ResponseSuccess(w, http.StatusCreated, "User created successfully", user)
+}
+
+func (h *AuthHandler) HandleLogin(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.Login(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *AuthHandler) HandleRefresh(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.Refresh(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *AuthHandler) HandleUsers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.CreateUser(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"kasir-api/internal/domain/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type fakeAuthUseCase struct {
+	err    error
+	called bool
+}
+
+func (f *fakeAuthUseCase) token() (*models.AuthToken, error) {
+	f.called = true
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &models.AuthToken{AccessToken: "a", RefreshToken: "r", TokenType: "Bearer", User: &models.User{ID: 7, Username: "kasir"}}, nil
+}
+
+func (f *fakeAuthUseCase) Login(ctx context.Context, req *models.LoginRequest) (*models.AuthToken, error) {
+	return f.token()
+}
+
+func (f *fakeAuthUseCase) Refresh(ctx context.Context, req *models.RefreshRequest) (*models.AuthToken, error) {
+	return f.token()
+}
+
+func (f *fakeAuthUseCase) CreateUser(ctx context.Context, req *models.CreateUserRequest) (*models.User, error) {
+	f.called = true
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &models.User{ID: 2, Username: req.Username, Role: req.Role}, nil
+}
+
+func (f *fakeAuthUseCase) EnsureAdmin(ctx context.Context, username, password string) error {
+	return f.err
+}
+
+func (f *fakeAuthUseCase) VerifySupervisor(ctx context.Context, username, password string) (*models.User, error) {
+	return nil, f.err
+}
+
+func TestLoginStatusCodes(t *testing.T) {
+	body := `{"username":"kasir","password":"rahasia123"}`
+	tests := []struct {
+		name   string
+		body   string
+		err    error
+		code   int
+		called bool
+	}{
+		{name: "success", body: body, code: http.StatusOK, called: true},
+		{name: "malformed body", body: `{"username":`, code: http.StatusBadRequest},
+		{name: "missing password", body: `{"username":"kasir"}`, err: models.NewValidationError("username", "username and password are required"),
+			code: http.StatusBadRequest, called: true},
+		{name: "invalid credentials", body: body, err: models.NewUnauthorizedError("invalid username or password"),
+			code: http.StatusUnauthorized, called: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &fakeAuthUseCase{err: tt.err}
+			h := NewAuthHandler(uc)
+
+			rec := httptest.NewRecorder()
+			h.Login(rec, httptest.NewRequest(http.MethodPost, "/api/auth/login", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.code {
+				t.Errorf("status = %d, want %d (body %s)", rec.Code, tt.code, rec.Body.String())
+			}
+			if uc.called != tt.called {
+				t.Errorf("use case called = %v, want %v", uc.called, tt.called)
+			}
+		})
+	}
+}
+
+func TestCreateUserStatusCodes(t *testing.T) {
+	body := `{"username":"budi","password":"rahasia123","role":"cashier"}`
+	tests := []struct {
+		name string
+		body string
+		err  error
+		code int
+	}{
+		{name: "success", body: body, code: http.StatusCreated},
+		{name: "malformed body", body: `[`, code: http.StatusBadRequest},
+		{name: "short password", body: body, err: models.NewValidationError("password", "password must be at least 8 characters"),
+			code: http.StatusBadRequest},
+		{name: "duplicate username", body: body, err: models.NewConflictError("username already exists"), code: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewAuthHandler(&fakeAuthUseCase{err: tt.err})
+
+			rec := httptest.NewRecorder()
+			h.CreateUser(rec, httptest.NewRequest(http.MethodPost, "/api/users", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.code {
+				t.Errorf("status = %d, want %d (body %s)", rec.Code, tt.code, rec.Body.String())
+			}
+		})
+	}
+}
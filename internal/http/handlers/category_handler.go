@@ -28,24 +28,24 @@ func NewCategoryHandler(categoryUseCase usecases.CategoryUseCase) *CategoryHandl
 // @Success 200 {object} pkg.ResponsePayload
 // @Router /api/category [get]
 func (h *CategoryHandler) GetAllCategory(w http.ResponseWriter, r *http.Request) {
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 		"handler": "category_handler",
 		"action":  "get_all_categories",
 		"method":  r.Method,
 	}).Info("Get all categories handler called")
 
-	categories, err := h.categoryUseCase.GetAllCategory()
+	categories, err := h.categoryUseCase.GetAllCategory(r.Context())
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 			"handler": "category_handler",
 			"action":  "get_all_categories",
 			"error":   err.Error(),
 		}).Error("Failed to get categories")
-		pkg.ResponseError(w, http.StatusInternalServerError, "Failed to get categories", nil)
+		pkg.ResponseFromError(w, err)
 		return
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 		"handler": "category_handler",
 		"action":  "get_all_categories",
 		"count":   len(categories),
@@ -63,7 +63,7 @@ func (h *CategoryHandler) GetAllCategory(w http.ResponseWriter, r *http.Request)
 // @Success 200 {object} pkg.ResponsePayload
 // @Router /api/category [post]
 func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 		"handler": "category_handler",
 		"action":  "create_category",
 		"method":  r.Method,
@@ -72,7 +72,7 @@ func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request)
 	var newCategory models.Category
 	err := json.NewDecoder(r.Body).Decode(&newCategory)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 			"handler": "category_handler",
 			"action":  "create_category",
 			"error":   err.Error(),
@@ -81,26 +81,25 @@ func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err = h.categoryUseCase.CreateCategory(&newCategory)
+	err = h.categoryUseCase.CreateCategory(r.Context(), &newCategory)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 			"handler":       "category_handler",
 			"action":        "create_category",
 			"category_name": newCategory.Name,
 			"error":         err.Error(),
 		}).Error("Failed to create category")
-		pkg.ResponseError(w, http.StatusInternalServerError, err.Error(), nil)
+		pkg.ResponseFromError(w, err)
 		return
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 		"handler":       "category_handler",
 		"action":        "create_category",
 		"category_id":   newCategory.ID,
 		"category_name": newCategory.Name,
 	}).Info("Category created successfully")
 
-	w.WriteHeader(http.StatusCreated)
 	pkg.ResponseSuccess(w, http.StatusCreated, "Category created successfully", nil)
 }
 
@@ -119,7 +118,7 @@ func (h *CategoryHandler) UpdateCategory(w http.ResponseWriter, r *http.Request)
 	// ganti jadi int
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 			"handler": "category_handler",
 			"action":  "update_category",
 			"id_str":  idStr,
@@ -128,7 +127,7 @@ func (h *CategoryHandler) UpdateCategory(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 		"handler":     "category_handler",
 		"action":      "update_category",
 		"category_id": id,
@@ -138,7 +137,7 @@ func (h *CategoryHandler) UpdateCategory(w http.ResponseWriter, r *http.Request)
 	var updateCategory models.Category
 	err = json.NewDecoder(r.Body).Decode(&updateCategory)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 			"handler":     "category_handler",
 			"action":      "update_category",
 			"category_id": id,
@@ -149,20 +148,20 @@ func (h *CategoryHandler) UpdateCategory(w http.ResponseWriter, r *http.Request)
 	}
 
 	updateCategory.ID = id
-	err = h.categoryUseCase.UpdateCategory(&updateCategory)
+	err = h.categoryUseCase.UpdateCategory(r.Context(), &updateCategory)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 			"handler":       "category_handler",
 			"action":        "update_category",
 			"category_id":   id,
 			"category_name": updateCategory.Name,
 			"error":         err.Error(),
 		}).Error("Failed to update category")
-		pkg.ResponseError(w, http.StatusInternalServerError, err.Error(), nil)
+		pkg.ResponseFromError(w, err)
 		return
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 		"handler":       "category_handler",
 		"action":        "update_category",
 		"category_id":   id,
@@ -184,7 +183,7 @@ func (h *CategoryHandler) GetCategoryByID(w http.ResponseWriter, r *http.Request
 	idStr := strings.TrimPrefix(r.URL.Path, "/api/category/")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 			"handler":     "category_handler",
 			"action":      "get_category_by_id",
 			"category_id": id,
@@ -193,25 +192,25 @@ func (h *CategoryHandler) GetCategoryByID(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 		"handler":     "category_handler",
 		"action":      "get_category_by_id",
 		"category_id": id,
 	}).Info("Get category by ID handler called")
 
-	category, err := h.categoryUseCase.GetCategoryByID(id)
+	category, err := h.categoryUseCase.GetCategoryByID(r.Context(), id)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 			"handler":     "category_handler",
 			"action":      "get_category_by_id",
 			"category_id": id,
 			"error":       err.Error(),
 		}).Error("Failed to get category")
-		pkg.ResponseError(w, http.StatusNotFound, err.Error(), nil)
+		pkg.ResponseFromError(w, err)
 		return
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 		"handler":       "category_handler",
 		"action":        "get_category_by_id",
 		"category_id":   id,
@@ -234,7 +233,7 @@ func (h *CategoryHandler) DeleteCategory(w http.ResponseWriter, r *http.Request)
 	// ganti jadi int
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 			"handler": "category_handler",
 			"action":  "delete_category",
 			"id_str":  idStr,
@@ -243,25 +242,25 @@ func (h *CategoryHandler) DeleteCategory(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 		"handler":     "category_handler",
 		"action":      "delete_category",
 		"category_id": id,
 	}).Info("Delete category handler called")
 
-	err = h.categoryUseCase.DeleteCategory(id)
+	err = h.categoryUseCase.DeleteCategory(r.Context(), id)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 			"handler":     "category_handler",
 			"action":      "delete_category",
 			"category_id": id,
 			"error":       err.Error(),
 		}).Error("Failed to delete category")
-		pkg.ResponseError(w, http.StatusInternalServerError, err.Error(), nil)
+		pkg.ResponseFromError(w, err)
 		return
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 		"handler":     "category_handler",
 		"action":      "delete_category",
 		"category_id": id,
@@ -272,7 +271,7 @@ func (h *CategoryHandler) DeleteCategory(w http.ResponseWriter, r *http.Request)
 
 func (h *CategoryHandler) HandleCategory(w http.ResponseWriter, r *http.Request) {
 	// Debug tracing
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 		"handler": "category_handler",
 		"func":    "HandleCategory",
 		"method":  r.Method,
@@ -289,7 +288,7 @@ func (h *CategoryHandler) HandleCategory(w http.ResponseWriter, r *http.Request)
 }
 
 func (h *CategoryHandler) HandleCategoryByID(w http.ResponseWriter, r *http.Request) {
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 		"handler": "category_handler",
 		"func":    "GetAllCategory",
 		"method":  r.Method,
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"kasir-api/internal/domain/models"
 	"kasir-api/internal/domain/usecases"
 	"kasir-api/internal/pkg"
 	"net/http"
@@ -20,34 +21,34 @@ func NewHealthHandler(healthUseCase usecases.HealthUseCase) *HealthHandler {
 	}
 }
 
-// CheckHealth adalah handler untuk endpoint GET /api/health
-// @Summary Health Check
-// @Description Mengecek kesehatan aplikasi
+// CheckHealth adalah handler untuk endpoint GET /api/health dan GET /api/health/live
+// @Summary Liveness Check
+// @Description Mengecek proses aplikasi masih berjalan, tanpa mengecek dependency
 // @Tags Health
 // @Produce json
 // @Success 200 {object} models.HealthResponse
-// @Router /api/health [get]
+// @Router /api/health/live [get]
 func (h *HealthHandler) CheckHealth(w http.ResponseWriter, r *http.Request) {
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 		"handler": "health_handler",
 		"action":  "check_health",
 		"method":  r.Method,
 	}).Info("Health check handler called")
 
 	// Panggil use case
-	response, err := h.healthUseCase.CheckHealth()
+	response, err := h.healthUseCase.CheckHealth(r.Context())
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 			"handler": "health_handler",
 			"action":  "check_health",
 			"error":   err.Error(),
 		}).Error("Failed to check health")
 
-		pkg.Log.Error(w, http.StatusInternalServerError, "Failed to check health")
+		pkg.ResponseError(w, http.StatusInternalServerError, "Failed to check health", nil)
 		return
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 		"handler": "health_handler",
 		"action":  "check_health",
 		"status":  response.Status,
@@ -56,3 +57,33 @@ func (h *HealthHandler) CheckHealth(w http.ResponseWriter, r *http.Request) {
 	// Kirim response
 	pkg.ResponseSuccess(w, http.StatusOK, "Health check successful", response)
 }
+
+// CheckReadiness adalah handler untuk endpoint GET /api/health/ready
+// @Summary Readiness Check
+// @Description Mengecek database, versi schema migration dan pool stats. Mengembalikan 503 jika ada dependency yang gagal
+// @Tags Health
+// @Produce json
+// @Success 200 {object} models.ReadinessResponse
+// @Failure 503 {object} models.ReadinessResponse
+// @Router /api/health/ready [get]
+func (h *HealthHandler) CheckReadiness(w http.ResponseWriter, r *http.Request) {
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler": "health_handler",
+		"action":  "check_readiness",
+		"method":  r.Method,
+	}).Info("Readiness check handler called")
+
+	response := h.healthUseCase.CheckReadiness(r.Context())
+	if response.Status != models.HealthStatusUp {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "health_handler",
+			"action":  "check_readiness",
+			"status":  response.Status,
+		}).Warn("Service not ready")
+
+		pkg.ResponseError(w, http.StatusServiceUnavailable, "Service not ready", response)
+		return
+	}
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Service ready", response)
+}
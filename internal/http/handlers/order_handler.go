@@ -0,0 +1,370 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/domain/usecases"
+	"kasir-api/internal/pkg"
+	"net/http"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+type OrderHandler struct {
+	orderUseCase usecases.OrderUseCase
+}
+
+func NewOrderHandler(orderUseCase usecases.OrderUseCase) *OrderHandler {
+	return &OrderHandler{orderUseCase: orderUseCase}
+}
+
+// @Summary Get Orders
+// @Description Daftar order, terbaru dulu. Tanpa status hanya order yang masih terbuka (draft dan held).
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "draft, held, voided atau completed"
+// @Param page query int false "Halaman (default 1)"
+// @Param per_page query int false "Jumlah per halaman (default 20, maksimal 100)"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/orders [get]
+func (h *OrderHandler) GetOrders(w http.ResponseWriter, r *http.Request) {
+	page, perPage, err := parsePageParams(r)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "order_handler",
+			"action":  "get_orders",
+			"error":   err.Error(),
+		}).Warn("Invalid query parameter")
+		pkg.ResponseError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler": "order_handler",
+		"action":  "get_orders",
+		"status":  status,
+	}).Info("Get orders handler called")
+
+	orders, err := h.orderUseCase.GetOrders(r.Context(), status, page, perPage)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "order_handler",
+			"action":  "get_orders",
+			"error":   err.Error(),
+		}).Error("Failed to get orders")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.ResponseSuccess(w, http.StatusOK, "success", orders)
+}
+
+// @Summary Create Order
+// @Description Buat order draft. Items boleh kosong dan ditambahkan belakangan.
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body models.CreateOrderRequest true "Create Order Request"
+// @Success 201 {object} pkg.ResponsePayload
+// @Router /api/orders [post]
+func (h *OrderHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler": "order_handler",
+		"action":  "create_order",
+		"method":  r.Method,
+	}).Info("Create order handler called")
+
+	var req models.CreateOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "order_handler",
+			"action":  "create_order",
+			"error":   err.Error(),
+		}).Warn("Invalid request body")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid request", nil)
+		return
+	}
+
+	order, err := h.orderUseCase.CreateOrder(r.Context(), &req)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "order_handler",
+			"action":  "create_order",
+			"error":   err.Error(),
+		}).Error("Failed to create order")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":  "order_handler",
+		"action":   "create_order",
+		"order_id": order.ID,
+	}).Info("Order created successfully")
+
+	pkg.ResponseSuccess(w, http.StatusCreated, "Order created successfully", order)
+}
+
+// @Summary Get Order By ID
+// @Description Order beserta item dan harga produk saat ini
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/orders/{id} [get]
+func (h *OrderHandler) GetOrderByID(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseOrderID(w, r, "get_order_by_id")
+	if !ok {
+		return
+	}
+
+	order, err := h.orderUseCase.GetOrderByID(r.Context(), id)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":  "order_handler",
+			"action":   "get_order_by_id",
+			"order_id": id,
+			"error":    err.Error(),
+		}).Error("Failed to get order")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Order found", order)
+}
+
+// @Summary Update Order
+// @Description Ubah order draft. Quantity item menimpa quantity sebelumnya, quantity 0 menghapus item. Note dan promo_code hanya diubah jika dikirim.
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Param body body models.UpdateOrderRequest true "Update Order Request"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/orders/{id} [patch]
+func (h *OrderHandler) UpdateOrder(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseOrderID(w, r, "update_order")
+	if !ok {
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":  "order_handler",
+		"action":   "update_order",
+		"order_id": id,
+	}).Info("Update order handler called")
+
+	var req models.UpdateOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":  "order_handler",
+			"action":   "update_order",
+			"order_id": id,
+			"error":    err.Error(),
+		}).Warn("Invalid request body")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid request", nil)
+		return
+	}
+
+	order, err := h.orderUseCase.UpdateOrder(r.Context(), id, &req)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":  "order_handler",
+			"action":   "update_order",
+			"order_id": id,
+			"error":    err.Error(),
+		}).Error("Failed to update order")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Order updated successfully", order)
+}
+
+// @Summary Hold Order
+// @Description Parkir order draft supaya kasir bisa melayani pelanggan lain
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/orders/{id}/hold [post]
+func (h *OrderHandler) HoldOrder(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseOrderID(w, r, "hold_order")
+	if !ok {
+		return
+	}
+
+	order, err := h.orderUseCase.HoldOrder(r.Context(), id)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":  "order_handler",
+			"action":   "hold_order",
+			"order_id": id,
+			"error":    err.Error(),
+		}).Error("Failed to hold order")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Order held", order)
+}
+
+// @Summary Resume Order
+// @Description Lanjutkan order yang diparkir, status kembali menjadi draft
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/orders/{id}/resume [post]
+func (h *OrderHandler) ResumeOrder(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseOrderID(w, r, "resume_order")
+	if !ok {
+		return
+	}
+
+	order, err := h.orderUseCase.ResumeOrder(r.Context(), id)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":  "order_handler",
+			"action":   "resume_order",
+			"order_id": id,
+			"error":    err.Error(),
+		}).Error("Failed to resume order")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Order resumed", order)
+}
+
+// @Summary Void Order
+// @Description Batalkan order draft atau held. Kasir wajib menyertakan username dan password admin sebagai supervisor.
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Param body body models.VoidOrderRequest true "Void Order Request"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/orders/{id}/void [post]
+func (h *OrderHandler) VoidOrder(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseOrderID(w, r, "void_order")
+	if !ok {
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":  "order_handler",
+		"action":   "void_order",
+		"order_id": id,
+	}).Info("Void order handler called")
+
+	var req models.VoidOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":  "order_handler",
+			"action":   "void_order",
+			"order_id": id,
+			"error":    err.Error(),
+		}).Warn("Invalid request body")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid request", nil)
+		return
+	}
+
+	order, err := h.orderUseCase.VoidOrder(r.Context(), id, &req)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":  "order_handler",
+			"action":   "void_order",
+			"order_id": id,
+			"error":    err.Error(),
+		}).Error("Failed to void order")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":  "order_handler",
+		"action":   "void_order",
+		"order_id": id,
+	}).Info("Order voided successfully")
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Order voided successfully", order)
+}
+
+// parseOrderID membaca {id} dari path dan menulis 400 jika tidak valid
+func parseOrderID(w http.ResponseWriter, r *http.Request, action string) (int, bool) {
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "order_handler",
+			"action":  action,
+			"id_str":  idStr,
+		}).Warn("Invalid order ID format")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid Order ID", nil)
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *OrderHandler) HandleOrders(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetOrders(w, r)
+	case http.MethodPost:
+		h.CreateOrder(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *OrderHandler) HandleOrderByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetOrderByID(w, r)
+	case http.MethodPatch:
+		h.UpdateOrder(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *OrderHandler) HandleHoldOrder(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.HoldOrder(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *OrderHandler) HandleResumeOrder(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.ResumeOrder(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *OrderHandler) HandleVoidOrder(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.VoidOrder(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"context"
+	"kasir-api/internal/domain/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeOrderUseCase mengembalikan err untuk semua aksi, atau order dengan
+// status hasil aksi jika err nil
+type fakeOrderUseCase struct {
+	err    error
+	called bool
+}
+
+func (f *fakeOrderUseCase) result(id int, status string) (*models.Order, error) {
+	f.called = true
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &models.Order{ID: id, Status: status}, nil
+}
+
+func (f *fakeOrderUseCase) CreateOrder(ctx context.Context, req *models.CreateOrderRequest) (*models.Order, error) {
+	return f.result(1, models.OrderStatusDraft)
+}
+
+func (f *fakeOrderUseCase) GetOrderByID(ctx context.Context, id int) (*models.Order, error) {
+	return f.result(id, models.OrderStatusDraft)
+}
+
+func (f *fakeOrderUseCase) GetOrders(ctx context.Context, status string, page, perPage int) ([]models.Order, error) {
+	f.called = true
+	return []models.Order{}, f.err
+}
+
+func (f *fakeOrderUseCase) UpdateOrder(ctx context.Context, id int, req *models.UpdateOrderRequest) (*models.Order, error) {
+	return f.result(id, models.OrderStatusDraft)
+}
+
+func (f *fakeOrderUseCase) HoldOrder(ctx context.Context, id int) (*models.Order, error) {
+	return f.result(id, models.OrderStatusHeld)
+}
+
+func (f *fakeOrderUseCase) ResumeOrder(ctx context.Context, id int) (*models.Order, error) {
+	return f.result(id, models.OrderStatusDraft)
+}
+
+func (f *fakeOrderUseCase) VoidOrder(ctx context.Context, id int, req *models.VoidOrderRequest) (*models.Order, error) {
+	return f.result(id, models.OrderStatusVoided)
+}
+
+func TestVoidOrderStatusCodes(t *testing.T) {
+	body := `{"reason":"pelanggan batal","supervisor_username":"admin","supervisor_password":"rahasia123"}`
+	tests := []struct {
+		name   string
+		id     string
+		body   string
+		err    error
+		code   int
+		called bool
+	}{
+		{name: "success", id: "1", body: body, code: http.StatusOK, called: true},
+		{name: "invalid order id", id: "x", body: body, code: http.StatusBadRequest},
+		{name: "malformed body", id: "1", body: `{"reason":`, code: http.StatusBadRequest},
+		{name: "reason required", id: "1", body: `{}`, err: models.NewValidationError("reason", "void reason is required"),
+			code: http.StatusBadRequest, called: true},
+		{name: "supervisor approval required", id: "1", body: body, err: models.NewForbiddenError("supervisor approval is required"),
+			code: http.StatusForbidden, called: true},
+		{name: "invalid supervisor credentials", id: "1", body: body, err: models.NewForbiddenError("invalid supervisor credentials"),
+			code: http.StatusForbidden, called: true},
+		{name: "unknown order", id: "1", body: body, err: models.NewNotFoundError("order"), code: http.StatusNotFound, called: true},
+		{name: "completed order", id: "1", body: body, err: models.NewConflictError("order is completed, only draft or held orders can be voided"),
+			code: http.StatusConflict, called: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &fakeOrderUseCase{err: tt.err}
+			h := NewOrderHandler(uc)
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/orders/"+tt.id+"/void", strings.NewReader(tt.body))
+			req.SetPathValue("id", tt.id)
+			h.VoidOrder(rec, req)
+
+			if rec.Code != tt.code {
+				t.Errorf("status = %d, want %d (body %s)", rec.Code, tt.code, rec.Body.String())
+			}
+			if uc.called != tt.called {
+				t.Errorf("use case called = %v, want %v", uc.called, tt.called)
+			}
+		})
+	}
+}
+
+func TestHoldOrderStatusCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		err  error
+		code int
+	}{
+		{name: "success", id: "1", code: http.StatusOK},
+		{name: "invalid order id", id: "x", code: http.StatusBadRequest},
+		{name: "not a draft", id: "1", err: models.NewConflictError("order is held"), code: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewOrderHandler(&fakeOrderUseCase{err: tt.err})
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/orders/"+tt.id+"/hold", nil)
+			req.SetPathValue("id", tt.id)
+			h.HoldOrder(rec, req)
+
+			if rec.Code != tt.code {
+				t.Errorf("status = %d, want %d (body %s)", rec.Code, tt.code, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// parsePageParams membaca query parameter page dan per_page.
+// Nilai kosong dikembalikan sebagai 0 supaya use case memakai default.
+func parsePageParams(r *http.Request) (page, perPage int, err error) {
+	q := r.URL.Query()
+	params := map[string]*int{
+		"page":     &page,
+		"per_page": &perPage,
+	}
+	for key, target := range params {
+		if v := q.Get(key); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return 0, 0, fmt.Errorf("invalid %s", key)
+			}
+			*target = n
+		}
+	}
+	return page, perPage, nil
+}
@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"kasir-api/internal/domain/models"
 	"kasir-api/internal/domain/usecases"
 	"kasir-api/internal/pkg"
@@ -12,6 +14,8 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+const productBarcodePath = "/api/product/barcode/"
+
 type ProductHandler struct {
 	productUseCase usecases.ProductUseCase
 }
@@ -21,39 +25,114 @@ func NewProductHandler(productUseCase usecases.ProductUseCase) *ProductHandler {
 }
 
 // @Summary Get All Products
-// @Description Get All Products
+// @Description Get All Products dengan filter, sorting dan pagination
 // @Tags Product
 // @Accept json
 // @Produce json
+// @Param name query string false "Filter nama produk"
+// @Param category_id query int false "Filter kategori"
+// @Param min_price query int false "Harga minimum"
+// @Param max_price query int false "Harga maksimum"
+// @Param in_stock query bool false "Hanya produk dengan stok > 0"
+// @Param sort query string false "name, price, stock (prefix - untuk descending)"
+// @Param page query int false "Halaman (default 1)"
+// @Param per_page query int false "Jumlah per halaman (default 20, max 100)"
+// @Param cursor query string false "Cursor dari meta.next_cursor"
 // @Success 200 {object} pkg.ResponsePayload
 // @Router /api/product [get]
 func (h *ProductHandler) GetAllProduct(w http.ResponseWriter, r *http.Request) {
-	name := r.URL.Query().Get("name")
-
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 		"handler": "product_handler",
 		"action":  "get_all_products",
 		"method":  r.Method,
 	}).Info("Get all products handler called")
 
-	products, err := h.productUseCase.GetAllProducts(name)
+	filter, err := parseProductFilter(r)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "product_handler",
+			"action":  "get_all_products",
+			"error":   err.Error(),
+		}).Warn("Invalid query parameter")
+		pkg.ResponseError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	result, err := h.productUseCase.GetAllProducts(r.Context(), filter)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 			"handler": "product_handler",
 			"action":  "get_all_products",
 			"error":   err.Error(),
 		}).Error("Failed to get products")
-		pkg.ResponseError(w, http.StatusInternalServerError, "Failed to get products", nil)
+		pkg.ResponseFromError(w, err)
 		return
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 		"handler": "product_handler",
 		"action":  "get_all_products",
-		"count":   len(products),
+		"count":   len(result.Products),
 	}).Info("Products retrieved successfully")
 
-	pkg.ResponseSuccess(w, http.StatusOK, "Products retrieved successfully", products)
+	pkg.ResponseSuccessWithMeta(w, http.StatusOK, "Products retrieved successfully", result.Products, result.Meta)
+}
+
+// parseProductFilter membaca query parameter list produk
+func parseProductFilter(r *http.Request) (models.ProductFilter, error) {
+	q := r.URL.Query()
+	filter := models.ProductFilter{
+		Name: q.Get("name"),
+		Sort: q.Get("sort"),
+	}
+
+	intParams := map[string]*int{
+		"category_id": &filter.CategoryID,
+		"page":        &filter.Page,
+		"per_page":    &filter.PerPage,
+	}
+	for key, target := range intParams {
+		if v := q.Get(key); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return filter, fmt.Errorf("invalid %s", key)
+			}
+			*target = n
+		}
+	}
+
+	if v := q.Get("min_price"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return filter, errors.New("invalid min_price")
+		}
+		filter.MinPrice = &n
+	}
+	if v := q.Get("max_price"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return filter, errors.New("invalid max_price")
+		}
+		filter.MaxPrice = &n
+	}
+
+	if v := q.Get("in_stock"); v != "" {
+		inStock, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, errors.New("invalid in_stock")
+		}
+		filter.InStock = inStock
+	}
+
+	if v := q.Get("cursor"); v != "" {
+		cursor, err := models.DecodeProductCursor(v)
+		if err != nil {
+			return filter, errors.New("invalid cursor")
+		}
+		filter.Cursor = cursor
+	}
+
+	return filter, nil
 }
 
 // @Summary Create Product
@@ -65,7 +144,7 @@ func (h *ProductHandler) GetAllProduct(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} pkg.ResponsePayload
 // @Router /api/product [post]
 func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 		"handler": "product_handler",
 		"action":  "create_product",
 		"method":  r.Method,
@@ -74,7 +153,7 @@ func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
 	var newProduct models.Product
 	err := json.NewDecoder(r.Body).Decode(&newProduct)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 			"handler": "product_handler",
 			"action":  "create_product",
 			"error":   err.Error(),
@@ -83,26 +162,25 @@ func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.productUseCase.CreateProduct(&newProduct)
+	err = h.productUseCase.CreateProduct(r.Context(), &newProduct)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 			"handler":      "product_handler",
 			"action":       "create_product",
 			"product_name": newProduct.Name,
 			"error":        err.Error(),
 		}).Error("Failed to create product")
-		pkg.ResponseError(w, http.StatusInternalServerError, err.Error(), nil)
+		pkg.ResponseFromError(w, err)
 		return
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 		"handler":      "product_handler",
 		"action":       "create_product",
 		"product_id":   newProduct.ID,
 		"product_name": newProduct.Name,
 	}).Info("Product created successfully")
 
-	w.WriteHeader(http.StatusCreated)
 	pkg.ResponseSuccess(w, http.StatusCreated, "Product created successfully", nil)
 }
 
@@ -118,7 +196,7 @@ func (h *ProductHandler) GetProductByID(w http.ResponseWriter, r *http.Request)
 	idStr := strings.TrimPrefix(r.URL.Path, "/api/product/")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 			"handler": "product_handler",
 			"action":  "get_product_by_id",
 			"id_str":  idStr,
@@ -127,25 +205,25 @@ func (h *ProductHandler) GetProductByID(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 		"handler":    "product_handler",
 		"action":     "get_product_by_id",
 		"product_id": id,
 	}).Info("Get product by ID handler called")
 
-	product, err := h.productUseCase.GetProductByID(id)
+	product, err := h.productUseCase.GetProductByID(r.Context(), id)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 			"handler":    "product_handler",
 			"action":     "get_product_by_id",
 			"product_id": id,
 			"error":      err.Error(),
 		}).Error("Failed to get product")
-		pkg.ResponseError(w, http.StatusNotFound, err.Error(), nil)
+		pkg.ResponseFromError(w, err)
 		return
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 		"handler":      "product_handler",
 		"action":       "get_product_by_id",
 		"product_id":   id,
@@ -170,7 +248,7 @@ func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 	// ganti jadi int
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 			"handler": "product_handler",
 			"action":  "update_product",
 			"id_str":  idStr,
@@ -179,7 +257,7 @@ func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 		"handler":    "product_handler",
 		"action":     "update_product",
 		"product_id": id,
@@ -189,7 +267,7 @@ func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 	var updateProduct models.Product
 	err = json.NewDecoder(r.Body).Decode(&updateProduct)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 			"handler":    "product_handler",
 			"action":     "update_product",
 			"product_id": id,
@@ -200,20 +278,20 @@ func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 	}
 
 	updateProduct.ID = id
-	err = h.productUseCase.UpdateProduct(&updateProduct)
+	err = h.productUseCase.UpdateProduct(r.Context(), &updateProduct)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 			"handler":      "product_handler",
 			"action":       "update_product",
 			"product_id":   id,
 			"product_name": updateProduct.Name,
 			"error":        err.Error(),
 		}).Error("Failed to update product")
-		pkg.ResponseError(w, http.StatusInternalServerError, err.Error(), nil)
+		pkg.ResponseFromError(w, err)
 		return
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 		"handler":      "product_handler",
 		"action":       "update_product",
 		"product_id":   id,
@@ -236,7 +314,7 @@ func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
 	// ganti jadi int
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 			"handler": "product_handler",
 			"action":  "delete_product",
 			"id_str":  idStr,
@@ -245,25 +323,25 @@ func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 		"handler":    "product_handler",
 		"action":     "delete_product",
 		"product_id": id,
 	}).Info("Delete product handler called")
 
-	err = h.productUseCase.DeleteProduct(id)
+	err = h.productUseCase.DeleteProduct(r.Context(), id)
 	if err != nil {
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 			"handler":    "product_handler",
 			"action":     "delete_product",
 			"product_id": id,
 			"error":      err.Error(),
 		}).Error("Failed to delete product")
-		pkg.ResponseError(w, http.StatusInternalServerError, err.Error(), nil)
+		pkg.ResponseFromError(w, err)
 		return
 	}
 
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 		"handler":    "product_handler",
 		"action":     "delete_product",
 		"product_id": id,
@@ -272,10 +350,191 @@ func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
 	pkg.ResponseSuccess(w, http.StatusOK, "Product deleted successfully", nil)
 }
 
+// @Summary Get Product By Barcode
+// @Description Cari produk dari hasil scan EAN-8, UPC-A atau EAN-13
+// @Tags Product
+// @Accept json
+// @Produce json
+// @Param code path string true "Barcode"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/product/barcode/{code} [get]
+func (h *ProductHandler) GetProductByBarcode(w http.ResponseWriter, r *http.Request) {
+	code := strings.TrimPrefix(r.URL.Path, productBarcodePath)
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler": "product_handler",
+		"action":  "get_product_by_barcode",
+		"code":    code,
+	}).Info("Get product by barcode handler called")
+
+	product, err := h.productUseCase.GetProductByBarcode(r.Context(), code)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "product_handler",
+			"action":  "get_product_by_barcode",
+			"code":    code,
+			"error":   err.Error(),
+		}).Warn("Failed to get product by barcode")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Product found", product)
+}
+
+// @Summary Generate Barcode
+// @Description Tambahkan barcode EAN-13 internal (prefix 04) untuk produk tanpa kode pabrik
+// @Tags Product
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Success 201 {object} pkg.ResponsePayload
+// @Router /api/product/{id}/barcode [post]
+func (h *ProductHandler) GenerateBarcode(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "product_handler",
+			"action":  "generate_barcode",
+			"id_str":  idStr,
+		}).Warn("Invalid product ID format")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid Product ID", nil)
+		return
+	}
+
+	product, err := h.productUseCase.GenerateBarcode(r.Context(), id)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":    "product_handler",
+			"action":     "generate_barcode",
+			"product_id": id,
+			"error":      err.Error(),
+		}).Error("Failed to generate barcode")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.ResponseSuccess(w, http.StatusCreated, "Barcode generated successfully", product)
+}
+
+// @Summary Set Product Options
+// @Description Ganti sumbu opsi produk (maks 3, mis. size dan colour). Satu varian dibuat untuk setiap kombinasi nilai dengan harga awal sama dengan harga produk; kombinasi yang hilang dinonaktifkan.
+// @Tags Product
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param options body models.SetProductOptionsRequest true "Product options"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/product/{id}/variants [put]
+func (h *ProductHandler) SetProductOptions(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "product_handler",
+			"action":  "set_product_options",
+			"id_str":  idStr,
+		}).Warn("Invalid product ID format")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid Product ID", nil)
+		return
+	}
+
+	var req models.SetProductOptionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":    "product_handler",
+			"action":     "set_product_options",
+			"product_id": id,
+			"error":      err.Error(),
+		}).Warn("Invalid request body")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid request", nil)
+		return
+	}
+
+	product, err := h.productUseCase.SetProductOptions(r.Context(), id, req)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":    "product_handler",
+			"action":     "set_product_options",
+			"product_id": id,
+			"error":      err.Error(),
+		}).Error("Failed to set product options")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Product options updated successfully", product)
+}
+
+// @Summary Update Variant
+// @Description Ubah SKU, harga, status aktif atau barcode satu varian. Stok varian diubah lewat POST /api/product/{id}/stock dengan variant_id.
+// @Tags Product
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param variant_id path int true "Variant ID"
+// @Param variant body models.UpdateVariantRequest true "Variant changes"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/product/{id}/variants/{variant_id} [put]
+func (h *ProductHandler) UpdateVariant(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "product_handler",
+			"action":  "update_variant",
+			"id_str":  r.PathValue("id"),
+		}).Warn("Invalid product ID format")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid Product ID", nil)
+		return
+	}
+	variantID, err := strconv.Atoi(r.PathValue("variant_id"))
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":        "product_handler",
+			"action":         "update_variant",
+			"variant_id_str": r.PathValue("variant_id"),
+		}).Warn("Invalid variant ID format")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid Variant ID", nil)
+		return
+	}
+
+	var req models.UpdateVariantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":    "product_handler",
+			"action":     "update_variant",
+			"product_id": id,
+			"variant_id": variantID,
+			"error":      err.Error(),
+		}).Warn("Invalid request body")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid request", nil)
+		return
+	}
+
+	product, err := h.productUseCase.UpdateVariant(r.Context(), id, variantID, req)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":    "product_handler",
+			"action":     "update_variant",
+			"product_id": id,
+			"variant_id": variantID,
+			"error":      err.Error(),
+		}).Error("Failed to update variant")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Variant updated successfully", product)
+}
+
 // define function untuk handle method
 func (h *ProductHandler) HandleProduct(w http.ResponseWriter, r *http.Request) {
 	// Debug tracing
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 		"handler": "product_handler",
 		"func":    "HandleProduct",
 		"method":  r.Method,
@@ -292,13 +551,24 @@ func (h *ProductHandler) HandleProduct(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *ProductHandler) HandleProductByID(w http.ResponseWriter, r *http.Request) {
-	pkg.Log.WithFields(logrus.Fields{
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 		"handler": "product_handler",
 		"func":    "HandleProductByID",
 		"method":  r.Method,
 		"path":    r.URL.Path,
 	}).Info("Routing dispatcher called")
 
+	// lookup barcode ditangani di sini karena pattern /api/product/barcode/{code}
+	// bentrok dengan /api/product/{id}/stock di ServeMux
+	if strings.HasPrefix(r.URL.Path, productBarcodePath) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+			return
+		}
+		h.GetProductByBarcode(w, r)
+		return
+	}
+
 	switch r.Method {
 	case http.MethodGet:
 		h.GetProductByID(w, r)
@@ -310,3 +580,30 @@ func (h *ProductHandler) HandleProductByID(w http.ResponseWriter, r *http.Reques
 		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
 	}
 }
+
+func (h *ProductHandler) HandleGenerateBarcode(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.GenerateBarcode(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ProductHandler) HandleProductVariants(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPut:
+		h.SetProductOptions(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ProductHandler) HandleProductVariant(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPut:
+		h.UpdateVariant(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
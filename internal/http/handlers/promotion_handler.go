@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/domain/usecases"
+	"kasir-api/internal/pkg"
+	"net/http"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+type PromotionHandler struct {
+	promotionUseCase usecases.PromotionUseCase
+}
+
+func NewPromotionHandler(promotionUseCase usecases.PromotionUseCase) *PromotionHandler {
+	return &PromotionHandler{promotionUseCase: promotionUseCase}
+}
+
+// @Summary Get All Promotions
+// @Description Daftar semua promo, termasuk yang tidak aktif
+// @Tags Promotion
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/promotion [get]
+func (h *PromotionHandler) GetAllPromotions(w http.ResponseWriter, r *http.Request) {
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler": "promotion_handler",
+		"action":  "get_all_promotions",
+		"method":  r.Method,
+	}).Info("Get all promotions handler called")
+
+	promotions, err := h.promotionUseCase.GetAllPromotions(r.Context())
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "promotion_handler",
+			"action":  "get_all_promotions",
+			"error":   err.Error(),
+		}).Error("Failed to get promotions")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.ResponseSuccess(w, http.StatusOK, "success", promotions)
+}
+
+// @Summary Create Promotion
+// @Description Buat promo percentage, fixed atau buy_x_get_y untuk item, kategori atau seluruh keranjang
+// @Tags Promotion
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body models.Promotion true "Create Promotion Request"
+// @Success 201 {object} pkg.ResponsePayload
+// @Router /api/promotion [post]
+func (h *PromotionHandler) CreatePromotion(w http.ResponseWriter, r *http.Request) {
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler": "promotion_handler",
+		"action":  "create_promotion",
+		"method":  r.Method,
+	}).Info("Create promotion handler called")
+
+	// active default true, sama dengan default kolom di database
+	promotion := models.Promotion{Active: true}
+	if err := json.NewDecoder(r.Body).Decode(&promotion); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "promotion_handler",
+			"action":  "create_promotion",
+			"error":   err.Error(),
+		}).Warn("Invalid request body")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid request", nil)
+		return
+	}
+
+	if err := h.promotionUseCase.CreatePromotion(r.Context(), &promotion); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "promotion_handler",
+			"action":  "create_promotion",
+			"error":   err.Error(),
+		}).Error("Failed to create promotion")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":      "promotion_handler",
+		"action":       "create_promotion",
+		"promotion_id": promotion.ID,
+	}).Info("Promotion created successfully")
+
+	pkg.ResponseSuccess(w, http.StatusCreated, "Promotion created successfully", promotion)
+}
+
+// @Summary Get Promotion By ID
+// @Description Get Promotion By ID
+// @Tags Promotion
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Promotion ID"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/promotion/{id} [get]
+func (h *PromotionHandler) GetPromotionByID(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePromotionID(w, r, "get_promotion_by_id")
+	if !ok {
+		return
+	}
+
+	promotion, err := h.promotionUseCase.GetPromotionByID(r.Context(), id)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":      "promotion_handler",
+			"action":       "get_promotion_by_id",
+			"promotion_id": id,
+			"error":        err.Error(),
+		}).Error("Failed to get promotion")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Promotion found", promotion)
+}
+
+// @Summary Update Promotion
+// @Description Ganti seluruh aturan promo. Kirim active false untuk menonaktifkan promo tanpa menghapusnya.
+// @Tags Promotion
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Promotion ID"
+// @Param body body models.Promotion true "Update Promotion Request"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/promotion/{id} [put]
+func (h *PromotionHandler) UpdatePromotion(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePromotionID(w, r, "update_promotion")
+	if !ok {
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":      "promotion_handler",
+		"action":       "update_promotion",
+		"promotion_id": id,
+	}).Info("Update promotion handler called")
+
+	promotion := models.Promotion{Active: true}
+	if err := json.NewDecoder(r.Body).Decode(&promotion); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":      "promotion_handler",
+			"action":       "update_promotion",
+			"promotion_id": id,
+			"error":        err.Error(),
+		}).Warn("Invalid request body")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid request", nil)
+		return
+	}
+
+	promotion.ID = id
+	if err := h.promotionUseCase.UpdatePromotion(r.Context(), &promotion); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":      "promotion_handler",
+			"action":       "update_promotion",
+			"promotion_id": id,
+			"error":        err.Error(),
+		}).Error("Failed to update promotion")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":      "promotion_handler",
+		"action":       "update_promotion",
+		"promotion_id": id,
+	}).Info("Promotion updated successfully")
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Promotion updated successfully", promotion)
+}
+
+// @Summary Delete Promotion
+// @Description Hapus promo. Riwayat promo di transaksi lama tetap tersimpan.
+// @Tags Promotion
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Promotion ID"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/promotion/{id} [delete]
+func (h *PromotionHandler) DeletePromotion(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePromotionID(w, r, "delete_promotion")
+	if !ok {
+		return
+	}
+
+	if err := h.promotionUseCase.DeletePromotion(r.Context(), id); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":      "promotion_handler",
+			"action":       "delete_promotion",
+			"promotion_id": id,
+			"error":        err.Error(),
+		}).Error("Failed to delete promotion")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":      "promotion_handler",
+		"action":       "delete_promotion",
+		"promotion_id": id,
+	}).Info("Promotion deleted successfully")
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Promotion deleted successfully", nil)
+}
+
+// parsePromotionID membaca {id} dari path dan menulis 400 jika tidak valid
+func parsePromotionID(w http.ResponseWriter, r *http.Request, action string) (int, bool) {
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "promotion_handler",
+			"action":  action,
+			"id_str":  idStr,
+		}).Warn("Invalid promotion ID format")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid Promotion ID", nil)
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *PromotionHandler) HandlePromotion(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAllPromotions(w, r)
+	case http.MethodPost:
+		h.CreatePromotion(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *PromotionHandler) HandlePromotionByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetPromotionByID(w, r)
+	case http.MethodPut:
+		h.UpdatePromotion(w, r)
+	case http.MethodDelete:
+		h.DeletePromotion(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
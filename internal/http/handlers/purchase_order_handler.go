@@ -0,0 +1,438 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/domain/usecases"
+	"kasir-api/internal/pkg"
+	"net/http"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+type PurchaseOrderHandler struct {
+	purchaseOrderUseCase usecases.PurchaseOrderUseCase
+}
+
+func NewPurchaseOrderHandler(purchaseOrderUseCase usecases.PurchaseOrderUseCase) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{purchaseOrderUseCase: purchaseOrderUseCase}
+}
+
+// @Summary Get Purchase Orders
+// @Description Daftar purchase order, terbaru dulu
+// @Tags Purchase Order
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "draft, sent, partially_received, received atau cancelled"
+// @Param supplier_id query int false "Hanya PO untuk supplier ini"
+// @Param page query int false "Halaman (default 1)"
+// @Param per_page query int false "Jumlah per halaman (default 20, maksimal 100)"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/purchase-orders [get]
+func (h *PurchaseOrderHandler) GetPurchaseOrders(w http.ResponseWriter, r *http.Request) {
+	filter, ok := parsePurchaseOrderFilter(w, r, "get_purchase_orders")
+	if !ok {
+		return
+	}
+	if v := r.URL.Query().Get("supplier_id"); v != "" {
+		supplierID, err := strconv.Atoi(v)
+		if err != nil {
+			pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+				"handler":     "purchase_order_handler",
+				"action":      "get_purchase_orders",
+				"supplier_id": v,
+			}).Warn("Invalid query parameter")
+			pkg.ResponseError(w, http.StatusBadRequest, "invalid supplier_id", nil)
+			return
+		}
+		filter.SupplierID = supplierID
+	}
+
+	h.listPurchaseOrders(w, r, "get_purchase_orders", filter)
+}
+
+// @Summary Get Supplier Purchase Orders
+// @Description Riwayat purchase order sebuah supplier, terbaru dulu
+// @Tags Purchase Order
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Supplier ID"
+// @Param status query string false "draft, sent, partially_received, received atau cancelled"
+// @Param page query int false "Halaman (default 1)"
+// @Param per_page query int false "Jumlah per halaman (default 20, maksimal 100)"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/suppliers/{id}/purchase-orders [get]
+func (h *PurchaseOrderHandler) GetSupplierPurchaseOrders(w http.ResponseWriter, r *http.Request) {
+	supplierID, ok := parseSupplierID(w, r, "get_supplier_purchase_orders")
+	if !ok {
+		return
+	}
+	filter, ok := parsePurchaseOrderFilter(w, r, "get_supplier_purchase_orders")
+	if !ok {
+		return
+	}
+	filter.SupplierID = supplierID
+
+	h.listPurchaseOrders(w, r, "get_supplier_purchase_orders", filter)
+}
+
+func (h *PurchaseOrderHandler) listPurchaseOrders(w http.ResponseWriter, r *http.Request, action string, filter models.PurchaseOrderFilter) {
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":     "purchase_order_handler",
+		"action":      action,
+		"supplier_id": filter.SupplierID,
+		"status":      filter.Status,
+	}).Info("Get purchase orders handler called")
+
+	result, err := h.purchaseOrderUseCase.GetPurchaseOrders(r.Context(), filter)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "purchase_order_handler",
+			"action":  action,
+			"error":   err.Error(),
+		}).Error("Failed to get purchase orders")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.ResponseSuccessWithMeta(w, http.StatusOK, "Purchase orders retrieved successfully", result.PurchaseOrders, result.Meta)
+}
+
+// parsePurchaseOrderFilter membaca status dan pagination dari query string
+// dan menulis 400 jika tidak valid
+func parsePurchaseOrderFilter(w http.ResponseWriter, r *http.Request, action string) (models.PurchaseOrderFilter, bool) {
+	page, perPage, err := parsePageParams(r)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "purchase_order_handler",
+			"action":  action,
+			"error":   err.Error(),
+		}).Warn("Invalid query parameter")
+		pkg.ResponseError(w, http.StatusBadRequest, err.Error(), nil)
+		return models.PurchaseOrderFilter{}, false
+	}
+	return models.PurchaseOrderFilter{
+		Status:  r.URL.Query().Get("status"),
+		Page:    page,
+		PerPage: perPage,
+	}, true
+}
+
+// @Summary Create Purchase Order
+// @Description Buat purchase order draft ke supplier. Quantity dan unit_cost dalam satuan unit baris (default satuan dasar produk).
+// @Tags Purchase Order
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body models.PurchaseOrderRequest true "Create Purchase Order Request"
+// @Success 201 {object} pkg.ResponsePayload
+// @Router /api/purchase-orders [post]
+func (h *PurchaseOrderHandler) CreatePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler": "purchase_order_handler",
+		"action":  "create_purchase_order",
+		"method":  r.Method,
+	}).Info("Create purchase order handler called")
+
+	var req models.PurchaseOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "purchase_order_handler",
+			"action":  "create_purchase_order",
+			"error":   err.Error(),
+		}).Warn("Invalid request body")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid request", nil)
+		return
+	}
+
+	po, err := h.purchaseOrderUseCase.CreatePurchaseOrder(r.Context(), &req)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "purchase_order_handler",
+			"action":  "create_purchase_order",
+			"error":   err.Error(),
+		}).Error("Failed to create purchase order")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":           "purchase_order_handler",
+		"action":            "create_purchase_order",
+		"purchase_order_id": po.ID,
+	}).Info("Purchase order created successfully")
+
+	pkg.ResponseSuccess(w, http.StatusCreated, "Purchase order created successfully", po)
+}
+
+// @Summary Get Purchase Order By ID
+// @Description Purchase order beserta baris, jumlah yang sudah diterima dan riwayat goods receipt
+// @Tags Purchase Order
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Purchase Order ID"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/purchase-orders/{id} [get]
+func (h *PurchaseOrderHandler) GetPurchaseOrderByID(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePurchaseOrderID(w, r, "get_purchase_order_by_id")
+	if !ok {
+		return
+	}
+
+	po, err := h.purchaseOrderUseCase.GetPurchaseOrderByID(r.Context(), id)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":           "purchase_order_handler",
+			"action":            "get_purchase_order_by_id",
+			"purchase_order_id": id,
+			"error":             err.Error(),
+		}).Error("Failed to get purchase order")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Purchase order found", po)
+}
+
+// @Summary Update Purchase Order
+// @Description Ganti supplier, note dan semua baris purchase order draft
+// @Tags Purchase Order
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Purchase Order ID"
+// @Param body body models.PurchaseOrderRequest true "Update Purchase Order Request"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/purchase-orders/{id} [put]
+func (h *PurchaseOrderHandler) UpdatePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePurchaseOrderID(w, r, "update_purchase_order")
+	if !ok {
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":           "purchase_order_handler",
+		"action":            "update_purchase_order",
+		"purchase_order_id": id,
+	}).Info("Update purchase order handler called")
+
+	var req models.PurchaseOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":           "purchase_order_handler",
+			"action":            "update_purchase_order",
+			"purchase_order_id": id,
+			"error":             err.Error(),
+		}).Warn("Invalid request body")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid request", nil)
+		return
+	}
+
+	po, err := h.purchaseOrderUseCase.UpdatePurchaseOrder(r.Context(), id, &req)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":           "purchase_order_handler",
+			"action":            "update_purchase_order",
+			"purchase_order_id": id,
+			"error":             err.Error(),
+		}).Error("Failed to update purchase order")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Purchase order updated successfully", po)
+}
+
+// @Summary Send Purchase Order
+// @Description Tandai purchase order draft sudah dikirim ke supplier. PO yang sudah dikirim tidak bisa diubah.
+// @Tags Purchase Order
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Purchase Order ID"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/purchase-orders/{id}/send [post]
+func (h *PurchaseOrderHandler) SendPurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePurchaseOrderID(w, r, "send_purchase_order")
+	if !ok {
+		return
+	}
+
+	po, err := h.purchaseOrderUseCase.SendPurchaseOrder(r.Context(), id)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":           "purchase_order_handler",
+			"action":            "send_purchase_order",
+			"purchase_order_id": id,
+			"error":             err.Error(),
+		}).Error("Failed to send purchase order")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Purchase order sent", po)
+}
+
+// @Summary Cancel Purchase Order
+// @Description Batalkan purchase order yang belum selesai diterima. Barang yang sudah diterima tetap di stok.
+// @Tags Purchase Order
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Purchase Order ID"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/purchase-orders/{id}/cancel [post]
+func (h *PurchaseOrderHandler) CancelPurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePurchaseOrderID(w, r, "cancel_purchase_order")
+	if !ok {
+		return
+	}
+
+	po, err := h.purchaseOrderUseCase.CancelPurchaseOrder(r.Context(), id)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":           "purchase_order_handler",
+			"action":            "cancel_purchase_order",
+			"purchase_order_id": id,
+			"error":             err.Error(),
+		}).Error("Failed to cancel purchase order")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Purchase order cancelled", po)
+}
+
+// @Summary Receive Goods
+// @Description Catat barang yang diterima untuk purchase order yang sudah dikirim. Stok bertambah dengan reason restock dan harga pokok diperbarui dari unit_cost. Tanpa lines berarti semua sisa diterima.
+// @Tags Purchase Order
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Purchase Order ID"
+// @Param body body models.ReceiveGoodsRequest true "Receive Goods Request"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/purchase-orders/{id}/receive [post]
+func (h *PurchaseOrderHandler) ReceiveGoods(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePurchaseOrderID(w, r, "receive_goods")
+	if !ok {
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":           "purchase_order_handler",
+		"action":            "receive_goods",
+		"purchase_order_id": id,
+	}).Info("Receive goods handler called")
+
+	var req models.ReceiveGoodsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":           "purchase_order_handler",
+			"action":            "receive_goods",
+			"purchase_order_id": id,
+			"error":             err.Error(),
+		}).Warn("Invalid request body")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid request", nil)
+		return
+	}
+
+	po, err := h.purchaseOrderUseCase.ReceiveGoods(r.Context(), id, &req)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":           "purchase_order_handler",
+			"action":            "receive_goods",
+			"purchase_order_id": id,
+			"error":             err.Error(),
+		}).Error("Failed to receive goods")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":           "purchase_order_handler",
+		"action":            "receive_goods",
+		"purchase_order_id": id,
+		"status":            po.Status,
+	}).Info("Goods received successfully")
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Goods received successfully", po)
+}
+
+// parsePurchaseOrderID membaca {id} dari path dan menulis 400 jika tidak valid
+func parsePurchaseOrderID(w http.ResponseWriter, r *http.Request, action string) (int, bool) {
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "purchase_order_handler",
+			"action":  action,
+			"id_str":  idStr,
+		}).Warn("Invalid purchase order ID format")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid Purchase Order ID", nil)
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *PurchaseOrderHandler) HandlePurchaseOrders(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetPurchaseOrders(w, r)
+	case http.MethodPost:
+		h.CreatePurchaseOrder(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *PurchaseOrderHandler) HandlePurchaseOrderByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetPurchaseOrderByID(w, r)
+	case http.MethodPut:
+		h.UpdatePurchaseOrder(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *PurchaseOrderHandler) HandleSupplierPurchaseOrders(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetSupplierPurchaseOrders(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *PurchaseOrderHandler) HandleSendPurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.SendPurchaseOrder(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *PurchaseOrderHandler) HandleCancelPurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.CancelPurchaseOrder(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *PurchaseOrderHandler) HandleReceiveGoods(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.ReceiveGoods(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
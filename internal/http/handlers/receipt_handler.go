@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"fmt"
+	"kasir-api/internal/domain/receipt"
+	"kasir-api/internal/domain/usecases"
+	"kasir-api/internal/pkg"
+	"net/http"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+type ReceiptHandler struct {
+	receiptUseCase usecases.ReceiptUseCase
+}
+
+func NewReceiptHandler(receiptUseCase usecases.ReceiptUseCase) *ReceiptHandler {
+	return &ReceiptHandler{receiptUseCase: receiptUseCase}
+}
+
+// @Summary Get Receipt
+// @Description Struk transaksi sebagai teks monospace, byte stream ESC/POS untuk printer thermal, atau PDF
+// @Tags Transaction
+// @Produce plain
+// @Produce octet-stream
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param id path int true "Transaction ID"
+// @Param format query string false "escpos, text atau pdf (default text)"
+// @Param paper query int false "Lebar kertas dalam mm: 58 atau 80 (default 80)"
+// @Success 200 {file} file
+// @Router /api/transaction/{id}/receipt [get]
+func (h *ReceiptHandler) GetReceipt(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	transactionID, err := strconv.Atoi(idStr)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "receipt_handler",
+			"action":  "get_receipt",
+			"id_str":  idStr,
+		}).Warn("Invalid transaction ID format")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid Transaction ID", nil)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = receipt.FormatText
+	}
+	paperMM := receipt.Paper80mm
+	if v := r.URL.Query().Get("paper"); v != "" {
+		paperMM, err = strconv.Atoi(v)
+		if err != nil {
+			pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+				"handler": "receipt_handler",
+				"action":  "get_receipt",
+				"paper":   v,
+			}).Warn("Invalid query parameter")
+			pkg.ResponseError(w, http.StatusBadRequest, "invalid paper", nil)
+			return
+		}
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":        "receipt_handler",
+		"action":         "get_receipt",
+		"transaction_id": transactionID,
+		"format":         format,
+		"paper_mm":       paperMM,
+	}).Info("Get receipt handler called")
+
+	body, err := h.receiptUseCase.RenderReceipt(r.Context(), transactionID, format, paperMM)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":        "receipt_handler",
+			"action":         "get_receipt",
+			"transaction_id": transactionID,
+			"error":          err.Error(),
+		}).Error("Failed to render receipt")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	switch format {
+	case receipt.FormatESCPOS:
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="receipt-TRX-%d.bin"`, transactionID))
+	case receipt.FormatPDF:
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="receipt-TRX-%d.pdf"`, transactionID))
+	default:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":        "receipt_handler",
+			"action":         "get_receipt",
+			"transaction_id": transactionID,
+			"error":          err.Error(),
+		}).Warn("Failed to write receipt")
+	}
+}
+
+func (h *ReceiptHandler) HandleReceipt(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetReceipt(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
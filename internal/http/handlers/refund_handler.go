@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/domain/usecases"
+	"kasir-api/internal/pkg"
+	"net/http"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+type RefundHandler struct {
+	refundUseCase usecases.RefundUseCase
+}
+
+func NewRefundHandler(refundUseCase usecases.RefundUseCase) *RefundHandler {
+	return &RefundHandler{refundUseCase: refundUseCase}
+}
+
+// @Summary Refund Transaction
+// @Description Refund penuh (items kosong) atau parsial per baris dan jumlah unit, dengan opsi mengembalikan barang ke stok
+// @Tags Refund
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Transaction ID"
+// @Param body body models.RefundRequest true "Refund Request"
+// @Success 201 {object} pkg.ResponsePayload
+// @Router /api/transaction/{id}/refund [post]
+func (h *RefundHandler) CreateRefund(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	transactionID, err := strconv.Atoi(idStr)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "refund_handler",
+			"action":  "create_refund",
+			"id_str":  idStr,
+		}).Warn("Invalid transaction ID format")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid Transaction ID", nil)
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":        "refund_handler",
+		"action":         "create_refund",
+		"transaction_id": transactionID,
+	}).Info("Create refund handler called")
+
+	var req models.RefundRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "refund_handler",
+			"action":  "create_refund",
+			"error":   err.Error(),
+		}).Warn("Invalid request body")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid request", nil)
+		return
+	}
+
+	refund, err := h.refundUseCase.CreateRefund(r.Context(), transactionID, &req)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":        "refund_handler",
+			"action":         "create_refund",
+			"transaction_id": transactionID,
+			"error":          err.Error(),
+		}).Error("Failed to create refund")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":        "refund_handler",
+		"action":         "create_refund",
+		"transaction_id": transactionID,
+		"refund_id":      refund.ID,
+	}).Info("Refund created successfully")
+
+	pkg.ResponseSuccess(w, http.StatusCreated, "Refund created successfully", refund)
+}
+
+// @Summary Get Refund By ID
+// @Description Dokumen refund beserta baris yang dikembalikan
+// @Tags Refund
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Refund ID"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/refund/{id} [get]
+func (h *RefundHandler) GetRefundByID(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "refund_handler",
+			"action":  "get_refund_by_id",
+			"id_str":  idStr,
+		}).Warn("Invalid refund ID format")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid Refund ID", nil)
+		return
+	}
+
+	refund, err := h.refundUseCase.GetRefundByID(r.Context(), id)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":   "refund_handler",
+			"action":    "get_refund_by_id",
+			"refund_id": id,
+			"error":     err.Error(),
+		}).Error("Failed to get refund")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Refund found", refund)
+}
+
+func (h *RefundHandler) HandleRefund(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.CreateRefund(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *RefundHandler) HandleRefundByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetRefundByID(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"kasir-api/internal/domain/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type fakeRefundUseCase struct {
+	err    error
+	called bool
+}
+
+func (f *fakeRefundUseCase) CreateRefund(ctx context.Context, transactionID int, req *models.RefundRequest) (*models.Refund, error) {
+	f.called = true
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &models.Refund{ID: 1, TransactionID: transactionID}, nil
+}
+
+func (f *fakeRefundUseCase) GetRefundByID(ctx context.Context, id int) (*models.Refund, error) {
+	f.called = true
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &models.Refund{ID: id}, nil
+}
+
+func TestCreateRefundStatusCodes(t *testing.T) {
+	body := `{"reason":"damaged","method":"cash","restock":true,"items":[{"transaction_item_id":10,"quantity":1}]}`
+	tests := []struct {
+		name   string
+		id     string
+		body   string
+		err    error
+		code   int
+		called bool
+	}{
+		{name: "success", id: "42", body: body, code: http.StatusCreated, called: true},
+		{name: "invalid transaction id", id: "x", body: body, code: http.StatusBadRequest},
+		{name: "malformed body", id: "42", body: `[`, code: http.StatusBadRequest},
+		{name: "validation", id: "42", body: body, err: models.NewValidationError("reason", "reason must be one of customer_request, damaged, wrong_item, expired, other"),
+			code: http.StatusBadRequest, called: true},
+		{name: "unknown transaction", id: "42", body: body, err: models.NewNotFoundError("transaction"), code: http.StatusNotFound, called: true},
+		{name: "more than remaining", id: "42", body: body, err: models.NewConflictError("only 1 of Sabun can still be refunded"),
+			code: http.StatusConflict, called: true},
+		{name: "no open shift", id: "42", body: body, err: models.NewConflictError("no open shift, open a shift before refund"),
+			code: http.StatusConflict, called: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &fakeRefundUseCase{err: tt.err}
+			h := NewRefundHandler(uc)
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/transaction/"+tt.id+"/refund", strings.NewReader(tt.body))
+			req.SetPathValue("id", tt.id)
+			h.CreateRefund(rec, req)
+
+			if rec.Code != tt.code {
+				t.Errorf("status = %d, want %d (body %s)", rec.Code, tt.code, rec.Body.String())
+			}
+			if uc.called != tt.called {
+				t.Errorf("use case called = %v, want %v", uc.called, tt.called)
+			}
+		})
+	}
+}
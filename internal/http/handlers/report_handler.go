@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"kasir-api/internal/domain/usecases"
+	"kasir-api/internal/pkg"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+type ReportHandler struct {
+	reportUseCase usecases.ReportUseCase
+}
+
+func NewReportHandler(reportUseCase usecases.ReportUseCase) *ReportHandler {
+	return &ReportHandler{reportUseCase: reportUseCase}
+}
+
+// @Summary Sales Report
+// @Description Penjualan bersih, HPP dan margin kotor per produk untuk satu periode (zona waktu toko)
+// @Tags Report
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Tanggal awal YYYY-MM-DD (default awal bulan ini)"
+// @Param to query string false "Tanggal akhir YYYY-MM-DD, inklusif (default hari ini)"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/report/sales [get]
+func (h *ReportHandler) GetSalesReport(w http.ResponseWriter, r *http.Request) {
+	from := r.URL.Query().Get("from")
+	to := r.URL.Query().Get("to")
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler": "report_handler",
+		"action":  "get_sales_report",
+		"from":    from,
+		"to":      to,
+	}).Info("Get sales report handler called")
+
+	report, err := h.reportUseCase.GetSalesReport(r.Context(), from, to)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "report_handler",
+			"action":  "get_sales_report",
+			"error":   err.Error(),
+		}).Error("Failed to get sales report")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Sales report", report)
+}
+
+func (h *ReportHandler) HandleSalesReport(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetSalesReport(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/domain/usecases"
+	"kasir-api/internal/pkg"
+	"net/http"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+type ShiftHandler struct {
+	shiftUseCase usecases.ShiftUseCase
+}
+
+func NewShiftHandler(shiftUseCase usecases.ShiftUseCase) *ShiftHandler {
+	return &ShiftHandler{shiftUseCase: shiftUseCase}
+}
+
+// @Summary Open Shift
+// @Description Buka shift kasir dengan modal awal laci (opening float)
+// @Tags Shift
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body models.OpenShiftRequest true "Open Shift Request"
+// @Success 201 {object} pkg.ResponsePayload
+// @Router /api/shift/open [post]
+func (h *ShiftHandler) OpenShift(w http.ResponseWriter, r *http.Request) {
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler": "shift_handler",
+		"action":  "open_shift",
+		"method":  r.Method,
+	}).Info("Open shift handler called")
+
+	var req models.OpenShiftRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "shift_handler",
+			"action":  "open_shift",
+			"error":   err.Error(),
+		}).Warn("Invalid request body")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid request", nil)
+		return
+	}
+
+	shift, err := h.shiftUseCase.OpenShift(r.Context(), &req)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "shift_handler",
+			"action":  "open_shift",
+			"error":   err.Error(),
+		}).Error("Failed to open shift")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":  "shift_handler",
+		"action":   "open_shift",
+		"shift_id": shift.ID,
+	}).Info("Shift opened successfully")
+
+	pkg.ResponseSuccess(w, http.StatusCreated, "Shift opened successfully", shift)
+}
+
+// @Summary Get Current Shift
+// @Description Rekap berjalan dari shift yang sedang dibuka oleh user yang login
+// @Tags Shift
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/shift/current [get]
+func (h *ShiftHandler) GetCurrentShift(w http.ResponseWriter, r *http.Request) {
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler": "shift_handler",
+		"action":  "get_current_shift",
+		"method":  r.Method,
+	}).Info("Get current shift handler called")
+
+	report, err := h.shiftUseCase.GetCurrentShift(r.Context())
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "shift_handler",
+			"action":  "get_current_shift",
+			"error":   err.Error(),
+		}).Warn("Failed to get current shift")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Shift found", report)
+}
+
+// @Summary Get Shift Report
+// @Description Rekap penjualan, pembayaran per metode, kas masuk/keluar dan selisih kas sebuah shift
+// @Tags Shift
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Shift ID"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/shift/{id} [get]
+func (h *ShiftHandler) GetShiftReport(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseShiftID(w, r, "get_shift_report")
+	if !ok {
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":  "shift_handler",
+		"action":   "get_shift_report",
+		"shift_id": id,
+	}).Info("Get shift report handler called")
+
+	report, err := h.shiftUseCase.GetShiftReport(r.Context(), id)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":  "shift_handler",
+			"action":   "get_shift_report",
+			"shift_id": id,
+			"error":    err.Error(),
+		}).Error("Failed to get shift report")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Shift found", report)
+}
+
+// @Summary Add Cash Event
+// @Description Catat kas masuk (cash_in) atau kas keluar (cash_out) laci, misalnya petty cash atau setoran ke brankas
+// @Tags Shift
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Shift ID"
+// @Param body body models.CashEventRequest true "Cash Event Request"
+// @Success 201 {object} pkg.ResponsePayload
+// @Router /api/shift/{id}/cash [post]
+func (h *ShiftHandler) AddCashEvent(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseShiftID(w, r, "add_cash_event")
+	if !ok {
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":  "shift_handler",
+		"action":   "add_cash_event",
+		"shift_id": id,
+	}).Info("Add cash event handler called")
+
+	var req models.CashEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "shift_handler",
+			"action":  "add_cash_event",
+			"error":   err.Error(),
+		}).Warn("Invalid request body")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid request", nil)
+		return
+	}
+
+	event, err := h.shiftUseCase.AddCashEvent(r.Context(), id, &req)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":  "shift_handler",
+			"action":   "add_cash_event",
+			"shift_id": id,
+			"error":    err.Error(),
+		}).Error("Failed to add cash event")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":  "shift_handler",
+		"action":   "add_cash_event",
+		"shift_id": id,
+		"event_id": event.ID,
+	}).Info("Cash event recorded successfully")
+
+	pkg.ResponseSuccess(w, http.StatusCreated, "Cash event recorded successfully", event)
+}
+
+// @Summary Close Shift
+// @Description Tutup shift dengan jumlah uang hasil hitung laci, mengembalikan laporan expected vs actual
+// @Tags Shift
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Shift ID"
+// @Param body body models.CloseShiftRequest true "Close Shift Request"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/shift/{id}/close [post]
+func (h *ShiftHandler) CloseShift(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseShiftID(w, r, "close_shift")
+	if !ok {
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":  "shift_handler",
+		"action":   "close_shift",
+		"shift_id": id,
+	}).Info("Close shift handler called")
+
+	var req models.CloseShiftRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "shift_handler",
+			"action":  "close_shift",
+			"error":   err.Error(),
+		}).Warn("Invalid request body")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid request", nil)
+		return
+	}
+
+	report, err := h.shiftUseCase.CloseShift(r.Context(), id, &req)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":  "shift_handler",
+			"action":   "close_shift",
+			"shift_id": id,
+			"error":    err.Error(),
+		}).Error("Failed to close shift")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":  "shift_handler",
+		"action":   "close_shift",
+		"shift_id": id,
+		"variance": *report.Variance,
+	}).Info("Shift closed successfully")
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Shift closed successfully", report)
+}
+
+// parseShiftID membaca {id} dari path dan menulis 400 jika tidak valid
+func parseShiftID(w http.ResponseWriter, r *http.Request, action string) (int, bool) {
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "shift_handler",
+			"action":  action,
+			"id_str":  idStr,
+		}).Warn("Invalid shift ID format")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid Shift ID", nil)
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *ShiftHandler) HandleOpenShift(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.OpenShift(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ShiftHandler) HandleCurrentShift(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetCurrentShift(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ShiftHandler) HandleShiftByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetShiftReport(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ShiftHandler) HandleCashEvent(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.AddCashEvent(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ShiftHandler) HandleCloseShift(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.CloseShift(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/domain/usecases"
+	"kasir-api/internal/pkg"
+	"net/http"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+type StockHandler struct {
+	stockUseCase usecases.StockUseCase
+}
+
+func NewStockHandler(stockUseCase usecases.StockUseCase) *StockHandler {
+	return &StockHandler{stockUseCase: stockUseCase}
+}
+
+// @Summary Adjust Stock
+// @Description Catat pergerakan stok (restock, adjustment, return, damage) dan perbarui stok produk
+// @Tags Stock
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param body body models.StockAdjustmentRequest true "Stock Adjustment Request"
+// @Success 201 {object} pkg.ResponsePayload
+// @Router /api/product/{id}/stock [post]
+func (h *StockHandler) AdjustStock(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "stock_handler",
+			"action":  "adjust_stock",
+			"id_str":  idStr,
+		}).Warn("Invalid product ID format")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid Product ID", nil)
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":    "stock_handler",
+		"action":     "adjust_stock",
+		"product_id": id,
+	}).Info("Adjust stock handler called")
+
+	var req models.StockAdjustmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "stock_handler",
+			"action":  "adjust_stock",
+			"error":   err.Error(),
+		}).Warn("Invalid request body")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid request", nil)
+		return
+	}
+
+	movement, err := h.stockUseCase.AdjustStock(r.Context(), id, &req)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":    "stock_handler",
+			"action":     "adjust_stock",
+			"product_id": id,
+			"error":      err.Error(),
+		}).Error("Failed to adjust stock")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":     "stock_handler",
+		"action":      "adjust_stock",
+		"product_id":  id,
+		"movement_id": movement.ID,
+	}).Info("Stock adjusted successfully")
+
+	pkg.ResponseSuccess(w, http.StatusCreated, "Stock adjusted successfully", movement)
+}
+
+// @Summary Get Stock History
+// @Description Riwayat pergerakan stok produk, terbaru dulu
+// @Tags Stock
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param page query int false "Halaman (default 1)"
+// @Param per_page query int false "Jumlah per halaman (default 20, max 100)"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/product/{id}/stock-history [get]
+func (h *StockHandler) GetStockHistory(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "stock_handler",
+			"action":  "get_stock_history",
+			"id_str":  idStr,
+		}).Warn("Invalid product ID format")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid Product ID", nil)
+		return
+	}
+
+	page, perPage, err := parsePageParams(r)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "stock_handler",
+			"action":  "get_stock_history",
+			"error":   err.Error(),
+		}).Warn("Invalid query parameter")
+		pkg.ResponseError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":    "stock_handler",
+		"action":     "get_stock_history",
+		"product_id": id,
+	}).Info("Get stock history handler called")
+
+	result, err := h.stockUseCase.GetStockHistory(r.Context(), id, page, perPage)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":    "stock_handler",
+			"action":     "get_stock_history",
+			"product_id": id,
+			"error":      err.Error(),
+		}).Error("Failed to get stock history")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":    "stock_handler",
+		"action":     "get_stock_history",
+		"product_id": id,
+		"count":      len(result.Movements),
+	}).Info("Stock history retrieved successfully")
+
+	pkg.ResponseSuccessWithMeta(w, http.StatusOK, "Stock history retrieved successfully", result.Movements, result.Meta)
+}
+
+func (h *StockHandler) HandleStock(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.AdjustStock(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *StockHandler) HandleStockHistory(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetStockHistory(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
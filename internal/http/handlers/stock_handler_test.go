@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"kasir-api/internal/domain/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type fakeStockUseCase struct {
+	err    error
+	called bool
+}
+
+func (f *fakeStockUseCase) AdjustStock(ctx context.Context, productID int, req *models.StockAdjustmentRequest) (*models.StockMovement, error) {
+	f.called = true
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &models.StockMovement{ID: 1, ProductID: productID, Delta: req.Delta, Reason: req.Reason}, nil
+}
+
+func (f *fakeStockUseCase) GetStockHistory(ctx context.Context, productID, page, perPage int) (*models.StockMovementList, error) {
+	f.called = true
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &models.StockMovementList{Movements: []models.StockMovement{}}, nil
+}
+
+func TestAdjustStockStatusCodes(t *testing.T) {
+	body := `{"delta":-3,"reason":"damage"}`
+	tests := []struct {
+		name   string
+		id     string
+		body   string
+		err    error
+		code   int
+		called bool
+	}{
+		{name: "success", id: "1", body: body, code: http.StatusCreated, called: true},
+		{name: "invalid product id", id: "x", body: body, code: http.StatusBadRequest},
+		{name: "malformed body", id: "1", body: `{"delta":`, code: http.StatusBadRequest},
+		{name: "validation", id: "1", body: body, err: models.NewValidationError("delta", "delta must be negative for reason damage"),
+			code: http.StatusBadRequest, called: true},
+		{name: "unknown product", id: "1", body: body, err: models.NewNotFoundError("product"), code: http.StatusNotFound, called: true},
+		{name: "insufficient stock", id: "1", body: body, err: models.NewConflictError("insufficient stock for Teh Botol"),
+			code: http.StatusConflict, called: true},
+		{name: "frozen by stock opname", id: "1", body: body, err: models.NewConflictError("stock of Teh Botol is frozen while stock opname #4 is open"),
+			code: http.StatusConflict, called: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &fakeStockUseCase{err: tt.err}
+			h := NewStockHandler(uc)
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/product/"+tt.id+"/stock", strings.NewReader(tt.body))
+			req.SetPathValue("id", tt.id)
+			h.AdjustStock(rec, req)
+
+			if rec.Code != tt.code {
+				t.Errorf("status = %d, want %d (body %s)", rec.Code, tt.code, rec.Body.String())
+			}
+			if uc.called != tt.called {
+				t.Errorf("use case called = %v, want %v", uc.called, tt.called)
+			}
+		})
+	}
+}
+
+func TestGetStockHistoryStatusCodes(t *testing.T) {
+	tests := []struct {
+		name  string
+		id    string
+		query string
+		err   error
+		code  int
+	}{
+		{name: "success", id: "1", code: http.StatusOK},
+		{name: "invalid product id", id: "x", code: http.StatusBadRequest},
+		{name: "invalid page", id: "1", query: "?page=abc", code: http.StatusBadRequest},
+		{name: "unknown product", id: "1", err: models.NewNotFoundError("product"), code: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewStockHandler(&fakeStockUseCase{err: tt.err})
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/product/"+tt.id+"/stock/history"+tt.query, nil)
+			req.SetPathValue("id", tt.id)
+			h.GetStockHistory(rec, req)
+
+			if rec.Code != tt.code {
+				t.Errorf("status = %d, want %d (body %s)", rec.Code, tt.code, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,404 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/domain/usecases"
+	"kasir-api/internal/pkg"
+	"net/http"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+type StockOpnameHandler struct {
+	stockOpnameUseCase usecases.StockOpnameUseCase
+}
+
+func NewStockOpnameHandler(stockOpnameUseCase usecases.StockOpnameUseCase) *StockOpnameHandler {
+	return &StockOpnameHandler{stockOpnameUseCase: stockOpnameUseCase}
+}
+
+// @Summary Get Stock Opnames
+// @Description Daftar sesi stock opname, terbaru dulu
+// @Tags Stock Opname
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "open, approved atau cancelled"
+// @Param page query int false "Halaman (default 1)"
+// @Param per_page query int false "Jumlah per halaman (default 20, maksimal 100)"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/stock-opnames [get]
+func (h *StockOpnameHandler) GetStockOpnames(w http.ResponseWriter, r *http.Request) {
+	page, perPage, err := parsePageParams(r)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "stock_opname_handler",
+			"action":  "get_stock_opnames",
+			"error":   err.Error(),
+		}).Warn("Invalid query parameter")
+		pkg.ResponseError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler": "stock_opname_handler",
+		"action":  "get_stock_opnames",
+		"status":  status,
+	}).Info("Get stock opnames handler called")
+
+	result, err := h.stockOpnameUseCase.GetStockOpnames(r.Context(), status, page, perPage)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "stock_opname_handler",
+			"action":  "get_stock_opnames",
+			"error":   err.Error(),
+		}).Error("Failed to get stock opnames")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.ResponseSuccessWithMeta(w, http.StatusOK, "Stock opnames retrieved successfully", result.Opnames, result.Meta)
+}
+
+// @Summary Create Stock Opname
+// @Description Buka sesi stock opname dan snapshot stok sistem semua produk, atau satu kategori. Hanya satu sesi yang boleh open.
+// @Tags Stock Opname
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body models.CreateStockOpnameRequest true "Create Stock Opname Request"
+// @Success 201 {object} pkg.ResponsePayload
+// @Router /api/stock-opnames [post]
+func (h *StockOpnameHandler) CreateStockOpname(w http.ResponseWriter, r *http.Request) {
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler": "stock_opname_handler",
+		"action":  "create_stock_opname",
+		"method":  r.Method,
+	}).Info("Create stock opname handler called")
+
+	var req models.CreateStockOpnameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "stock_opname_handler",
+			"action":  "create_stock_opname",
+			"error":   err.Error(),
+		}).Warn("Invalid request body")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid request", nil)
+		return
+	}
+
+	opname, err := h.stockOpnameUseCase.CreateStockOpname(r.Context(), &req)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "stock_opname_handler",
+			"action":  "create_stock_opname",
+			"error":   err.Error(),
+		}).Error("Failed to create stock opname")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":   "stock_opname_handler",
+		"action":    "create_stock_opname",
+		"opname_id": opname.ID,
+	}).Info("Stock opname created successfully")
+
+	pkg.ResponseSuccess(w, http.StatusCreated, "Stock opname created successfully", opname)
+}
+
+// @Summary Get Stock Opname By ID
+// @Description Status sesi stock opname beserta jumlah item yang sudah dihitung. Stok sistem tidak ditampilkan supaya penghitung tetap menghitung buta.
+// @Tags Stock Opname
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Stock Opname ID"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/stock-opnames/{id} [get]
+func (h *StockOpnameHandler) GetStockOpnameByID(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseStockOpnameID(w, r, "get_stock_opname_by_id")
+	if !ok {
+		return
+	}
+
+	opname, err := h.stockOpnameUseCase.GetStockOpnameByID(r.Context(), id)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":   "stock_opname_handler",
+			"action":    "get_stock_opname_by_id",
+			"opname_id": id,
+			"error":     err.Error(),
+		}).Error("Failed to get stock opname")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Stock opname found", opname)
+}
+
+// @Summary Get Stock Opname Variance
+// @Description Selisih hasil hitung terhadap snapshot beserta nilainya dengan harga pokok saat sesi dibuka
+// @Tags Stock Opname
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Stock Opname ID"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/stock-opnames/{id}/variance [get]
+func (h *StockOpnameHandler) GetVariance(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseStockOpnameID(w, r, "get_variance")
+	if !ok {
+		return
+	}
+
+	report, err := h.stockOpnameUseCase.GetVariance(r.Context(), id)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":   "stock_opname_handler",
+			"action":    "get_variance",
+			"opname_id": id,
+			"error":     err.Error(),
+		}).Error("Failed to get stock opname variance")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Stock opname variance retrieved successfully", report)
+}
+
+// @Summary Submit Stock Opname Counts
+// @Description Kirim hasil hitung beberapa produk sekaligus. Quantity menimpa hasil hitung user yang sedang login, hasil penghitung lain dijumlahkan.
+// @Tags Stock Opname
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Stock Opname ID"
+// @Param body body models.StockOpnameCountRequest true "Stock Opname Count Request"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/stock-opnames/{id}/counts [post]
+func (h *StockOpnameHandler) SubmitCounts(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseStockOpnameID(w, r, "submit_counts")
+	if !ok {
+		return
+	}
+
+	var req models.StockOpnameCountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":   "stock_opname_handler",
+			"action":    "submit_counts",
+			"opname_id": id,
+			"error":     err.Error(),
+		}).Warn("Invalid request body")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid request", nil)
+		return
+	}
+
+	counts, err := h.stockOpnameUseCase.SubmitCounts(r.Context(), id, &req)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":   "stock_opname_handler",
+			"action":    "submit_counts",
+			"opname_id": id,
+			"error":     err.Error(),
+		}).Error("Failed to submit stock opname counts")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Counts saved", counts)
+}
+
+// @Summary Scan Stock Opname Count
+// @Description Tambah hasil hitung barang yang di-scan, default 1 atau berat di label timbangan
+// @Tags Stock Opname
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Stock Opname ID"
+// @Param body body models.StockOpnameScanRequest true "Stock Opname Scan Request"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/stock-opnames/{id}/scan [post]
+func (h *StockOpnameHandler) ScanCount(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseStockOpnameID(w, r, "scan_count")
+	if !ok {
+		return
+	}
+
+	var req models.StockOpnameScanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":   "stock_opname_handler",
+			"action":    "scan_count",
+			"opname_id": id,
+			"error":     err.Error(),
+		}).Warn("Invalid request body")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid request", nil)
+		return
+	}
+
+	count, err := h.stockOpnameUseCase.ScanCount(r.Context(), id, &req)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":   "stock_opname_handler",
+			"action":    "scan_count",
+			"opname_id": id,
+			"error":     err.Error(),
+		}).Error("Failed to scan stock opname count")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Count saved", count)
+}
+
+// @Summary Approve Stock Opname
+// @Description Posting adjustment untuk setiap selisih dalam satu transaksi dan tutup sesi. Item yang belum dihitung tidak disesuaikan.
+// @Tags Stock Opname
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Stock Opname ID"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/stock-opnames/{id}/approve [post]
+func (h *StockOpnameHandler) ApproveStockOpname(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseStockOpnameID(w, r, "approve_stock_opname")
+	if !ok {
+		return
+	}
+
+	report, err := h.stockOpnameUseCase.ApproveStockOpname(r.Context(), id)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":   "stock_opname_handler",
+			"action":    "approve_stock_opname",
+			"opname_id": id,
+			"error":     err.Error(),
+		}).Error("Failed to approve stock opname")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":   "stock_opname_handler",
+		"action":    "approve_stock_opname",
+		"opname_id": id,
+	}).Info("Stock opname approved successfully")
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Stock opname approved", report)
+}
+
+// @Summary Cancel Stock Opname
+// @Description Tutup sesi stock opname tanpa mengubah stok
+// @Tags Stock Opname
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Stock Opname ID"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/stock-opnames/{id}/cancel [post]
+func (h *StockOpnameHandler) CancelStockOpname(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseStockOpnameID(w, r, "cancel_stock_opname")
+	if !ok {
+		return
+	}
+
+	opname, err := h.stockOpnameUseCase.CancelStockOpname(r.Context(), id)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":   "stock_opname_handler",
+			"action":    "cancel_stock_opname",
+			"opname_id": id,
+			"error":     err.Error(),
+		}).Error("Failed to cancel stock opname")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Stock opname cancelled", opname)
+}
+
+// parseStockOpnameID membaca {id} dari path dan menulis 400 jika tidak valid
+func parseStockOpnameID(w http.ResponseWriter, r *http.Request, action string) (int, bool) {
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "stock_opname_handler",
+			"action":  action,
+			"id_str":  idStr,
+		}).Warn("Invalid stock opname ID format")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid Stock Opname ID", nil)
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *StockOpnameHandler) HandleStockOpnames(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetStockOpnames(w, r)
+	case http.MethodPost:
+		h.CreateStockOpname(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *StockOpnameHandler) HandleStockOpnameByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetStockOpnameByID(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *StockOpnameHandler) HandleVariance(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetVariance(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *StockOpnameHandler) HandleCounts(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.SubmitCounts(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *StockOpnameHandler) HandleScan(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.ScanCount(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *StockOpnameHandler) HandleApprove(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.ApproveStockOpname(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *StockOpnameHandler) HandleCancel(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.CancelStockOpname(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/domain/usecases"
+	"kasir-api/internal/pkg"
+	"net/http"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+type SupplierHandler struct {
+	supplierUseCase usecases.SupplierUseCase
+}
+
+func NewSupplierHandler(supplierUseCase usecases.SupplierUseCase) *SupplierHandler {
+	return &SupplierHandler{supplierUseCase: supplierUseCase}
+}
+
+// @Summary Get All Suppliers
+// @Description Daftar semua supplier, urut nama
+// @Tags Supplier
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/suppliers [get]
+func (h *SupplierHandler) GetAllSuppliers(w http.ResponseWriter, r *http.Request) {
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler": "supplier_handler",
+		"action":  "get_all_suppliers",
+		"method":  r.Method,
+	}).Info("Get all suppliers handler called")
+
+	suppliers, err := h.supplierUseCase.GetAllSuppliers(r.Context())
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "supplier_handler",
+			"action":  "get_all_suppliers",
+			"error":   err.Error(),
+		}).Error("Failed to get suppliers")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.ResponseSuccess(w, http.StatusOK, "success", suppliers)
+}
+
+// @Summary Create Supplier
+// @Description Tambah supplier baru. Nama supplier unik tanpa membedakan huruf besar/kecil.
+// @Tags Supplier
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body models.Supplier true "Create Supplier Request"
+// @Success 201 {object} pkg.ResponsePayload
+// @Router /api/suppliers [post]
+func (h *SupplierHandler) CreateSupplier(w http.ResponseWriter, r *http.Request) {
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler": "supplier_handler",
+		"action":  "create_supplier",
+		"method":  r.Method,
+	}).Info("Create supplier handler called")
+
+	var supplier models.Supplier
+	if err := json.NewDecoder(r.Body).Decode(&supplier); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "supplier_handler",
+			"action":  "create_supplier",
+			"error":   err.Error(),
+		}).Warn("Invalid request body")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid request", nil)
+		return
+	}
+
+	if err := h.supplierUseCase.CreateSupplier(r.Context(), &supplier); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "supplier_handler",
+			"action":  "create_supplier",
+			"error":   err.Error(),
+		}).Error("Failed to create supplier")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":     "supplier_handler",
+		"action":      "create_supplier",
+		"supplier_id": supplier.ID,
+	}).Info("Supplier created successfully")
+
+	pkg.ResponseSuccess(w, http.StatusCreated, "Supplier created successfully", supplier)
+}
+
+// @Summary Get Supplier By ID
+// @Description Get Supplier By ID
+// @Tags Supplier
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Supplier ID"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/suppliers/{id} [get]
+func (h *SupplierHandler) GetSupplierByID(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseSupplierID(w, r, "get_supplier_by_id")
+	if !ok {
+		return
+	}
+
+	supplier, err := h.supplierUseCase.GetSupplierByID(r.Context(), id)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":     "supplier_handler",
+			"action":      "get_supplier_by_id",
+			"supplier_id": id,
+			"error":       err.Error(),
+		}).Error("Failed to get supplier")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Supplier found", supplier)
+}
+
+// @Summary Update Supplier
+// @Description Ganti data supplier
+// @Tags Supplier
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Supplier ID"
+// @Param body body models.Supplier true "Update Supplier Request"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/suppliers/{id} [put]
+func (h *SupplierHandler) UpdateSupplier(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseSupplierID(w, r, "update_supplier")
+	if !ok {
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":     "supplier_handler",
+		"action":      "update_supplier",
+		"supplier_id": id,
+	}).Info("Update supplier handler called")
+
+	var supplier models.Supplier
+	if err := json.NewDecoder(r.Body).Decode(&supplier); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":     "supplier_handler",
+			"action":      "update_supplier",
+			"supplier_id": id,
+			"error":       err.Error(),
+		}).Warn("Invalid request body")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid request", nil)
+		return
+	}
+
+	supplier.ID = id
+	if err := h.supplierUseCase.UpdateSupplier(r.Context(), &supplier); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":     "supplier_handler",
+			"action":      "update_supplier",
+			"supplier_id": id,
+			"error":       err.Error(),
+		}).Error("Failed to update supplier")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":     "supplier_handler",
+		"action":      "update_supplier",
+		"supplier_id": id,
+	}).Info("Supplier updated successfully")
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Supplier updated successfully", supplier)
+}
+
+// @Summary Delete Supplier
+// @Description Hapus supplier. Supplier yang sudah punya purchase order tidak bisa dihapus.
+// @Tags Supplier
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Supplier ID"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/suppliers/{id} [delete]
+func (h *SupplierHandler) DeleteSupplier(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseSupplierID(w, r, "delete_supplier")
+	if !ok {
+		return
+	}
+
+	if err := h.supplierUseCase.DeleteSupplier(r.Context(), id); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":     "supplier_handler",
+			"action":      "delete_supplier",
+			"supplier_id": id,
+			"error":       err.Error(),
+		}).Error("Failed to delete supplier")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":     "supplier_handler",
+		"action":      "delete_supplier",
+		"supplier_id": id,
+	}).Info("Supplier deleted successfully")
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Supplier deleted successfully", nil)
+}
+
+// parseSupplierID membaca {id} dari path dan menulis 400 jika tidak valid
+func parseSupplierID(w http.ResponseWriter, r *http.Request, action string) (int, bool) {
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "supplier_handler",
+			"action":  action,
+			"id_str":  idStr,
+		}).Warn("Invalid supplier ID format")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid Supplier ID", nil)
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *SupplierHandler) HandleSuppliers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAllSuppliers(w, r)
+	case http.MethodPost:
+		h.CreateSupplier(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *SupplierHandler) HandleSupplierByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetSupplierByID(w, r)
+	case http.MethodPut:
+		h.UpdateSupplier(w, r)
+	case http.MethodDelete:
+		h.DeleteSupplier(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/domain/usecases"
+	"kasir-api/internal/pkg"
+	"net/http"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+type TransactionHandler struct {
+	transactionUseCase usecases.TransactionUseCase
+}
+
+func NewTransactionHandler(transactionUseCase usecases.TransactionUseCase) *TransactionHandler {
+	return &TransactionHandler{transactionUseCase: transactionUseCase}
+}
+
+// @Summary Checkout
+// @Description Checkout keranjang beserta pembayaran (bisa split tender) dan kode promo opsional, simpan transaksi dan kurangi stok
+// @Tags Transaction
+// @Accept json
+// @Produce json
+// @Param body body models.CheckoutRequest true "Checkout Request"
+// @Success 201 {object} pkg.ResponsePayload
+// @Router /api/checkout [post]
+func (h *TransactionHandler) Checkout(w http.ResponseWriter, r *http.Request) {
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler": "transaction_handler",
+		"action":  "checkout",
+		"method":  r.Method,
+	}).Info("Checkout handler called")
+
+	var req models.CheckoutRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "transaction_handler",
+			"action":  "checkout",
+			"error":   err.Error(),
+		}).Warn("Invalid request body")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid request", nil)
+		return
+	}
+
+	transaction, err := h.transactionUseCase.Checkout(r.Context(), &req)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "transaction_handler",
+			"action":  "checkout",
+			"error":   err.Error(),
+		}).Error("Failed to checkout")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":        "transaction_handler",
+		"action":         "checkout",
+		"transaction_id": transaction.ID,
+	}).Info("Checkout successful")
+
+	pkg.ResponseSuccess(w, http.StatusCreated, "Checkout successful", transaction)
+}
+
+// @Summary Preview Checkout
+// @Description Hitung subtotal, diskon promo dan total keranjang tanpa menyimpan transaksi
+// @Tags Transaction
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body models.CheckoutRequest true "Checkout Request (payments diabaikan)"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/checkout/preview [post]
+func (h *TransactionHandler) PreviewCheckout(w http.ResponseWriter, r *http.Request) {
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler": "transaction_handler",
+		"action":  "preview_checkout",
+		"method":  r.Method,
+	}).Info("Preview checkout handler called")
+
+	var req models.CheckoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "transaction_handler",
+			"action":  "preview_checkout",
+			"error":   err.Error(),
+		}).Warn("Invalid request body")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid request", nil)
+		return
+	}
+
+	transaction, err := h.transactionUseCase.PreviewCheckout(r.Context(), &req)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "transaction_handler",
+			"action":  "preview_checkout",
+			"error":   err.Error(),
+		}).Warn("Failed to preview checkout")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Checkout preview", transaction)
+}
+
+// @Summary Get Transaction By ID
+// @Description Detail transaksi beserta item dan rincian pembayaran
+// @Tags Transaction
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Transaction ID"
+// @Success 200 {object} pkg.ResponsePayload
+// @Router /api/transaction/{id} [get]
+func (h *TransactionHandler) GetTransactionByID(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler": "transaction_handler",
+			"action":  "get_transaction_by_id",
+			"id_str":  idStr,
+		}).Warn("Invalid transaction ID format")
+		pkg.ResponseError(w, http.StatusBadRequest, "Invalid Transaction ID", nil)
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":        "transaction_handler",
+		"action":         "get_transaction_by_id",
+		"transaction_id": id,
+	}).Info("Get transaction by ID handler called")
+
+	transaction, err := h.transactionUseCase.GetTransactionByID(r.Context(), id)
+	if err != nil {
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+			"handler":        "transaction_handler",
+			"action":         "get_transaction_by_id",
+			"transaction_id": id,
+			"error":          err.Error(),
+		}).Error("Failed to get transaction")
+		pkg.ResponseFromError(w, err)
+		return
+	}
+
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler":        "transaction_handler",
+		"action":         "get_transaction_by_id",
+		"transaction_id": id,
+	}).Info("Transaction found")
+
+	pkg.ResponseSuccess(w, http.StatusOK, "Transaction found", transaction)
+}
+
+func (h *TransactionHandler) HandleCheckout(w http.ResponseWriter, r *http.Request) {
+	pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+		"handler": "transaction_handler",
+		"func":    "HandleCheckout",
+		"method":  r.Method,
+	}).Info("Routing dispatcher called")
+
+	switch r.Method {
+	case http.MethodPost:
+		h.Checkout(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *TransactionHandler) HandlePreviewCheckout(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.PreviewCheckout(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *TransactionHandler) HandleTransactionByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetTransactionByID(w, r)
+	default:
+		http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+	}
+}
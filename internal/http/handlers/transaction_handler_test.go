@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/pkg"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	pkg.InitLogger()
+	pkg.Log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// fakeTransactionUseCase mengembalikan transaksi atau error yang sudah
+// ditentukan test
+type fakeTransactionUseCase struct {
+	transaction *models.Transaction
+	err         error
+	called      bool
+}
+
+func (f *fakeTransactionUseCase) Checkout(ctx context.Context, req *models.CheckoutRequest) (*models.Transaction, error) {
+	f.called = true
+	return f.transaction, f.err
+}
+
+func (f *fakeTransactionUseCase) PreviewCheckout(ctx context.Context, req *models.CheckoutRequest) (*models.Transaction, error) {
+	f.called = true
+	return f.transaction, f.err
+}
+
+func (f *fakeTransactionUseCase) GetTransactionByID(ctx context.Context, id int) (*models.Transaction, error) {
+	f.called = true
+	return f.transaction, f.err
+}
+
+func TestCheckoutStatusCodes(t *testing.T) {
+	body := `{"items":[{"product_id":1,"quantity":2}],"payments":[{"method":"cash","amount":7000}]}`
+	tests := []struct {
+		name   string
+		body   string
+		err    error
+		code   int
+		called bool
+	}{
+		{name: "success", body: body, code: http.StatusCreated, called: true},
+		{name: "malformed body", body: `{"items":`, code: http.StatusBadRequest},
+		{name: "validation", body: body, err: models.NewValidationError("payments", "payments total 5000 does not match transaction total 7000"),
+			code: http.StatusBadRequest, called: true},
+		{name: "no authenticated user", body: body, err: models.NewUnauthorizedError("authenticated user is required for checkout"),
+			code: http.StatusUnauthorized, called: true},
+		{name: "unknown product", body: body, err: models.NewNotFoundError("product 1"), code: http.StatusNotFound, called: true},
+		{name: "insufficient stock", body: body, err: models.NewConflictError("insufficient stock for Teh Botol"),
+			code: http.StatusConflict, called: true},
+		{name: "price changed", body: body, err: models.NewConflictError("price of Teh Botol has changed, please retry checkout"),
+			code: http.StatusConflict, called: true},
+		{name: "no open shift", body: body, err: models.NewConflictError("no open shift, open a shift before checkout"),
+			code: http.StatusConflict, called: true},
+		{name: "timeout", body: body, err: context.DeadlineExceeded, code: http.StatusGatewayTimeout, called: true},
+		{name: "internal", body: body, err: errors.New("connection reset"), code: http.StatusInternalServerError, called: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &fakeTransactionUseCase{err: tt.err}
+			if tt.err == nil {
+				uc.transaction = &models.Transaction{ID: 1, TotalAmount: 7000}
+			}
+			h := NewTransactionHandler(uc)
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/checkout", strings.NewReader(tt.body))
+			h.Checkout(rec, req)
+
+			if rec.Code != tt.code {
+				t.Errorf("status = %d, want %d (body %s)", rec.Code, tt.code, rec.Body.String())
+			}
+			if uc.called != tt.called {
+				t.Errorf("use case called = %v, want %v", uc.called, tt.called)
+			}
+		})
+	}
+}
+
+func TestGetTransactionByIDStatusCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		err  error
+		code int
+	}{
+		{name: "found", id: "12", code: http.StatusOK},
+		{name: "invalid id", id: "abc", code: http.StatusBadRequest},
+		{name: "not found", id: "12", err: models.NewNotFoundError("transaction"), code: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &fakeTransactionUseCase{err: tt.err}
+			if tt.err == nil {
+				uc.transaction = &models.Transaction{ID: 12}
+			}
+			h := NewTransactionHandler(uc)
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/transaction/"+tt.id, nil)
+			req.SetPathValue("id", tt.id)
+			h.GetTransactionByID(rec, req)
+
+			if rec.Code != tt.code {
+				t.Errorf("status = %d, want %d (body %s)", rec.Code, tt.code, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"kasir-api/internal/domain/models"
+	"kasir-api/internal/pkg"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MethodRoles memetakan HTTP method ke role yang boleh mengaksesnya
+type MethodRoles map[string][]string
+
+// Auth memvalidasi access token dari header Authorization: Bearer <token>
+// dan menyimpan user-nya ke context request
+func Auth(secret string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tokenString, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !found || tokenString == "" {
+				pkg.ResponseError(w, http.StatusUnauthorized, "Missing bearer token", nil)
+				return
+			}
+
+			claims, err := pkg.ParseToken(secret, tokenString, pkg.TokenTypeAccess)
+			if err != nil {
+				pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+					"path":  r.URL.Path,
+					"error": err.Error(),
+				}).Warn("Invalid access token")
+				pkg.ResponseError(w, http.StatusUnauthorized, "Invalid or expired token", nil)
+				return
+			}
+
+			user := &models.AuthUser{
+				ID:       claims.UserID,
+				Username: claims.Username,
+				Role:     claims.Role,
+			}
+			ctx := pkg.WithAuthUser(r.Context(), user)
+			// Log selanjutnya untuk request ini otomatis berisi user yang login
+			ctx = pkg.WithLogger(ctx, pkg.LoggerFromContext(ctx).WithFields(logrus.Fields{
+				"user_id":  user.ID,
+				"username": user.Username,
+			}))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireRoles membatasi akses berdasarkan role user per HTTP method.
+// Method yang tidak terdaftar di rules ditolak dengan 405.
+// Harus dipasang setelah Auth.
+func RequireRoles(rules MethodRoles) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			roles, ok := rules[r.Method]
+			if !ok {
+				http.Error(w, "Request not found", http.StatusMethodNotAllowed)
+				return
+			}
+
+			user, ok := pkg.AuthUserFromContext(r.Context())
+			if !ok || !slices.Contains(roles, user.Role) {
+				pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
+					"path":   r.URL.Path,
+					"method": r.Method,
+				}).Warn("Access denied")
+				pkg.ResponseError(w, http.StatusForbidden, "Access denied", nil)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -44,7 +44,7 @@ func Logging(next http.Handler) http.Handler {
 
 		duration := time.Since(start)
 
-		pkg.Log.WithFields(logrus.Fields{
+		pkg.LoggerFromContext(r.Context()).WithFields(logrus.Fields{
 			"method":      r.Method,
 			"path":        r.URL.Path,
 			"status_code": wrapped.statusCode,
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"kasir-api/internal/pkg"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Metrics mencatat jumlah dan latency request ke Prometheus, dilabeli
+// dengan route pattern (bukan path mentah) supaya kardinalitas tetap kecil.
+// Harus dipasang langsung di atas ServeMux karena r.Pattern baru diisi
+// oleh mux saat request dicocokkan.
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		pkg.HTTPRequestsInFlight.Inc()
+		defer pkg.HTTPRequestsInFlight.Dec()
+
+		wrapped := &responseWriter{
+			ResponseWriter: w,
+			statusCode:     http.StatusOK,
+		}
+
+		next.ServeHTTP(wrapped, r)
+
+		route := r.Pattern
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(wrapped.statusCode)
+
+		pkg.HTTPRequestsTotal.WithLabelValues(r.Method, route, status).Inc()
+		pkg.HTTPRequestDuration.WithLabelValues(r.Method, route, status).Observe(time.Since(start).Seconds())
+	})
+}
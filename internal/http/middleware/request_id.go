@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"kasir-api/internal/pkg"
+	"net/http"
+)
+
+// RequestIDHeader adalah header yang dipakai untuk korelasi request
+const RequestIDHeader = "X-Request-ID"
+
+const maxRequestIDLength = 128
+
+// RequestID memakai X-Request-ID dari client (mis. dari load balancer) atau
+// membuat ID baru, mengembalikannya di response header, dan menyimpan logger
+// dengan field request_id ke context supaya semua log di handler, use case
+// dan repository untuk request ini bisa ditelusuri bersama.
+// Harus dipasang paling luar supaya log middleware lain ikut berisi request_id.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+
+		w.Header().Set(RequestIDHeader, requestID)
+
+		ctx := pkg.WithRequestID(r.Context(), requestID)
+		ctx = pkg.WithLogger(ctx, pkg.Log.WithField("request_id", requestID))
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// validRequestID menolak ID kosong, terlalu panjang, atau berisi karakter
+// non-printable supaya header dari client tidak bisa merusak log
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
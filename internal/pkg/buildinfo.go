@@ -0,0 +1,10 @@
+package pkg
+
+// Informasi build, diisi saat link time:
+//
+//	go build -ldflags "-X kasir-api/internal/pkg.Version=1.2.0 -X kasir-api/internal/pkg.Commit=$(git rev-parse --short HEAD) -X kasir-api/internal/pkg.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/api
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)
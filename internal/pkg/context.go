@@ -0,0 +1,53 @@
+package pkg
+
+import (
+	"context"
+	"kasir-api/internal/domain/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+type contextKey string
+
+const (
+	authUserKey  contextKey = "auth_user"
+	requestIDKey contextKey = "request_id"
+	loggerKey    contextKey = "logger"
+)
+
+// WithAuthUser menyimpan user yang sudah terautentikasi ke context
+func WithAuthUser(ctx context.Context, user *models.AuthUser) context.Context {
+	return context.WithValue(ctx, authUserKey, user)
+}
+
+// AuthUserFromContext mengambil user yang sudah terautentikasi dari context
+func AuthUserFromContext(ctx context.Context) (*models.AuthUser, bool) {
+	user, ok := ctx.Value(authUserKey).(*models.AuthUser)
+	return user, ok
+}
+
+// WithRequestID menyimpan request ID ke context
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestIDFromContext mengambil request ID dari context, string kosong jika tidak ada
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// WithLogger menyimpan logger request-scoped ke context
+func WithLogger(ctx context.Context, entry *logrus.Entry) context.Context {
+	return context.WithValue(ctx, loggerKey, entry)
+}
+
+// LoggerFromContext mengambil logger request-scoped dari context. Jika tidak
+// ada (mis. dipanggil di luar HTTP request), dikembalikan entry dari pkg.Log
+// supaya pemanggil selalu bisa langsung log.
+func LoggerFromContext(ctx context.Context) *logrus.Entry {
+	if entry, ok := ctx.Value(loggerKey).(*logrus.Entry); ok {
+		return entry
+	}
+	return logrus.NewEntry(Log)
+}
@@ -0,0 +1,49 @@
+package pkg
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+// TokenClaims adalah isi JWT yang diterbitkan aplikasi
+type TokenClaims struct {
+	UserID    int    `json:"uid"`
+	Username  string `json:"username"`
+	Role      string `json:"role"`
+	TokenType string `json:"typ"`
+	jwt.RegisteredClaims
+}
+
+// GenerateToken membuat JWT HS256 yang berlaku selama ttl
+func GenerateToken(secret string, claims TokenClaims, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.ExpiresAt = jwt.NewNumericDate(now.Add(ttl))
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(secret))
+}
+
+// ParseToken memvalidasi signature, masa berlaku dan tipe token
+func ParseToken(secret, tokenString, tokenType string) (*TokenClaims, error) {
+	claims := &TokenClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.TokenType != tokenType {
+		return nil, errors.New("invalid token type")
+	}
+
+	return claims, nil
+}
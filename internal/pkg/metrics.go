@@ -0,0 +1,139 @@
+package pkg
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const metricsNamespace = "kasir"
+
+// Metrics HTTP, diisi oleh middleware.Metrics
+var (
+	HTTPRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "http_requests_total",
+		Help:      "Total HTTP requests by method, route pattern and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route pattern and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestsInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "http_requests_in_flight",
+		Help:      "Number of HTTP requests currently being served.",
+	})
+)
+
+// Metrics bisnis, diisi oleh use case setelah operasi berhasil
+var (
+	ProductsCreatedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "products_created_total",
+		Help:      "Total products created.",
+	})
+
+	CheckoutsCompletedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "checkouts_completed_total",
+		Help:      "Total successful checkouts.",
+	})
+
+	CheckoutsFailedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "checkouts_failed_total",
+		Help:      "Total failed checkouts by reason.",
+	}, []string{"reason"})
+
+	CheckoutRevenueTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "checkout_revenue_total",
+		Help:      "Sum of total_amount of successful checkouts.",
+	})
+
+	CheckoutTaxTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "checkout_tax_total",
+		Help:      "Sum of tax_amount of successful checkouts.",
+	})
+
+	CheckoutItemsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "checkout_items_total",
+		Help:      "Total item quantity sold through checkout.",
+	})
+
+	PaymentsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "payments_total",
+		Help:      "Total payments recorded at checkout by method.",
+	}, []string{"method"})
+
+	PaymentAmountTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "payment_amount_total",
+		Help:      "Sum of payment amounts at checkout by method.",
+	}, []string{"method"})
+
+	PromotionDiscountTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "promotion_discount_total",
+		Help:      "Sum of discounts given at checkout by promotion ID.",
+	}, []string{"promotion_id"})
+
+	ShiftsClosedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "shifts_closed_total",
+		Help:      "Total cashier shifts closed.",
+	})
+
+	ShiftCashVariance = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "shift_cash_variance",
+		Help:      "Counted minus expected cash when a shift is closed.",
+		Buckets:   []float64{-100000, -50000, -10000, -1000, 0, 1000, 10000, 50000, 100000},
+	})
+
+	StockMovementsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "stock_movements_total",
+		Help:      "Total manual stock movements by reason.",
+	}, []string{"reason"})
+
+	RefundsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "refunds_total",
+		Help:      "Total refunds by reason.",
+	}, []string{"reason"})
+
+	RefundAmountTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "refund_amount_total",
+		Help:      "Sum of refunded amounts by refund method.",
+	}, []string{"method"})
+
+	OrdersVoidedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "orders_voided_total",
+		Help:      "Total draft or held orders voided.",
+	})
+
+	ReceiptsRenderedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "receipts_rendered_total",
+		Help:      "Total receipts rendered by format.",
+	}, []string{"format"})
+)
+
+// RegisterDBMetrics mengekspos sql.DBStats (open/idle/in-use connection,
+// wait count, dll) dari connection pool sebagai gauge Prometheus
+func RegisterDBMetrics(db *sql.DB) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, "kasir"))
+}
@@ -1,7 +1,10 @@
 package pkg
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"kasir-api/internal/domain/models"
 	"net/http"
 )
 
@@ -10,6 +13,7 @@ type ResponsePayload struct {
 	Status  bool        `json:"status"`
 	Message string      `json:"message"`
 	Data    interface{} `json:"data"`
+	Meta    interface{} `json:"meta,omitempty"`
 }
 
 func ResponseSuccess(w http.ResponseWriter, code int, message string, data interface{}) {
@@ -20,8 +24,19 @@ func ResponseSuccess(w http.ResponseWriter, code int, message string, data inter
 		Data:    data,
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	writeJSON(w, code, response)
+}
+
+func ResponseSuccessWithMeta(w http.ResponseWriter, code int, message string, data interface{}, meta interface{}) {
+	response := ResponsePayload{
+		Code:    code,
+		Status:  true,
+		Message: message,
+		Data:    data,
+		Meta:    meta,
+	}
+
+	writeJSON(w, code, response)
 }
 
 func ResponseError(w http.ResponseWriter, code int, message string, data interface{}) {
@@ -32,6 +47,37 @@ func ResponseError(w http.ResponseWriter, code int, message string, data interfa
 		Data:    data,
 	}
 
+	writeJSON(w, code, response)
+}
+
+// ResponseFromError memetakan error domain ke HTTP status yang sesuai:
+// validation 400, unauthorized 401, not found 404, conflict 409,
+// timeout 504, selain itu 500 tanpa membocorkan detail error internal
+func ResponseFromError(w http.ResponseWriter, err error) {
+	var validationErr *models.ValidationError
+
+	switch {
+	case errors.As(err, &validationErr):
+		ResponseError(w, http.StatusBadRequest, validationErr.Error(), validationErr.Fields)
+	case errors.Is(err, models.ErrValidation):
+		ResponseError(w, http.StatusBadRequest, err.Error(), nil)
+	case errors.Is(err, models.ErrUnauthorized):
+		ResponseError(w, http.StatusUnauthorized, err.Error(), nil)
+	case errors.Is(err, models.ErrForbidden):
+		ResponseError(w, http.StatusForbidden, err.Error(), nil)
+	case errors.Is(err, models.ErrNotFound):
+		ResponseError(w, http.StatusNotFound, err.Error(), nil)
+	case errors.Is(err, models.ErrConflict):
+		ResponseError(w, http.StatusConflict, err.Error(), nil)
+	case errors.Is(err, context.DeadlineExceeded):
+		ResponseError(w, http.StatusGatewayTimeout, "Request timed out", nil)
+	default:
+		ResponseError(w, http.StatusInternalServerError, "Internal server error", nil)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, response ResponsePayload) {
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
 	json.NewEncoder(w).Encode(response)
 }
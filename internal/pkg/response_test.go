@@ -0,0 +1,54 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"kasir-api/internal/domain/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResponseFromError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		code    int
+		message string
+	}{
+		{name: "validation", err: models.NewValidationError("price", "product price cannot be negative"),
+			code: http.StatusBadRequest, message: "product price cannot be negative"},
+		{name: "unauthorized", err: models.NewUnauthorizedError("invalid token"), code: http.StatusUnauthorized, message: "invalid token"},
+		{name: "forbidden", err: models.NewForbiddenError("admin role required"), code: http.StatusForbidden, message: "admin role required"},
+		{name: "not found", err: models.NewNotFoundError("product"), code: http.StatusNotFound},
+		{name: "conflict", err: models.NewConflictError("insufficient stock"), code: http.StatusConflict, message: "insufficient stock"},
+		{name: "wrapped conflict", err: fmt.Errorf("checkout: %w", models.NewConflictError("insufficient stock")),
+			code: http.StatusConflict},
+		{name: "timeout", err: fmt.Errorf("query: %w", context.DeadlineExceeded), code: http.StatusGatewayTimeout, message: "Request timed out"},
+		{name: "internal error is hidden", err: errors.New("pq: connection refused on 10.0.0.5"),
+			code: http.StatusInternalServerError, message: "Internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			ResponseFromError(rec, tt.err)
+
+			if rec.Code != tt.code {
+				t.Errorf("status = %d, want %d", rec.Code, tt.code)
+			}
+			var payload ResponsePayload
+			if err := json.NewDecoder(rec.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if payload.Status || payload.Code != tt.code {
+				t.Errorf("payload status, code = %v, %d, want false, %d", payload.Status, payload.Code, tt.code)
+			}
+			if tt.message != "" && payload.Message != tt.message {
+				t.Errorf("message = %q, want %q", payload.Message, tt.message)
+			}
+		})
+	}
+}
@@ -1,29 +1,138 @@
 package routes
 
 import (
+	"kasir-api/internal/domain/models"
 	"kasir-api/internal/http/handlers"
+	"kasir-api/internal/http/middleware"
 	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 type RouteConfig struct {
-	ProductHandler  *handlers.ProductHandler
-	CategoryHandler *handlers.CategoryHandler
-	HealthHandler   *handlers.HealthHandler
+	ProductHandler       *handlers.ProductHandler
+	CategoryHandler      *handlers.CategoryHandler
+	HealthHandler        *handlers.HealthHandler
+	TransactionHandler   *handlers.TransactionHandler
+	AuthHandler          *handlers.AuthHandler
+	StockHandler         *handlers.StockHandler
+	ShiftHandler         *handlers.ShiftHandler
+	PromotionHandler     *handlers.PromotionHandler
+	RefundHandler        *handlers.RefundHandler
+	OrderHandler         *handlers.OrderHandler
+	ReceiptHandler       *handlers.ReceiptHandler
+	ReportHandler        *handlers.ReportHandler
+	SupplierHandler      *handlers.SupplierHandler
+	PurchaseOrderHandler *handlers.PurchaseOrderHandler
+	StockOpnameHandler   *handlers.StockOpnameHandler
+	JWTSecret            string
 }
 
+var (
+	anyRole   = []string{models.RoleAdmin, models.RoleCashier}
+	adminOnly = []string{models.RoleAdmin}
+
+	// katalog: kasir hanya boleh membaca, admin boleh mengubah
+	catalogCollectionRoles = middleware.MethodRoles{
+		http.MethodGet:  anyRole,
+		http.MethodPost: adminOnly,
+	}
+	catalogItemRoles = middleware.MethodRoles{
+		http.MethodGet:    anyRole,
+		http.MethodPut:    adminOnly,
+		http.MethodDelete: adminOnly,
+	}
+)
+
 func RegisterAll(cfg *RouteConfig) http.Handler {
 	mux := http.NewServeMux()
 
+	auth := middleware.Auth(cfg.JWTSecret)
+	protect := func(rules middleware.MethodRoles, h http.HandlerFunc) http.Handler {
+		return auth(middleware.RequireRoles(rules)(h))
+	}
+
 	// health
 	mux.Handle("/api/health", http.HandlerFunc(cfg.HealthHandler.CheckHealth))
+	mux.Handle("/api/health/live", http.HandlerFunc(cfg.HealthHandler.CheckHealth))
+	mux.Handle("/api/health/ready", http.HandlerFunc(cfg.HealthHandler.CheckReadiness))
+
+	// metrics Prometheus
+	mux.Handle("/metrics", promhttp.Handler())
+
+	// auth
+	mux.Handle("/api/auth/login", http.HandlerFunc(cfg.AuthHandler.HandleLogin))
+	mux.Handle("/api/auth/refresh", http.HandlerFunc(cfg.AuthHandler.HandleRefresh))
+	mux.Handle("/api/users", protect(middleware.MethodRoles{http.MethodPost: adminOnly}, cfg.AuthHandler.HandleUsers))
 
 	// product collection
-	mux.Handle("/api/product", http.HandlerFunc(cfg.ProductHandler.HandleProduct))
-	// product by id
-	mux.Handle("/api/product/", http.HandlerFunc(cfg.ProductHandler.HandleProductByID))
+	mux.Handle("/api/product", protect(catalogCollectionRoles, cfg.ProductHandler.HandleProduct))
+	// product by id, termasuk lookup scanner /api/product/barcode/{code}
+	mux.Handle("/api/product/", protect(catalogItemRoles, cfg.ProductHandler.HandleProductByID))
+	// stock ledger per produk
+	mux.Handle("/api/product/{id}/stock", protect(middleware.MethodRoles{http.MethodPost: adminOnly}, cfg.StockHandler.HandleStock))
+	mux.Handle("/api/product/{id}/stock-history", protect(middleware.MethodRoles{http.MethodGet: anyRole}, cfg.StockHandler.HandleStockHistory))
+	// barcode internal untuk produk tanpa kode pabrik
+	mux.Handle("/api/product/{id}/barcode", protect(middleware.MethodRoles{http.MethodPost: adminOnly}, cfg.ProductHandler.HandleGenerateBarcode))
+	// opsi dan matriks varian produk
+	mux.Handle("/api/product/{id}/variants", protect(middleware.MethodRoles{http.MethodPut: adminOnly}, cfg.ProductHandler.HandleProductVariants))
+	mux.Handle("/api/product/{id}/variants/{variant_id}", protect(middleware.MethodRoles{http.MethodPut: adminOnly}, cfg.ProductHandler.HandleProductVariant))
+
+	mux.Handle("/api/category", protect(catalogCollectionRoles, cfg.CategoryHandler.HandleCategory))
+	mux.Handle("/api/category/", protect(catalogItemRoles, cfg.CategoryHandler.HandleCategoryByID))
+
+	// promo: kasir boleh melihat, admin yang mengatur
+	mux.Handle("/api/promotion", protect(catalogCollectionRoles, cfg.PromotionHandler.HandlePromotion))
+	mux.Handle("/api/promotion/{id}", protect(catalogItemRoles, cfg.PromotionHandler.HandlePromotionByID))
+
+	// checkout
+	mux.Handle("/api/checkout", protect(middleware.MethodRoles{http.MethodPost: anyRole}, cfg.TransactionHandler.HandleCheckout))
+	mux.Handle("/api/checkout/preview", protect(middleware.MethodRoles{http.MethodPost: anyRole}, cfg.TransactionHandler.HandlePreviewCheckout))
+	mux.Handle("/api/transaction/{id}", protect(middleware.MethodRoles{http.MethodGet: anyRole}, cfg.TransactionHandler.HandleTransactionByID))
+	mux.Handle("/api/transaction/{id}/receipt", protect(middleware.MethodRoles{http.MethodGet: anyRole}, cfg.ReceiptHandler.HandleReceipt))
+
+	// refund: uang keluar dari laci shift kasir yang memproses
+	mux.Handle("/api/transaction/{id}/refund", protect(middleware.MethodRoles{http.MethodPost: anyRole}, cfg.RefundHandler.HandleRefund))
+	mux.Handle("/api/refund/{id}", protect(middleware.MethodRoles{http.MethodGet: anyRole}, cfg.RefundHandler.HandleRefundByID))
+
+	// shift kasir: kepemilikan shift dicek di use case
+	postAnyRole := middleware.MethodRoles{http.MethodPost: anyRole}
+	getAnyRole := middleware.MethodRoles{http.MethodGet: anyRole}
+	mux.Handle("/api/shift/open", protect(postAnyRole, cfg.ShiftHandler.HandleOpenShift))
+	mux.Handle("/api/shift/current", protect(getAnyRole, cfg.ShiftHandler.HandleCurrentShift))
+	mux.Handle("/api/shift/{id}", protect(getAnyRole, cfg.ShiftHandler.HandleShiftByID))
+	mux.Handle("/api/shift/{id}/cash", protect(postAnyRole, cfg.ShiftHandler.HandleCashEvent))
+	mux.Handle("/api/shift/{id}/close", protect(postAnyRole, cfg.ShiftHandler.HandleCloseShift))
+
+	// order draft/held: kasir boleh void dengan persetujuan supervisor, dicek di use case
+	mux.Handle("/api/orders", protect(middleware.MethodRoles{http.MethodGet: anyRole, http.MethodPost: anyRole}, cfg.OrderHandler.HandleOrders))
+	mux.Handle("/api/orders/{id}", protect(middleware.MethodRoles{http.MethodGet: anyRole, http.MethodPatch: anyRole}, cfg.OrderHandler.HandleOrderByID))
+	mux.Handle("/api/orders/{id}/hold", protect(postAnyRole, cfg.OrderHandler.HandleHoldOrder))
+	mux.Handle("/api/orders/{id}/resume", protect(postAnyRole, cfg.OrderHandler.HandleResumeOrder))
+	mux.Handle("/api/orders/{id}/void", protect(postAnyRole, cfg.OrderHandler.HandleVoidOrder))
+
+	// laporan penjualan dan margin hanya untuk admin
+	mux.Handle("/api/report/sales", protect(middleware.MethodRoles{http.MethodGet: adminOnly}, cfg.ReportHandler.HandleSalesReport))
+
+	// supplier dan purchase order: pembelian barang hanya oleh admin
+	postAdminOnly := middleware.MethodRoles{http.MethodPost: adminOnly}
+	mux.Handle("/api/suppliers", protect(middleware.MethodRoles{http.MethodGet: adminOnly, http.MethodPost: adminOnly}, cfg.SupplierHandler.HandleSuppliers))
+	mux.Handle("/api/suppliers/{id}", protect(middleware.MethodRoles{http.MethodGet: adminOnly, http.MethodPut: adminOnly, http.MethodDelete: adminOnly}, cfg.SupplierHandler.HandleSupplierByID))
+	mux.Handle("/api/suppliers/{id}/purchase-orders", protect(middleware.MethodRoles{http.MethodGet: adminOnly}, cfg.PurchaseOrderHandler.HandleSupplierPurchaseOrders))
+	mux.Handle("/api/purchase-orders", protect(middleware.MethodRoles{http.MethodGet: adminOnly, http.MethodPost: adminOnly}, cfg.PurchaseOrderHandler.HandlePurchaseOrders))
+	mux.Handle("/api/purchase-orders/{id}", protect(middleware.MethodRoles{http.MethodGet: adminOnly, http.MethodPut: adminOnly}, cfg.PurchaseOrderHandler.HandlePurchaseOrderByID))
+	mux.Handle("/api/purchase-orders/{id}/send", protect(postAdminOnly, cfg.PurchaseOrderHandler.HandleSendPurchaseOrder))
+	mux.Handle("/api/purchase-orders/{id}/cancel", protect(postAdminOnly, cfg.PurchaseOrderHandler.HandleCancelPurchaseOrder))
+	mux.Handle("/api/purchase-orders/{id}/receive", protect(postAdminOnly, cfg.PurchaseOrderHandler.HandleReceiveGoods))
 
-	mux.Handle("/api/category", http.HandlerFunc(cfg.CategoryHandler.HandleCategory))
-	mux.Handle("/api/category/", http.HandlerFunc(cfg.CategoryHandler.HandleCategoryByID))
+	// stock opname: semua user boleh menghitung, admin membuka, melihat selisih dan approve
+	mux.Handle("/api/stock-opnames", protect(middleware.MethodRoles{http.MethodGet: adminOnly, http.MethodPost: adminOnly}, cfg.StockOpnameHandler.HandleStockOpnames))
+	mux.Handle("/api/stock-opnames/{id}", protect(getAnyRole, cfg.StockOpnameHandler.HandleStockOpnameByID))
+	mux.Handle("/api/stock-opnames/{id}/counts", protect(postAnyRole, cfg.StockOpnameHandler.HandleCounts))
+	mux.Handle("/api/stock-opnames/{id}/scan", protect(postAnyRole, cfg.StockOpnameHandler.HandleScan))
+	mux.Handle("/api/stock-opnames/{id}/variance", protect(middleware.MethodRoles{http.MethodGet: adminOnly}, cfg.StockOpnameHandler.HandleVariance))
+	mux.Handle("/api/stock-opnames/{id}/approve", protect(postAdminOnly, cfg.StockOpnameHandler.HandleApprove))
+	mux.Handle("/api/stock-opnames/{id}/cancel", protect(postAdminOnly, cfg.StockOpnameHandler.HandleCancel))
 
 	return mux
 }